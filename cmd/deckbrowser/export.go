@@ -0,0 +1,38 @@
+package main
+
+import (
+    "bufio"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// writeDecklist writes one "1 CardName" line per distinct card name, in list order, and
+// returns how many lines it wrote. The file is replaced atomically via a temp file in the
+// same directory, so an existing list is never left half-written.
+func writeDecklist(path string, cards []Card) (int, error) {
+    path = strings.TrimSpace(path)
+    if path == "" { return 0, fmt.Errorf("export path is required") }
+    if len(cards) == 0 { return 0, fmt.Errorf("no cards to export") }
+    dir := filepath.Dir(path)
+    if err := os.MkdirAll(dir, 0o755); err != nil { return 0, err }
+    f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+    if err != nil { return 0, err }
+    defer os.Remove(f.Name()) // no-op once renamed
+    w := bufio.NewWriter(f)
+    seen := map[string]bool{}
+    n := 0
+    for _, c := range cards {
+        key := strings.ToLower(c.Name)
+        if c.Name == "" || seen[key] { continue }
+        seen[key] = true
+        fmt.Fprintf(w, "1 %s\n", c.Name)
+        n++
+    }
+    if err := w.Flush(); err != nil { f.Close(); return 0, err }
+    if err := f.Chmod(0o644); err != nil { f.Close(); return 0, err }
+    if err := f.Close(); err != nil { return 0, err }
+    if err := os.Rename(f.Name(), path); err != nil { return 0, err }
+    return n, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// filter is a parsed mini query such as `c:U cmc<=3 t:instant`, applied client-side to the
+// loaded cards with the same semantics as the web's applyFiltersSort: every listed color must
+// be present, the type matches as a case-insensitive substring, and mana value bounds are inclusive.
+type filter struct {
+    raw    string
+    colors []string
+    typ    string
+    cmcMin int // -1 = unbounded
+    cmcMax int
+}
+
+func noFilter() filter { return filter{cmcMin: -1, cmcMax: -1} }
+
+func (f filter) active() bool { return f.raw != "" }
+
+// parseFilter reads space-separated terms: c:WU (or c:W,U), t:<type>, and cmc/mv with
+// =, <, <=, >, >=. Unknown terms are an error so typos don't silently match everything.
+func parseFilter(s string) (filter, error) {
+    f := noFilter()
+    f.raw = strings.Join(strings.Fields(s), " ")
+    for _, term := range strings.Fields(s) {
+        lower := strings.ToLower(term)
+        switch {
+        case strings.HasPrefix(lower, "c:"):
+            for _, r := range strings.ToUpper(strings.ReplaceAll(term[2:], ",", "")) {
+                if !strings.ContainsRune("WUBRG", r) { return f, fmt.Errorf("unknown color %q in %q (use W, U, B, R, G)", r, term) }
+                f.colors = append(f.colors, string(r))
+            }
+        case strings.HasPrefix(lower, "t:"):
+            f.typ = term[2:]
+        case strings.HasPrefix(lower, "cmc"), strings.HasPrefix(lower, "mv"):
+            if err := f.parseCMC(strings.TrimLeft(lower, "cmv")); err != nil { return f, fmt.Errorf("%q: %w", term, err) }
+        default:
+            return f, fmt.Errorf("unknown filter term %q (try c:U, t:instant, cmc<=3)", term)
+        }
+    }
+    return f, nil
+}
+
+// parseCMC reads the comparison after "cmc"/"mv"; strict bounds become inclusive integer ones.
+func (f *filter) parseCMC(cmp string) error {
+    for _, op := range []string{"<=", ">=", "<", ">", "="} {
+        rest, ok := strings.CutPrefix(cmp, op)
+        if !ok { continue }
+        n, err := strconv.Atoi(rest)
+        if err != nil || n < 0 { return fmt.Errorf("mana value must be a non-negative integer") }
+        switch op {
+        case "<=": f.cmcMax = n
+        case ">=": f.cmcMin = n
+        case "<": f.cmcMax = n - 1
+        case ">": f.cmcMin = n + 1
+        case "=": f.cmcMin, f.cmcMax = n, n
+        }
+        return nil
+    }
+    return fmt.Errorf("expected =, <, <=, > or >= and a number")
+}
+
+func (f filter) match(c Card) bool {
+    if f.typ != "" && !strings.Contains(strings.ToLower(c.TypeLine), strings.ToLower(f.typ)) { return false }
+    if len(f.colors) > 0 && !containsAllColors(c.Colors, f.colors) { return false }
+    if f.cmcMin >= 0 && int(c.CMC) < f.cmcMin { return false }
+    if f.cmcMax >= 0 && int(c.CMC) > f.cmcMax { return false }
+    return true
+}
+
+// apply returns the matching cards in their original order.
+func (f filter) apply(cards []Card) []Card {
+    if !f.active() { return cards }
+    out := make([]Card, 0, len(cards))
+    for _, c := range cards {
+        if f.match(c) { out = append(out, c) }
+    }
+    return out
+}
+
+// containsAllColors mirrors the web UI's predicate: every wanted color code is in have.
+func containsAllColors(have []string, want []string) bool {
+    set := map[string]struct{}{}
+    for _, c := range have { set[strings.ToUpper(strings.TrimSpace(c))] = struct{}{} }
+    for _, c := range want {
+        c = strings.ToUpper(strings.TrimSpace(c))
+        if c == "" { continue }
+        if _, ok := set[c]; !ok { return false }
+    }
+    return true
+}
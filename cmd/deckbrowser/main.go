@@ -1,15 +1,12 @@
 package main
 
 import (
-    "bytes"
     "context"
     "encoding/json"
-    "errors"
     "fmt"
-    "io"
-    "net/http"
     "os"
     "path/filepath"
+    "strconv"
     "strings"
     "time"
 
@@ -42,68 +39,42 @@ type Card struct {
     ManaCost   string
     OracleText string
     Colors     []string
+    CMC        float64
     Image      string
     Distance   float64
     Similarity float64
 }
 
-type gqlResp struct { Data json.RawMessage `json:"data"`; Errors []struct{ Message string `json:"message"` } `json:"errors"` }
-
-func gqlDo(ctx context.Context, baseURL, query string) (json.RawMessage, error) {
-    endpoint := strings.TrimRight(baseURL, "/") + "/v1/graphql"
-    body := map[string]string{"query": query}
-    b, _ := json.Marshal(body)
-    req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(b))
-    if err != nil { return nil, err }
-    req.Header.Set("Content-Type", "application/json")
-    hc := &http.Client{ Timeout: 15 * time.Second }
-    resp, err := hc.Do(req)
-    if err != nil { return nil, err }
-    defer resp.Body.Close()
-    if resp.StatusCode != http.StatusOK {
-        data, _ := io.ReadAll(resp.Body)
-        return nil, fmt.Errorf("graphql status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
-    }
-    var wr gqlResp
-    if err := json.NewDecoder(resp.Body).Decode(&wr); err != nil { return nil, err }
-    if len(wr.Errors) > 0 { return nil, errors.New(wr.Errors[0].Message) }
-    return wr.Data, nil
-}
-
-func listCards(ctx context.Context, baseURL string, offset, limit int) ([]Card, error) {
-    cli := wv.NewClient(baseURL)
+func listCards(ctx context.Context, cli *wv.Client, offset, limit int) ([]Card, error) {
     res, err := cli.ListCards(ctx, offset, limit)
     if err != nil { return nil, err }
     out := make([]Card, 0, len(res))
     for _, c := range res {
-        out = append(out, Card{ ID:c.ID, Name:c.Name, TypeLine:c.TypeLine, ManaCost:c.ManaCost, OracleText:c.OracleText, Image:c.ImageNormal })
+        out = append(out, Card{ ID:c.ID, Name:c.Name, TypeLine:c.TypeLine, ManaCost:c.ManaCost, OracleText:c.OracleText, Colors:c.Colors, CMC:c.CMC, Image:c.ImageNormal })
     }
     return out, nil
 }
 
-func findByNameLike(ctx context.Context, baseURL, name string, limit int) ([]Card, error) {
-    cli := wv.NewClient(baseURL)
+func findByNameLike(ctx context.Context, cli *wv.Client, name string, limit int) ([]Card, error) {
     res, err := cli.FindByNameLike(ctx, name, limit)
     if err != nil { return nil, err }
     out := make([]Card, 0, len(res))
     for _, c := range res {
-        out = append(out, Card{ ID:c.ID, Name:c.Name, TypeLine:c.TypeLine, ManaCost:c.ManaCost, OracleText:c.OracleText, Image:c.ImageNormal })
+        out = append(out, Card{ ID:c.ID, Name:c.Name, TypeLine:c.TypeLine, ManaCost:c.ManaCost, OracleText:c.OracleText, Colors:c.Colors, CMC:c.CMC, Image:c.ImageNormal })
     }
     return out, nil
 }
 
-func fetchVectorForName(ctx context.Context, baseURL, name string) ([]float64, string, error) {
-    cli := wv.NewClient(baseURL)
+func fetchVectorForName(ctx context.Context, cli *wv.Client, name string) ([]float64, string, error) {
     return cli.FetchVectorForName(ctx, name)
 }
 
-func searchSimilar(ctx context.Context, baseURL string, vector []float64, k int) ([]Card, error) {
-    cli := wv.NewClient(baseURL)
+func searchSimilar(ctx context.Context, cli *wv.Client, vector []float64, k int) ([]Card, error) {
     res, err := cli.SearchNearVector(ctx, vector, k)
     if err != nil { return nil, err }
     out := make([]Card, 0, len(res))
     for _, c := range res {
-        out = append(out, Card{ ID:c.ID, Name:c.Name, TypeLine:c.TypeLine, ManaCost:c.ManaCost, OracleText:c.OracleText, Image:c.ImageNormal, Distance:c.Distance, Similarity:c.Similarity })
+        out = append(out, Card{ ID:c.ID, Name:c.Name, TypeLine:c.TypeLine, ManaCost:c.ManaCost, OracleText:c.OracleText, Colors:c.Colors, CMC:c.CMC, Image:c.ImageNormal, Distance:c.Distance, Similarity:c.Similarity })
     }
     return out, nil
 }
@@ -118,33 +89,132 @@ const (
     details
     config
     loading
+    filtering
+    exporting
 )
 
 type model struct {
     cfg     cfg
     cfgPath string
+    cli     *wv.Client // one per process (rebuilt when the URL changes) so connections are reused
     mode    mode
     spinner spinner.Model
     input   textinput.Model
     status  string
     errMsg  string
-    cards   []Card
+    cards   []Card // loaded cards after the filter
+    all     []Card // loaded cards before the filter
+    filter  filter
+    filterIn textinput.Model
+    exportIn textinput.Model
+    back    mode // list mode to return to from filtering or exporting
+    // search-as-you-type: gen counts input changes so answers to older input are dropped;
+    // cancelSearch stops the name search in flight, liveFor is the input its matches are for
+    gen          int
+    cancelSearch context.CancelFunc
+    liveFor      string
     selected int
     offset  int
+    // config form: URL, K, Limit
+    fields  []*textinput.Model
+    cursor  int
+    // Weaviate connectivity: search and browse stay closed until a ping succeeds or the user
+    // overrides with o
+    pinging  bool
+    pingErr  error
+    online   bool
+    override bool
 }
 
 func newModel(cfgPath string) model {
     c := loadCfg(cfgPath)
     sp := spinner.New(); sp.Spinner = spinner.Dot
     ti := textinput.New(); ti.Placeholder = "Enter card name"; ti.Prompt = "> "
-    return model{ cfg:c, cfgPath: cfgPath, mode: menu, spinner: sp, input: ti, status: "" }
+    mk := func(prompt, placeholder string) *textinput.Model {
+        f := textinput.New(); f.Prompt = prompt; f.Placeholder = placeholder
+        return &f
+    }
+    fields := []*textinput.Model{mk("Weaviate URL: ", "http://localhost:8080"), mk("K (similar results): ", "10"), mk("Limit (page size): ", "20")}
+    fi := textinput.New(); fi.Placeholder = "c:U cmc<=3 t:instant"; fi.Prompt = "/ "
+    ei := textinput.New(); ei.SetValue("decklist.txt"); ei.Prompt = "Export to: "
+    return model{ cfg:c, cfgPath: cfgPath, cli: wv.NewClient(c.WeaviateURL), mode: menu, spinner: sp, input: ti, status: "", fields: fields, filter: noFilter(), filterIn: fi, exportIn: ei, pinging: true }
+}
+
+// openConfig fills the config form from the current settings and focuses the first field.
+func (m model) openConfig() model {
+    m.fields[0].SetValue(m.cfg.WeaviateURL)
+    m.fields[1].SetValue(strconv.Itoa(m.cfg.K))
+    m.fields[2].SetValue(strconv.Itoa(m.cfg.Limit))
+    m.mode, m.errMsg = config, ""
+    return m.focusField(0)
+}
+
+func (m model) focusField(i int) model {
+    m.cursor = i
+    for j, f := range m.fields {
+        if j == i { f.Focus() } else { f.Blur() }
+    }
+    return m
+}
+
+// saveConfig validates the form; on success it applies and persists the settings.
+func (m model) saveConfig() (model, error) {
+    url := strings.TrimSpace(m.fields[0].Value())
+    if url == "" { return m, fmt.Errorf("Weaviate URL is required") }
+    k, err := strconv.Atoi(strings.TrimSpace(m.fields[1].Value()))
+    if err != nil || k <= 0 { return m, fmt.Errorf("K must be a positive integer") }
+    limit, err := strconv.Atoi(strings.TrimSpace(m.fields[2].Value()))
+    if err != nil || limit <= 0 { return m, fmt.Errorf("Limit must be a positive integer") }
+    if url != m.cfg.WeaviateURL { m.cli = wv.NewClient(url) }
+    m.cfg = cfg{WeaviateURL: url, K: k, Limit: limit}
+    saveCfg(m.cfgPath, m.cfg)
+    return m, nil
 }
 
-func (m model) Init() tea.Cmd { return nil }
+func (m model) Init() tea.Cmd { return tea.Batch(m.spinner.Tick, m.ping()) }
 
-type done struct{ fn string; cards []Card; err error }
+// pinged reports whether Weaviate answered at url.
+type pinged struct{ url string; err error }
+
+// ping checks the configured Weaviate with a short timeout.
+func (m model) ping() tea.Cmd {
+    cli, url := m.cli, m.cfg.WeaviateURL
+    return func() tea.Msg {
+        ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second); defer cancel()
+        return pinged{ url: url, err: cli.Ping(ctx) }
+    }
+}
+
+// connected reports whether search and browse may run: Weaviate answered, or the user chose
+// to go on without it.
+func (m model) connected() bool { return m.online || m.override }
+
+// searchDebounce is how long typing must pause before the search runs.
+const searchDebounce = 300 * time.Millisecond
+
+// done carries a query's cards; gen is the input generation of a name search.
+type done struct{ fn string; gen int; cards []Card; err error }
 type setStatus string
 
+// searchIdle fires searchDebounce after the input change numbered gen.
+type searchIdle struct{ gen int }
+
+// stopSearch cancels the name search in flight and invalidates pending timers and answers.
+func (m model) stopSearch() model {
+    if m.cancelSearch != nil { m.cancelSearch(); m.cancelSearch = nil }
+    m.gen++
+    return m
+}
+
+// startSearch runs a name search for the current generation; fn is "live" while typing and
+// "search" on Enter.
+func (m model) startSearch(name, fn string) (model, tea.Cmd) {
+    if m.cancelSearch != nil { m.cancelSearch() }
+    var cmd tea.Cmd
+    cmd, m.cancelSearch = m.doSearch(name, m.gen, fn)
+    return m, cmd
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
     switch msg := msg.(type) {
     case spinner.TickMsg:
@@ -156,25 +226,64 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
         case menu:
             switch msg.String() {
             case "q", "ctrl+c": return m, tea.Quit
-            case "1": m.mode = search; m.input.Focus(); return m, nil
-            case "2": m.mode = browse; return m, m.loadPage(0)
-            case "3": m.mode = config; return m, nil
+            case "1", "2":
+                if !m.connected() { m.errMsg = "Weaviate is not reachable yet: r retries, o continues anyway"; return m, nil }
+                m.errMsg = ""
+                if msg.String() == "1" {
+                    m = m.stopSearch()
+                    m.mode = search; m.input.Focus(); m.all, m.cards, m.liveFor, m.status = nil, nil, "", ""
+                    if name := strings.TrimSpace(m.input.Value()); name != "" { return m.startSearch(name, "live") }
+                    return m, nil
+                }
+                m.mode = browse; return m, m.loadPage(0)
+            case "3": return m.openConfig(), nil
+            case "r":
+                if m.pinging { return m, nil }
+                m.pinging, m.errMsg = true, ""; return m, tea.Batch(m.spinner.Tick, m.ping())
+            case "o": if m.pingErr != nil { m.override, m.errMsg = true, "" }; return m, nil
             }
         case search:
             switch msg.String() {
-            case "esc": m.mode = menu; return m, nil
+            case "esc": m = m.stopSearch(); m.mode = menu; return m, nil
             case "enter":
                 name := strings.TrimSpace(m.input.Value()); if name == "" { return m, nil }
+                if name == m.liveFor && m.cancelSearch == nil && m.errMsg == "" {
+                    // the live matches are current
+                    m.mode = results; m.selected = 0; m.input.Blur()
+                    return m, nil
+                }
+                m = m.stopSearch()
                 m.status = "Searching..."; m.errMsg = ""; m.cards = nil; m.selected = 0
-                return m, tea.Batch(m.spinner.Tick, m.doSearch(name))
+                var cmd tea.Cmd
+                m, cmd = m.startSearch(name, "search")
+                return m, tea.Batch(m.spinner.Tick, cmd)
             default:
+                before := m.input.Value()
                 var cmd tea.Cmd
                 m.input, cmd = m.input.Update(msg)
-                return m, cmd
+                if m.input.Value() == before { return m, cmd }
+                m = m.stopSearch()
+                if strings.TrimSpace(m.input.Value()) == "" {
+                    m.all, m.cards, m.liveFor, m.status = nil, nil, "", ""
+                    return m, cmd
+                }
+                gen := m.gen
+                return m, tea.Batch(cmd, tea.Tick(searchDebounce, func(time.Time) tea.Msg { return searchIdle{gen} }))
             }
         case browse, results:
             switch msg.String() {
-            case "esc": m.mode = menu; return m, nil
+            case "esc":
+                if m.filter.active() { return m.setFilter(noFilter()), nil }
+                m.mode = menu; return m, nil
+            case "/":
+                m.back, m.mode = m.mode, filtering
+                m.filterIn.SetValue(m.filter.raw); m.filterIn.CursorEnd(); m.filterIn.Focus()
+                return m, nil
+            case "e":
+                if len(m.cards) == 0 { return m, nil }
+                m.back, m.mode, m.errMsg = m.mode, exporting, ""
+                m.exportIn.CursorEnd(); m.exportIn.Focus()
+                return m, nil
             case "up", "k": if m.selected > 0 { m.selected-- }; return m, nil
             case "down", "j": if m.selected < len(m.cards)-1 { m.selected++ }; return m, nil
             case "n": if m.mode == browse { m.offset += m.cfg.Limit; return m, m.loadPage(m.offset) }
@@ -185,36 +294,101 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                 // Run similar search from selected
                 m.mode = loading; m.status = "Searching similar..."; return m, tea.Batch(m.spinner.Tick, m.doSimilar(sel.Name))
             }
+        case filtering:
+            switch msg.String() {
+            case "esc": m.mode = m.back; m.errMsg = ""; m.filterIn.Blur(); return m, nil
+            case "enter":
+                f, err := parseFilter(m.filterIn.Value())
+                if err != nil { m.errMsg = err.Error(); return m, nil }
+                m.errMsg = ""; m.mode = m.back; m.filterIn.Blur()
+                return m.setFilter(f), nil
+            default:
+                var cmd tea.Cmd
+                m.filterIn, cmd = m.filterIn.Update(msg)
+                return m, cmd
+            }
+        case exporting:
+            switch msg.String() {
+            case "esc": m.mode = m.back; m.errMsg = ""; m.exportIn.Blur(); return m, nil
+            case "enter":
+                path := m.exportIn.Value()
+                n, err := writeDecklist(path, m.cards)
+                if err != nil { m.errMsg = "export: " + err.Error(); return m, nil }
+                m.errMsg = ""; m.mode = m.back; m.exportIn.Blur()
+                m.status = fmt.Sprintf("Exported %d card(s) to %s", n, strings.TrimSpace(path))
+                return m, nil
+            default:
+                var cmd tea.Cmd
+                m.exportIn, cmd = m.exportIn.Update(msg)
+                return m, cmd
+            }
         case config:
             switch msg.String() {
             case "esc": m.mode = menu; return m, nil
+            case "tab", "down": return m.focusField((m.cursor + 1) % len(m.fields)), nil
+            case "shift+tab", "up": return m.focusField((m.cursor - 1 + len(m.fields)) % len(m.fields)), nil
             case "enter":
-                // toggle K and Limit or save URL – simple cycle for brevity
-                if strings.HasPrefix(m.input.Value(), "http") { m.cfg.WeaviateURL = m.input.Value() } else { m.cfg.WeaviateURL = m.input.Value() }
-                saveCfg(m.cfgPath, m.cfg); m.mode = menu; return m, nil
+                saved, err := m.saveConfig()
+                if err != nil { m.errMsg = err.Error(); return m, nil }
+                saved.errMsg = ""; saved.mode = menu
+                if saved.cfg.WeaviateURL == m.cfg.WeaviateURL { return saved, nil }
+                saved.pinging, saved.online, saved.override, saved.pingErr = true, false, false, nil
+                return saved, tea.Batch(saved.spinner.Tick, saved.ping())
             default:
                 var cmd tea.Cmd
-                m.input, cmd = m.input.Update(msg)
+                *m.fields[m.cursor], cmd = m.fields[m.cursor].Update(msg)
                 return m, cmd
             }
         }
+    case searchIdle:
+        if msg.gen != m.gen || m.mode != search { return m, nil } // typed on, or left search
+        name := strings.TrimSpace(m.input.Value())
+        m.status = "Searching..."
+        return m.startSearch(name, "live")
     case done:
+        if msg.fn == "search" || msg.fn == "live" {
+            if msg.gen != m.gen { return m, nil } // answer to older input
+            m.cancelSearch, m.errMsg = nil, ""
+        }
         if msg.err != nil { m.errMsg = msg.err.Error() }
         switch msg.fn {
+        case "live":
+            if m.mode != search { return m, nil }
+            m.liveFor = strings.TrimSpace(m.input.Value()); m.selected = 0
+            m.status = fmt.Sprintf("Found %d match(es) (Enter lists them)", len(msg.cards))
         case "search":
-            m.cards = msg.cards; m.mode = results; m.status = fmt.Sprintf("Found %d match(es)", len(m.cards))
+            m.mode = results; m.input.Blur(); m.status = fmt.Sprintf("Found %d match(es)", len(msg.cards))
         case "similar":
-            m.cards = msg.cards; m.mode = results; m.status = fmt.Sprintf("Top %d similar", len(m.cards))
+            m.mode = results; m.status = fmt.Sprintf("Top %d similar", len(msg.cards))
         case "page":
-            m.cards = msg.cards; m.mode = browse; m.status = fmt.Sprintf("Page offset %d", m.offset)
+            m.mode = browse; m.status = fmt.Sprintf("Page offset %d", m.offset)
         }
-        return m, nil
+        m.all = msg.cards
+        return m.setFilter(m.filter), nil
     case setStatus:
         m.status = string(msg); return m, nil
+    case pinged:
+        if msg.url != m.cfg.WeaviateURL { return m, nil } // answer for a URL changed since
+        m.pinging, m.pingErr, m.online = false, msg.err, msg.err == nil
+        return m, nil
     }
     return m, nil
 }
 
+// setFilter applies f to the loaded cards, keeping the selection in range.
+func (m model) setFilter(f filter) model {
+    m.filter = f
+    m.cards = f.apply(m.all)
+    m.selected = min(m.selected, max(len(m.cards)-1, 0))
+    return m
+}
+
+// filterLine is the status line for an active filter, e.g. `Filter: c:U cmc<=3 (4/20) — Esc clears`.
+func (m model) filterLine() string {
+    if !m.filter.active() { return "" }
+    return fmt.Sprintf("Filter: %s (%d/%d) — Esc clears", m.filter.raw, len(m.cards), len(m.all))
+}
+
 func (m model) View() string {
     sb := &strings.Builder{}
     title := lipgloss.NewStyle().Bold(true).Render("DeckTech DB Browser")
@@ -223,13 +397,24 @@ func (m model) View() string {
     case menu:
         fmt.Fprintln(sb, "1) Search by name\n2) Browse list\n3) Config\nq) Quit")
         fmt.Fprintf(sb, "DB: %s | K=%d | Limit=%d\n", m.cfg.WeaviateURL, m.cfg.K, m.cfg.Limit)
+        switch {
+        case m.pinging:
+            fmt.Fprintln(sb, m.spinner.View(), "Checking Weaviate...")
+        case m.pingErr != nil && !m.override:
+            fmt.Fprintln(sb, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true).Render(fmt.Sprintf("Weaviate unreachable at %s: %v", m.cfg.WeaviateURL, m.pingErr)))
+            fmt.Fprintln(sb, "r) Retry  o) Continue anyway  3) Change URL")
+        case m.pingErr != nil:
+            fmt.Fprintln(sb, "Weaviate unreachable; continuing anyway (r retries)")
+        }
+        if m.errMsg != "" { fmt.Fprintln(sb, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(m.errMsg)) }
     case search:
-        fmt.Fprintln(sb, "Search by card name (Enter submits, Esc cancels)")
+        fmt.Fprintln(sb, "Search by card name (matches update as you type; Enter lists them, Esc cancels)")
         fmt.Fprintln(sb, m.input.View())
+        for _, c := range m.cards { fmt.Fprintf(sb, "  %s — %s\n", c.Name, c.TypeLine) }
         if m.status != "" { fmt.Fprintln(sb, m.status) }
         if m.errMsg != "" { fmt.Fprintln(sb, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(m.errMsg)) }
     case browse:
-        fmt.Fprintf(sb, "Browse (offset %d). n/p to page, Enter=Similar, Esc=Back\n", m.offset)
+        fmt.Fprintf(sb, "Browse (offset %d). n/p to page, Enter=Similar, /=Filter, e=Export, Esc=Back\n", m.offset)
         for i, c := range m.cards {
             cur := "  "; if i == m.selected { cur = "> " }
             line := fmt.Sprintf("%s%s — %s", cur, c.Name, c.TypeLine)
@@ -237,9 +422,10 @@ func (m model) View() string {
             fmt.Fprintln(sb, line)
         }
         if m.status != "" { fmt.Fprintln(sb, m.status) }
+        if f := m.filterLine(); f != "" { fmt.Fprintln(sb, f) }
         if m.errMsg != "" { fmt.Fprintln(sb, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(m.errMsg)) }
     case results:
-        fmt.Fprintln(sb, "Results (Enter=Similar from selected, Esc=Back)")
+        fmt.Fprintln(sb, "Results (Enter=Similar from selected, /=Filter, e=Export, Esc=Back)")
         for i, c := range m.cards {
             cur := "  "; if i == m.selected { cur = "> " }
             sim := ""; if c.Similarity > 0 { sim = fmt.Sprintf(" (sim %.3f)", c.Similarity) }
@@ -248,34 +434,45 @@ func (m model) View() string {
             fmt.Fprintln(sb, line)
         }
         if m.status != "" { fmt.Fprintln(sb, m.status) }
+        if f := m.filterLine(); f != "" { fmt.Fprintln(sb, f) }
+        if m.errMsg != "" { fmt.Fprintln(sb, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(m.errMsg)) }
+    case filtering:
+        fmt.Fprintln(sb, "Filter loaded cards: c:WU (all colors), t:<type>, cmc<=3 / mv>2 / cmc=1 (Enter applies, empty clears, Esc cancels)")
+        fmt.Fprintln(sb, m.filterIn.View())
+        if m.errMsg != "" { fmt.Fprintln(sb, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(m.errMsg)) }
+    case exporting:
+        fmt.Fprintf(sb, "Write the %d listed card(s) as a decklist, one \"1 Name\" line per distinct name (Enter writes, Esc cancels)\n", len(m.cards))
+        fmt.Fprintln(sb, m.exportIn.View())
         if m.errMsg != "" { fmt.Fprintln(sb, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(m.errMsg)) }
     case loading:
         fmt.Fprintln(sb, m.spinner.View(), "Loading...")
         if m.status != "" { fmt.Fprintln(sb, m.status) }
     case config:
-        fmt.Fprintln(sb, "Set Weaviate URL, then Enter to save. Esc cancels.")
-        if m.input.Value() == "" { m.input.SetValue(m.cfg.WeaviateURL) }
-        fmt.Fprintln(sb, m.input.View())
+        fmt.Fprintln(sb, "Edit config (Tab/↑↓ move, Enter saves, Esc cancels)")
+        for _, f := range m.fields { fmt.Fprintln(sb, f.View()) }
+        if m.errMsg != "" { fmt.Fprintln(sb, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(m.errMsg)) }
     }
     return sb.String()
 }
 
-func (m model) doSearch(name string) tea.Cmd {
+// doSearch returns the name search command and the cancel that abandons it.
+func (m model) doSearch(name string, gen int, fn string) (tea.Cmd, context.CancelFunc) {
+    ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+    cli, limit := m.cli, m.cfg.Limit
     return func() tea.Msg {
-        ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second); defer cancel()
-        // first try exact vector; if not, LIKE finds candidates
+        defer cancel()
         // For search list, we show LIKE matches; selecting one triggers similar search.
-        matches, err := findByNameLike(ctx, m.cfg.WeaviateURL, name, m.cfg.Limit)
-        return done{ fn:"search", cards: matches, err: err }
-    }
+        matches, err := findByNameLike(ctx, cli, name, limit)
+        return done{ fn: fn, gen: gen, cards: matches, err: err }
+    }, cancel
 }
 
 func (m model) doSimilar(name string) tea.Cmd {
     return func() tea.Msg {
         ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second); defer cancel()
-        vec, _, err := fetchVectorForName(ctx, m.cfg.WeaviateURL, name)
+        vec, _, err := fetchVectorForName(ctx, m.cli, name)
         if err != nil { return done{ fn:"similar", err: err } }
-        res, err := searchSimilar(ctx, m.cfg.WeaviateURL, vec, m.cfg.K)
+        res, err := searchSimilar(ctx, m.cli, vec, m.cfg.K)
         return done{ fn:"similar", cards: res, err: err }
     }
 }
@@ -283,7 +480,7 @@ func (m model) doSimilar(name string) tea.Cmd {
 func (m model) loadPage(offset int) tea.Cmd {
     return func() tea.Msg {
         ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second); defer cancel()
-        res, err := listCards(ctx, m.cfg.WeaviateURL, offset, m.cfg.Limit)
+        res, err := listCards(ctx, m.cli, offset, m.cfg.Limit)
         return done{ fn:"page", cards: res, err: err }
     }
 }
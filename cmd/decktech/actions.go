@@ -0,0 +1,94 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "os/exec"
+    "sort"
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// customAction is a user-defined menu entry from the config file's "actions" list, e.g.
+// {"title": "Backup DB", "desc": "Snapshot Weaviate", "command": ["./scripts/backup.sh", "data/backup"], "env": {"KEEP": "3"}}.
+// Command is an argv run via runProcess, so .sh and .py paths go through bash and python3.
+type customAction struct {
+    Title   string            `json:"title"`
+    Desc    string            `json:"desc,omitempty"`
+    Command []string          `json:"command"`
+    Env     map[string]string `json:"env,omitempty"`
+}
+
+// validateActions returns the actions that can be offered and a problem for each one that
+// can't: no title, a title taken by a built-in action or an earlier custom one, or a command
+// that isn't there. Scripts must exist; other commands must resolve on PATH.
+func validateActions(actions []customAction) (valid []customAction, problems []string) {
+    taken := map[string]bool{}
+    for _, it := range menuItems { taken[strings.ToLower(it.title)] = true }
+    for i, a := range actions {
+        title := strings.TrimSpace(a.Title)
+        key := strings.ToLower(title)
+        switch {
+        case title == "":
+            problems = append(problems, fmt.Sprintf("action %d: title required", i+1))
+        case taken[key]:
+            problems = append(problems, fmt.Sprintf("action %q: title is already used by another menu item", title))
+        case len(a.Command) == 0 || strings.TrimSpace(a.Command[0]) == "":
+            problems = append(problems, fmt.Sprintf("action %q: command required", title))
+        default:
+            if err := commandExists(a.Command[0]); err != nil {
+                problems = append(problems, fmt.Sprintf("action %q: %v", title, err))
+                continue
+            }
+            a.Title = title
+            taken[key] = true
+            valid = append(valid, a)
+        }
+    }
+    return valid, problems
+}
+
+// commandExists checks the first argv element the way runProcess will run it.
+func commandExists(cmd string) error {
+    if strings.HasSuffix(cmd, ".sh") || strings.HasSuffix(cmd, ".py") {
+        _, err := os.Stat(cmd)
+        return err
+    }
+    _, err := exec.LookPath(cmd)
+    return err
+}
+
+// buildMenu lists the built-in actions with the custom ones inserted before Quit. Built-in
+// entries keep their positions, which startAction switches on.
+func buildMenu(actions []customAction) []menuItem {
+    n := len(menuItems) - 1 // Quit
+    menu := append([]menuItem{}, menuItems[:n]...)
+    for _, a := range actions { menu = append(menu, menuItem{a.Title, a.Desc}) }
+    return append(menu, menuItems[n])
+}
+
+// customIndex maps menu position sel to an index into m.actions, or -1 for a built-in entry.
+func (m model) customIndex(sel int) int {
+    i := sel - (len(menuItems) - 1)
+    if i < 0 || i >= len(m.actions) { return -1 }
+    return i
+}
+
+// runCustom runs a custom action's command with its env added to the environment, in sorted
+// key order.
+func runCustom(a customAction) tea.Cmd {
+    return func() tea.Msg {
+        keys := make([]string, 0, len(a.Env))
+        for k := range a.Env { keys = append(keys, k) }
+        sort.Strings(keys)
+        env := make([]string, 0, len(keys))
+        for _, k := range keys { env = append(env, k+"="+a.Env[k]) }
+        msg := runProcess(a.Command, env)
+        if dm, ok := msg.(doneMsg); ok && dm.err != nil {
+            dm.err = fmt.Errorf("%s: %w", a.Title, dm.err)
+            return dm
+        }
+        return doneMsg{note: a.Title + " finished"}
+    }
+}
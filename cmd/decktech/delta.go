@@ -0,0 +1,94 @@
+package main
+
+import (
+    "bufio"
+    "context"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "time"
+
+    tea "github.com/charmbracelet/bubbletea"
+    "github.com/domano/decktech/pkg/scryfall"
+    wv "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// deltaStats counts what a bulk file adds relative to the ingested collection.
+type deltaStats struct {
+    Scanned, New, Changed int
+}
+
+// writeDelta streams the (optionally gzipped) Scryfall bulk array at bulkPath and writes the cards that are missing
+// from existing, or whose oracle text hash differs, to outPath as a JSON array of the original objects.
+// Cards are decoded one at a time so the bulk file is never held in memory.
+func writeDelta(bulkPath, outPath string, existing map[string]uint64) (deltaStats, error) {
+    var st deltaStats
+    in, err := scryfall.OpenBulk(bulkPath)
+    if err != nil { return st, err }
+    defer in.Close()
+    out, err := os.Create(outPath)
+    if err != nil { return st, err }
+    defer out.Close()
+    w := bufio.NewWriter(out)
+
+    dec := json.NewDecoder(in)
+    if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+        return st, fmt.Errorf("%s: expected a JSON array of cards", bulkPath)
+    }
+    _, _ = w.WriteString("[")
+    for dec.More() {
+        var raw json.RawMessage
+        if err := dec.Decode(&raw); err != nil { return st, fmt.Errorf("%s: card %d: %w", bulkPath, st.Scanned, err) }
+        st.Scanned++
+        var c scryCard
+        if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" { continue }
+        h, ok := existing[c.ID]
+        switch {
+        case !ok:
+            st.New++
+        case h != wv.OracleHash(c.oracle()):
+            st.Changed++
+        default:
+            continue
+        }
+        if st.New+st.Changed > 1 { _, _ = w.WriteString(",\n") }
+        _, _ = w.Write(raw)
+    }
+    _, _ = w.WriteString("]\n")
+    if err := w.Flush(); err != nil { return st, err }
+    return st, out.Close()
+}
+
+// runDelta diffs the bulk file against the ingested cards and embeds/ingests only new or
+// changed ones. Changed cards keep their ID, so the batch import overwrites the existing object.
+// Delta batches and their checkpoint live under <outdir>/delta so the full-run checkpoint is untouched.
+func (m model) runDelta() tea.Cmd {
+    return func() tea.Msg {
+        ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+        defer cancel()
+        existing, err := wv.NewClient(m.cfg.WeaviateURL).ExistingOracleHashes(ctx)
+        if err != nil { return doneMsg{err: fmt.Errorf("list ingested cards: %w", err)} }
+
+        dir := filepath.Join(m.cfg.OutDir, "delta")
+        if err := os.MkdirAll(dir, 0o755); err != nil { return doneMsg{err: err} }
+        deltaJSON := filepath.Join(dir, "delta-cards.json")
+        st, err := writeDelta(m.cfg.ScryfallJSON, deltaJSON, existing)
+        if err != nil { return doneMsg{err: err} }
+        note := fmt.Sprintf("Delta: scanned %d, ingested %d, new %d, changed %d", st.Scanned, len(existing), st.New, st.Changed)
+        if st.New+st.Changed == 0 {
+            return doneMsg{note: note + " — already up to date"}
+        }
+
+        checkpoint := filepath.Join(dir, "delta_progress.json")
+        _ = os.Remove(checkpoint) // each delta starts from the top of its own file
+        env := []string{"MODEL=" + m.cfg.Model, "WEAVIATE_URL=" + m.cfg.WeaviateURL, "OUTDIR=" + dir, "CHECKPOINT=" + checkpoint, "EMBED_QUIET=1", fmt.Sprintf("EMBED_TAGS_WEIGHT=%d", m.cfg.TagsWeight)}
+        if m.cfg.IncludeName { env = append(env, "INCLUDE_NAME=1") }
+        msg := runProcess([]string{"./scripts/embed_batches.sh", deltaJSON, fmt.Sprintf("%d", m.cfg.BatchSize)}, env)
+        if dm, ok := msg.(doneMsg); ok {
+            dm.note = note
+            return dm
+        }
+        return msg
+    }
+}
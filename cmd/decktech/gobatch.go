@@ -0,0 +1,288 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strings"
+    "time"
+
+    tea "github.com/charmbracelet/bubbletea"
+    "github.com/domano/decktech/pkg/embed"
+    prg "github.com/domano/decktech/pkg/progress"
+    "github.com/domano/decktech/pkg/scryfall"
+    wv "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// scryCard is the part of a Scryfall bulk card that embedding, ingest and delta diffs use.
+type scryCard struct {
+    ID              string            `json:"id"`
+    OracleID        string            `json:"oracle_id"`
+    Name            string            `json:"name"`
+    ManaCost        string            `json:"mana_cost"`
+    CMC             *float64          `json:"cmc"`
+    TypeLine        string            `json:"type_line"`
+    OracleText      string            `json:"oracle_text"`
+    Power           string            `json:"power"`
+    Toughness       string            `json:"toughness"`
+    Colors          []string          `json:"colors"`
+    ColorIdentity   []string          `json:"color_identity"`
+    Keywords        []string          `json:"keywords"`
+    EDHRecRank      *int              `json:"edhrec_rank"`
+    Set             string            `json:"set"`
+    CollectorNumber string            `json:"collector_number"`
+    Rarity          string            `json:"rarity"`
+    Layout          string            `json:"layout"`
+    ImageURIs       map[string]string `json:"image_uris"`
+    Legalities      json.RawMessage   `json:"legalities"`
+    Prices          json.RawMessage   `json:"prices"`
+    Faces           []struct {
+        Name       string            `json:"name"`
+        OracleID   string            `json:"oracle_id"`
+        ManaCost   string            `json:"mana_cost"`
+        TypeLine   string            `json:"type_line"`
+        OracleText string            `json:"oracle_text"`
+        Power      string            `json:"power"`
+        Toughness  string            `json:"toughness"`
+        ImageURIs  map[string]string `json:"image_uris"`
+    } `json:"card_faces"`
+}
+
+// oracle mirrors extract_props in embed_cards.py: top-level text, else non-empty face texts joined by " || ".
+func (c scryCard) oracle() string {
+    if c.OracleText != "" { return c.OracleText }
+    var parts []string
+    for _, f := range c.Faces {
+        if f.OracleText != "" { parts = append(parts, f.OracleText) }
+    }
+    return strings.Join(parts, " || ")
+}
+
+// oracleID returns the card's oracle ID; reversible cards only carry it per face.
+func (c scryCard) oracleID() string {
+    if c.OracleID != "" { return c.OracleID }
+    for _, f := range c.Faces {
+        if f.OracleID != "" { return f.OracleID }
+    }
+    return ""
+}
+
+// image returns the top-level image of the given size, else the first face that has one.
+func (c scryCard) image(size string) string {
+    if u, ok := c.ImageURIs[size]; ok { return u }
+    for _, f := range c.Faces {
+        if u, ok := f.ImageURIs[size]; ok { return u }
+    }
+    return ""
+}
+
+var colorWords = map[string]string{"W": "White", "U": "Blue", "B": "Black", "R": "Red", "G": "Green"}
+
+// embedText mirrors build_embed_text in embed_cards.py so Go- and Python-embedded batches agree.
+func embedText(c scryCard, includeName bool, tagsWeight int) string {
+    oracle := c.OracleText
+    if oracle == "" {
+        var parts []string
+        for _, f := range c.Faces {
+            if f.TypeLine != "" || f.OracleText != "" { parts = append(parts, f.TypeLine+" :: "+f.OracleText) }
+        }
+        oracle = strings.Join(parts, " || ")
+    }
+    colors := "Colorless"
+    if len(c.Colors) > 0 {
+        words := make([]string, len(c.Colors))
+        for i, col := range c.Colors {
+            words[i] = col
+            if w, ok := colorWords[col]; ok { words[i] = w }
+        }
+        colors = strings.Join(words, "/")
+    }
+    var fields []string
+    if includeName && c.Name != "" { fields = append(fields, "Name: "+c.Name) }
+    if c.TypeLine != "" { fields = append(fields, "Type: "+c.TypeLine) }
+    if c.ManaCost != "" { fields = append(fields, "ManaCost: "+c.ManaCost) }
+    fields = append(fields, "Colors: "+colors)
+    if tags := extractTags(c.TypeLine, oracle); len(tags) > 0 {
+        line := "Tags: " + strings.Join(tags, " ")
+        for i := 0; i < max(1, tagsWeight); i++ { fields = append(fields, line) }
+    }
+    if oracle != "" { fields = append(fields, "Oracle: "+oracle) }
+    return strings.Join(fields, "\n")
+}
+
+var mvLeq = regexp.MustCompile(`mana value (\d+) or less`)
+
+// extractTags mirrors extract_tags in embed_cards.py.
+func extractTags(typeLine, oracle string) []string {
+    tl := strings.ToLower(typeLine)
+    ot := strings.ToLower(strings.ReplaceAll(oracle, "converted mana cost", "mana value"))
+    var tags []string
+    for _, t := range [][2]string{
+        {"enchantment", "type_enchantment"}, {"aura", "type_aura"}, {"equipment", "type_equipment"},
+        {"artifact", "type_artifact"}, {"creature", "type_creature"}, {"planeswalker", "type_planeswalker"},
+        {"legendary", "type_legendary"},
+    } {
+        if strings.Contains(tl, t[0]) || strings.Contains(ot, t[0]) { tags = append(tags, t[1]) }
+    }
+    if strings.Contains(ot, "search your library") {
+        tags = append(tags, "tutor")
+        if strings.Contains(ot, "put") && strings.Contains(ot, "onto the battlefield") {
+            tags = append(tags, "tutor_to_battlefield")
+        } else if strings.Contains(ot, "reveal") || strings.Contains(ot, "put it into your hand") {
+            tags = append(tags, "tutor_to_hand")
+        }
+    }
+    if strings.Contains(ot, "onto the battlefield") { tags = append(tags, "cheat_battlefield") }
+    if strings.Contains(ot, "whenever") && strings.Contains(ot, "attacks") { tags = append(tags, "attack_trigger") }
+    if strings.Contains(ot, "whenever") && strings.Contains(ot, "enters the battlefield") { tags = append(tags, "etb_trigger") }
+    if m := mvLeq.FindStringSubmatch(ot); m != nil { tags = append(tags, "mv_leq_"+m[1]) }
+    for _, t := range [][2]string{
+        {"aura", "kw_aura"}, {"constellation", "kw_constellation"}, {"mentor", "kw_mentor"},
+        {"equip", "kw_equip"}, {"sagas", "kw_saga"}, {"tutor", "kw_tutor"},
+    } {
+        if strings.Contains(ot, t[0]) { tags = append(tags, t[1]) }
+    }
+    seen := map[string]bool{}
+    out := tags[:0]
+    for _, t := range tags {
+        if !seen[t] { seen[t] = true; out = append(out, t) }
+    }
+    return out
+}
+
+// cardProps mirrors extract_props in embed_cards.py; nil numbers are left out because Weaviate rejects nulls.
+func cardProps(c scryCard) map[string]any {
+    orEmpty := func(s []string) []string { if s == nil { return []string{} }; return s }
+    legalities, formats := "", []string{}
+    if len(c.Legalities) > 0 && string(c.Legalities) != "null" {
+        var buf bytes.Buffer
+        if json.Compact(&buf, c.Legalities) == nil { legalities = buf.String() }
+        var m map[string]string
+        _ = json.Unmarshal(c.Legalities, &m)
+        for f, st := range m {
+            if st == "legal" || st == "restricted" { formats = append(formats, f) }
+        }
+        sort.Strings(formats)
+        if len(m) == 0 { legalities = "" }
+    }
+    prices := ""
+    if len(c.Prices) > 0 && string(c.Prices) != "null" && string(c.Prices) != "{}" {
+        var buf bytes.Buffer
+        if json.Compact(&buf, c.Prices) == nil { prices = buf.String() }
+    }
+    faces := ""
+    if len(c.Faces) > 1 {
+        fs := make([]wv.CardFace, len(c.Faces))
+        for i, f := range c.Faces {
+            fs[i] = wv.CardFace{Name: f.Name, ManaCost: f.ManaCost, TypeLine: f.TypeLine, OracleText: f.OracleText, Power: f.Power, Toughness: f.Toughness, ImageNormal: f.ImageURIs["normal"]}
+        }
+        b, _ := json.Marshal(fs)
+        faces = string(b)
+    }
+    p := map[string]any{
+        "scryfall_id": c.ID, "oracle_id": c.oracleID(), "name": c.Name, "mana_cost": c.ManaCost, "type_line": c.TypeLine,
+        "oracle_text": c.oracle(), "power": c.Power, "toughness": c.Toughness,
+        "colors": orEmpty(c.Colors), "color_identity": orEmpty(c.ColorIdentity), "keywords": orEmpty(c.Keywords),
+        "set": c.Set, "collector_number": c.CollectorNumber, "rarity": c.Rarity, "layout": c.Layout,
+        "image_small": c.image("small"), "image_normal": c.image("normal"), "image_large": c.image("large"),
+        "legalities": legalities, "legal_formats": formats, "card_faces": faces, "prices": prices,
+    }
+    if c.CMC != nil { p["cmc"] = *c.CMC }
+    if c.EDHRecRank != nil { p["edhrec_rank"] = *c.EDHRecRank }
+    return p
+}
+
+// batchWindow reads up to limit cards with an ID starting at bulk index offset (the bulk file
+// may be gzipped). pos[i] is the
+// bulk index of cards[i]; next is the index after the last card read and total the bulk length.
+func batchWindow(bulkPath string, offset, limit int) (cards []scryCard, pos []int, next, total int, err error) {
+    f, err := scryfall.OpenBulk(bulkPath)
+    if err != nil { return nil, nil, 0, 0, err }
+    defer f.Close()
+    dec := json.NewDecoder(f)
+    if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+        return nil, nil, 0, 0, fmt.Errorf("%s: expected a JSON array of cards", bulkPath)
+    }
+    next = offset
+    for i := 0; dec.More(); i++ {
+        var raw json.RawMessage
+        if err := dec.Decode(&raw); err != nil { return nil, nil, 0, 0, fmt.Errorf("%s: card %d: %w", bulkPath, i, err) }
+        total++
+        if i < offset || len(cards) >= limit { continue }
+        next = i + 1
+        var c scryCard
+        if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" { continue }
+        cards, pos = append(cards, c), append(pos, i)
+    }
+    return cards, pos, next, total, nil
+}
+
+// runGoBatch is the single-batch action when EmbedURL is set: it embeds one window of the bulk
+// file via the HTTP embedder, writes the same batch file and checkpoint as embed_cards.py, and
+// ingests it. If the embedder fails part-way, the cards embedded so far are still ingested and
+// the checkpoint stops at the first failed card so the next run picks up from there. The
+// ingested batch is then verified against Weaviate (see verifyBatch).
+func (m model) runGoBatch() tea.Cmd {
+    return func() tea.Msg {
+        cp, _ := prg.ReadCheckpoint(m.cfg.Checkpoint)
+        offset := cp.NextOffset
+        cards, pos, next, total, err := batchWindow(m.cfg.ScryfallJSON, offset, m.cfg.BatchSize)
+        if err != nil { return doneMsg{err: err} }
+        if len(cards) == 0 {
+            if next <= offset { return doneMsg{note: fmt.Sprintf("Nothing to embed at offset %d/%d", offset, total)} }
+            // A window of cards without IDs: step past it so the next run doesn't read it again.
+            cp.NextOffset, cp.Total = next, total
+            if err := prg.WriteCheckpoint(m.cfg.Checkpoint, cp); err != nil { return doneMsg{err: fmt.Errorf("write checkpoint: %w", err)} }
+            return doneMsg{note: fmt.Sprintf("Skipped %d cards without an ID -> next_offset=%d/%d", next-offset, next, total)}
+        }
+
+        texts := make([]string, len(cards))
+        for i, c := range cards { texts[i] = embedText(c, m.cfg.IncludeName, m.cfg.TagsWeight) }
+        e := embed.NewHTTPEmbedder(m.cfg.EmbedURL, m.cfg.Model)
+        e.ChunkSize = m.cfg.BatchSize
+        ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+        defer cancel()
+        vecs, embedErr := e.Embed(ctx, texts)
+        var partial *embed.PartialError
+        if embedErr != nil && !errors.As(embedErr, &partial) { return doneMsg{err: embedErr} }
+        if partial != nil { next = pos[len(vecs)] }
+
+        type object struct {
+            Class      string         `json:"class"`
+            ID         string         `json:"id"`
+            Properties map[string]any `json:"properties"`
+            Vector     []float64      `json:"vector"`
+        }
+        objs := make([]object, len(vecs))
+        for i, v := range vecs {
+            objs[i] = object{Class: "Card", ID: cards[i].ID, Properties: cardProps(cards[i]), Vector: embed.Normalize(v)}
+        }
+        out := filepath.Join(m.cfg.OutDir, fmt.Sprintf("weaviate_batch.offset_%d.json", offset))
+        if err := os.MkdirAll(m.cfg.OutDir, 0o755); err != nil { return doneMsg{err: err} }
+        b, err := json.Marshal(map[string]any{"objects": objs})
+        if err != nil { return doneMsg{err: err} }
+        if err := os.WriteFile(out, b, 0o644); err != nil { return doneMsg{err: err} }
+        if err := prg.WriteCheckpoint(m.cfg.Checkpoint, prg.Checkpoint{NextOffset: next, Total: total, LastBatchOut: out, Model: m.cfg.Model, IncludeName: m.cfg.IncludeName, Kind: "http", BatchCounts: cp.BatchCounts, Mismatches: cp.Mismatches}); err != nil {
+            return doneMsg{err: fmt.Errorf("write checkpoint: %w", err)}
+        }
+        note := fmt.Sprintf("Embedded %d/%d cards via %s -> next_offset=%d/%d", len(vecs), len(cards), m.cfg.EmbedURL, next, total)
+        msg := runProcess([]string{"./scripts/ingest_batch.sh", out, m.cfg.WeaviateURL}, nil)
+        if dm, ok := msg.(doneMsg); ok {
+            dm.note = note
+            if dm.err == nil {
+                vnote, err := verifyBatch(m.cfg, offset, out)
+                if vnote != "" { dm.note += "\n" + vnote }
+                dm.err = err
+            }
+            if dm.err == nil && partial != nil { dm.err = partial }
+            return dm
+        }
+        return msg
+    }
+}
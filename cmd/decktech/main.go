@@ -4,12 +4,15 @@ import (
     "bufio"
     "context"
     "encoding/json"
+    "errors"
     "fmt"
     "io"
+    "io/fs"
     "os"
     "os/exec"
     "path/filepath"
     "strings"
+    "sync/atomic"
     "time"
 
     tea "github.com/charmbracelet/bubbletea"
@@ -18,6 +21,8 @@ import (
     "github.com/charmbracelet/bubbles/textinput"
     "github.com/charmbracelet/lipgloss"
     prg "github.com/domano/decktech/pkg/progress"
+    "github.com/domano/decktech/pkg/scryfall"
+    wv "github.com/domano/decktech/pkg/weaviateclient"
 )
 
 type config struct {
@@ -29,6 +34,16 @@ type config struct {
     IncludeName   bool   `json:"include_name"`
     BatchSize     int    `json:"batch_size"`
     TagsWeight    int    `json:"tags_weight"`
+    EmbedURL      string `json:"embed_url,omitempty"` // if set, Run Single Batch embeds via this HTTP endpoint instead of Python
+    // AllowModelMismatch skips the check that Model matches the model already ingested
+    // (checkpoint and Weaviate class), like a --force flag.
+    AllowModelMismatch bool `json:"allow_model_mismatch,omitempty"`
+    // VerifyRetries re-ingests a single batch up to this many times when verification finds
+    // objects missing from Weaviate; 0 only reports the mismatch.
+    VerifyRetries int `json:"verify_retries,omitempty"`
+    // Actions adds custom menu entries that run a command (see customAction). They are
+    // validated on startup; invalid ones are left out and reported on the menu.
+    Actions []customAction `json:"actions,omitempty"`
 }
 
 func defaultConfig() config {
@@ -76,17 +91,20 @@ const (
     modeMenu viewMode = iota
     modeConfig
     modeRun
+    modeConfirm // model mismatch: y runs the pending action anyway
+    modeConfirmClean // Clean Embeddings: y deletes the local files and every Card object
 )
 
 type menuItem struct { title, desc string }
 
 var menuItems = []menuItem{
-    {"Download Scryfall", "Fetch bulk JSON to data/oracle-cards.json"},
+    {"Download Scryfall", "Fetch bulk JSON to data/oracle-cards.json (resumes partial downloads)"},
     {"Apply Schema", "Create/verify Weaviate Card class"},
-    {"Run Single Batch", "Embed + ingest one batch using checkpoint"},
+    {"Run Single Batch", "Embed + ingest + verify one batch using checkpoint"},
     {"Run Continuous", "Loop batches until completion"},
     {"Clean Embeddings", "Delete local batches/checkpoint and wipe Card class"},
     {"Re-embed Full", "Reset checkpoint and run continuous with current config"},
+    {"Update (delta)", "Embed + ingest only cards new or changed since the last run"},
     {"Show Status", "Display checkpoint progress"},
     {"Edit Config", "Update paths and parameters"},
     {"Quit", "Exit the CLI"},
@@ -101,7 +119,9 @@ const (
     actContinuous
     actClean
     actReembed
+    actDelta
     actShowStatus
+    actCustom
 )
 
 type model struct {
@@ -109,6 +129,9 @@ type model struct {
     cfgPath     string
     mode        viewMode
     sel         int
+    menu        []menuItem // menuItems with the custom actions before Quit
+    actions     []customAction
+    actionErrs  []string // custom actions left out of the menu, and why
     spinner     spinner.Model
     progress    progress.Model
     logs        []string
@@ -117,6 +140,15 @@ type model struct {
     // config inputs
     inputs      []*textinput.Model
     cursor      int
+    // model mismatch confirmation
+    confirm     modelCheckMsg
+    modelOK     bool // set for one startAction call once the model check passed or was confirmed
+    // Weaviate connectivity, checked on startup, after config changes and on r
+    pinging     bool
+    pingErr     error
+    // Clean Embeddings: local files listed for confirmation, and the cancel for a running clean
+    cleanFiles  []string
+    cancel      context.CancelFunc
 }
 
 func newModel(cfgPath string) model {
@@ -126,6 +158,7 @@ func newModel(cfgPath string) model {
     // config inputs setup
     c := defaultConfig()
     if f, err := loadConfig(cfgPath); err == nil { c = f }
+    actions, actionErrs := validateActions(c.Actions)
     inputs := []*textinput.Model{}
     mk := func(placeholder, val string) *textinput.Model {
         ti := textinput.New()
@@ -140,25 +173,44 @@ func newModel(cfgPath string) model {
     inputs = append(inputs, mk("Model", c.Model))
     inputs = append(inputs, mk("Batch size (int)", fmt.Sprintf("%d", c.BatchSize)))
     inputs = append(inputs, mk("Tags weight (int)", fmt.Sprintf("%d", c.TagsWeight)))
+    inputs = append(inputs, mk("Embed URL (empty = Python embedder)", c.EmbedURL))
     inc := textinput.New()
     inc.Placeholder = "Include name (true/false)"
     inc.SetValue(fmt.Sprintf("%v", c.IncludeName))
     inputs = append(inputs, &inc)
+    inputs = append(inputs, mk("Allow model mismatch (true/false)", fmt.Sprintf("%v", c.AllowModelMismatch)))
+    inputs = append(inputs, mk("Verify retries (int)", fmt.Sprintf("%d", c.VerifyRetries)))
 
     return model{
         cfg: c,
         cfgPath: cfgPath,
         mode: modeMenu,
+        menu: buildMenu(actions),
+        actions: actions,
+        actionErrs: actionErrs,
         spinner: s,
         progress: p,
         inputs: inputs,
+        pinging: true,
     }
 }
 
-func (m model) Init() tea.Cmd { return nil }
+func (m model) Init() tea.Cmd { return pingWeaviate(m.cfg.WeaviateURL) }
+
+// pingMsg reports whether Weaviate answered at url.
+type pingMsg struct{ url string; err error }
+
+// pingWeaviate checks Weaviate at url with a short timeout.
+func pingWeaviate(url string) tea.Cmd {
+    return func() tea.Msg {
+        ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+        defer cancel()
+        return pingMsg{url: url, err: wv.NewClient(url).Ping(ctx)}
+    }
+}
 
 type logMsg string
-type doneMsg struct{ err error }
+type doneMsg struct{ err error; note string } // note, if set, is logged on completion
 type tickMsg struct{}
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -176,7 +228,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
             case "up", "k":
                 if m.sel > 0 { m.sel-- }
             case "down", "j":
-                if m.sel < len(menuItems)-1 { m.sel++ }
+                if m.sel < len(m.menu)-1 { m.sel++ }
+            case "r":
+                m.pinging = true
+                return m, pingWeaviate(m.cfg.WeaviateURL)
             case "enter":
                 return m.startAction(m.sel)
             }
@@ -202,10 +257,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                 if tw, err := fmt.Sscanf(m.inputs[6].Value(), "%d", &m.cfg.TagsWeight); tw == 0 || err != nil {
                     m.cfg.TagsWeight = 2
                 }
-                m.cfg.IncludeName = strings.ToLower(strings.TrimSpace(m.inputs[7].Value())) == "true"
+                m.cfg.EmbedURL = strings.TrimSpace(m.inputs[7].Value())
+                m.cfg.IncludeName = strings.ToLower(strings.TrimSpace(m.inputs[8].Value())) == "true"
+                m.cfg.AllowModelMismatch = strings.ToLower(strings.TrimSpace(m.inputs[9].Value())) == "true"
+                if n, err := fmt.Sscanf(m.inputs[10].Value(), "%d", &m.cfg.VerifyRetries); n == 0 || err != nil || m.cfg.VerifyRetries < 0 {
+                    m.cfg.VerifyRetries = 0
+                }
                 _ = saveConfig(m.cfgPath, m.cfg)
-                m.mode = modeMenu
-                return m, nil
+                m.mode, m.pinging = modeMenu, true
+                return m, pingWeaviate(m.cfg.WeaviateURL)
             }
             // forward to focused input
             for i := range m.inputs {
@@ -215,36 +275,75 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                     return m, cmd
                 }
             }
+        case modeConfirm:
+            switch msg.String() {
+            case "y", "Y":
+                m.mode, m.modelOK = modeMenu, true
+                return m.startAction(m.confirm.sel)
+            case "ctrl+c":
+                return m, tea.Quit
+            default:
+                m.mode = modeMenu
+                return m, nil
+            }
+        case modeConfirmClean:
+            switch msg.String() {
+            case "y", "Y":
+                ctx, cancel := context.WithCancel(context.Background())
+                m.mode, m.running, m.action, m.cancel = modeRun, true, actClean, cancel
+                return m, tea.Batch(m.spinner.Tick, m.runClean(ctx), tea.Tick(1*time.Second, func(time.Time) tea.Msg { return tickMsg{} }))
+            case "ctrl+c":
+                return m, tea.Quit
+            default:
+                m.mode = modeMenu
+                return m, nil
+            }
         case modeRun:
             switch msg.String() {
             case "esc":
-                // allow cancel display; processes should respect context
+                // a Go clean stops on cancel; processes should respect context
+                if m.running && m.cancel != nil { m.cancel() }
                 if !m.running { m.mode = modeMenu }
             }
         }
     case tea.WindowSizeMsg:
         return m, nil
+    case modelCheckMsg:
+        if len(msg.problems) > 0 {
+            m.mode, m.confirm = modeConfirm, msg
+            return m, nil
+        }
+        m.modelOK = true
+        return m.startAction(msg.sel)
     case logMsg:
         m.logs = append(m.logs, string(msg))
         if len(m.logs) > 1000 { m.logs = m.logs[len(m.logs)-1000:] }
         return m, nil
+    case pingMsg:
+        if msg.url != m.cfg.WeaviateURL { return m, nil } // answer for a URL changed since
+        m.pinging, m.pingErr = false, msg.err
+        return m, nil
     case doneMsg:
         prev := m.action
         m.running = false
+        if m.cancel != nil { m.cancel(); m.cancel = nil }
+        if msg.note != "" { m.logs = append(m.logs, msg.note) }
         if msg.err != nil {
             m.logs = append(m.logs, "ERROR: "+msg.err.Error())
         } else {
             // Auto-return to menu for single-shot actions (and continuous when it completes)
-            if prev == actSingleBatch || prev == actApplySchema || prev == actDownload || prev == actShowStatus || prev == actClean || prev == actContinuous {
+            if prev == actSingleBatch || prev == actApplySchema || prev == actDownload || prev == actShowStatus || prev == actClean || prev == actContinuous || prev == actCustom {
                 m.mode = modeMenu
             }
         }
         m.action = actNone
         return m, nil
     case tickMsg:
-        // update progress from checkpoint periodically
+        // update progress from the download counters or the checkpoint periodically
         cp, err := prg.ReadCheckpoint(m.cfg.Checkpoint)
-        if err == nil && cp.Total > 0 {
+        if m.action == actDownload {
+            if total := download.total.Load(); total > 0 { m.progress.SetPercent(float64(download.done.Load()) / float64(total)) }
+        } else if err == nil && cp.Total > 0 {
             m.progress.SetPercent(float64(cp.NextOffset) / float64(cp.Total))
         }
         if m.running {
@@ -261,9 +360,9 @@ func (m model) View() string {
         b := &strings.Builder{}
         title := lipgloss.NewStyle().Bold(true).Render("DeckTech CLI — Import & Batch")
         fmt.Fprintln(b, title)
-        fmt.Fprintln(b, "Use ↑/↓ to navigate, Enter to run, q to quit.")
+        fmt.Fprintln(b, "Use ↑/↓ to navigate, Enter to run, r to recheck Weaviate, q to quit.")
         fmt.Fprintln(b)
-        for i, it := range menuItems {
+        for i, it := range m.menu {
             cursor := "  "
             if m.sel == i { cursor = "> " }
             line := fmt.Sprintf("%s%s — %s", cursor, it.title, it.desc)
@@ -273,11 +372,19 @@ func (m model) View() string {
             fmt.Fprintln(b, line)
         }
         fmt.Fprintln(b)
+        for _, e := range m.actionErrs { fmt.Fprintln(b, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("Custom action skipped: "+e)) }
         cp, err := prg.ReadCheckpoint(m.cfg.Checkpoint)
         if err == nil && cp.Total > 0 {
             fmt.Fprintf(b, "Progress: %d / %d (%.1f%%)\n", cp.NextOffset, cp.Total, 100*float64(cp.NextOffset)/float64(cp.Total))
         }
-        fmt.Fprintf(b, "Weaviate: %s\n", m.cfg.WeaviateURL)
+        switch {
+        case m.pinging:
+            fmt.Fprintf(b, "Weaviate: %s (checking...)\n", m.cfg.WeaviateURL)
+        case m.pingErr != nil:
+            fmt.Fprintln(b, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(fmt.Sprintf("Weaviate unreachable at %s: %v — r to retry", m.cfg.WeaviateURL, m.pingErr)))
+        default:
+            fmt.Fprintf(b, "Weaviate: %s\n", m.cfg.WeaviateURL)
+        }
         return b.String()
     case modeConfig:
         b := &strings.Builder{}
@@ -287,14 +394,49 @@ func (m model) View() string {
             fmt.Fprintln(b, input.View())
         }
         return b.String()
+    case modeConfirm:
+        b := &strings.Builder{}
+        warn := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196"))
+        fmt.Fprintln(b, warn.Render("Embedding model mismatch — "+m.menu[m.confirm.sel].title+" would mix vectors from different models"))
+        fmt.Fprintln(b)
+        for _, p := range m.confirm.problems { fmt.Fprintln(b, "  • "+p) }
+        fmt.Fprintln(b)
+        fmt.Fprintln(b, "Similarities between cards embedded by different models are meaningless.")
+        fmt.Fprintln(b, "Fix the Model in Edit Config, use Re-embed Full, or set \"Allow model mismatch\" to skip this check.")
+        fmt.Fprintln(b)
+        fmt.Fprintln(b, "Proceed anyway? (y/N)")
+        return b.String()
+    case modeConfirmClean:
+        b := &strings.Builder{}
+        warn := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196"))
+        fmt.Fprintln(b, warn.Render("Clean Embeddings deletes every Card object in Weaviate at "+m.cfg.WeaviateURL))
+        fmt.Fprintln(b)
+        if len(m.cleanFiles) == 0 {
+            fmt.Fprintln(b, "No local checkpoint or batch files to remove.")
+        } else {
+            fmt.Fprintf(b, "and removes %d local files:\n", len(m.cleanFiles))
+        }
+        for i, f := range m.cleanFiles {
+            if i == 10 { fmt.Fprintf(b, "  … and %d more\n", len(m.cleanFiles)-10); break }
+            fmt.Fprintln(b, "  • "+f)
+        }
+        fmt.Fprintln(b)
+        fmt.Fprintln(b, "Continue? (y/N)")
+        return b.String()
     case modeRun:
         b := &strings.Builder{}
-        head := lipgloss.NewStyle().Bold(true).Render("Running… (Esc returns when finished)")
+        hint := "Running… (Esc returns when finished)"
+        if m.action == actClean { hint = "Cleaning… (Esc cancels)" }
+        head := lipgloss.NewStyle().Bold(true).Render(hint)
         fmt.Fprintln(b, head)
         if m.running { fmt.Fprintln(b, m.spinner.View()) }
         // Progress bar + numeric checkpoint
         fmt.Fprintln(b, m.progress.View())
-        if cp, err := prg.ReadCheckpoint(m.cfg.Checkpoint); err == nil && cp.Total > 0 {
+        if m.action == actDownload {
+            fmt.Fprintf(b, "Downloaded: %.1f / %.1f MB\n", float64(download.done.Load())/(1<<20), float64(download.total.Load())/(1<<20))
+        } else if m.action == actClean {
+            fmt.Fprintf(b, "Deleted: %d Card objects\n", cleaned.Load())
+        } else if cp, err := prg.ReadCheckpoint(m.cfg.Checkpoint); err == nil && cp.Total > 0 {
             pct := 100 * float64(cp.NextOffset) / float64(cp.Total)
             fmt.Fprintf(b, "Progress: %d / %d (%.1f%%)\n", cp.NextOffset, cp.Total, pct)
         }
@@ -311,6 +453,15 @@ func (m model) View() string {
 }
 
 func (m model) startAction(sel int) (tea.Model, tea.Cmd) {
+    if modelGuarded(sel) && !m.cfg.AllowModelMismatch && !m.modelOK {
+        return m, m.checkModel(sel)
+    }
+    m.modelOK = false
+    if i := m.customIndex(sel); i >= 0 {
+        m.mode, m.running, m.action = modeRun, true, actCustom
+        return m, tea.Batch(m.spinner.Tick, runCustom(m.actions[i]))
+    }
+    if sel == len(m.menu)-1 { return m, tea.Quit }
     switch sel {
     case 0: // download
         m.mode, m.running, m.action = modeRun, true, actDownload
@@ -324,13 +475,16 @@ func (m model) startAction(sel int) (tea.Model, tea.Cmd) {
     case 3: // continuous
         m.mode, m.running, m.action = modeRun, true, actContinuous
         return m, tea.Batch(m.spinner.Tick, m.runContinuous(), tea.Tick(1*time.Second, func(time.Time) tea.Msg { return tickMsg{} }))
-    case 4: // clean embeddings
-        m.mode, m.running, m.action = modeRun, true, actClean
-        return m, tea.Batch(m.spinner.Tick, m.runClean(), tea.Tick(1*time.Second, func(time.Time) tea.Msg { return tickMsg{} }))
+    case 4: // clean embeddings: confirm first
+        m.mode, m.cleanFiles = modeConfirmClean, cleanFiles(m.cfg)
+        return m, nil
     case 5: // re-embed full
         m.mode, m.running, m.action = modeRun, true, actReembed
         return m, tea.Batch(m.spinner.Tick, m.runReembedFull(), tea.Tick(1*time.Second, func(time.Time) tea.Msg { return tickMsg{} }))
-    case 6: // show status
+    case 6: // delta update
+        m.mode, m.running, m.action = modeRun, true, actDelta
+        return m, tea.Batch(m.spinner.Tick, m.runDelta(), tea.Tick(1*time.Second, func(time.Time) tea.Msg { return tickMsg{} }))
+    case 7: // show status
         m.mode = modeRun
         m.running = false
         m.action = actShowStatus
@@ -339,22 +493,31 @@ func (m model) startAction(sel int) (tea.Model, tea.Cmd) {
             if err != nil { return logMsg("No checkpoint found") }
             pct := 0.0
             if cp.Total > 0 { pct = 100*float64(cp.NextOffset)/float64(cp.Total) }
-            return logMsg(fmt.Sprintf("Progress: %d / %d (%.1f%%)", cp.NextOffset, cp.Total, pct))
+            status := fmt.Sprintf("Progress: %d / %d (%.1f%%)", cp.NextOffset, cp.Total, pct)
+            for _, mm := range cp.Mismatches { status += "\nUnverified " + mm.String() }
+            return logMsg(status)
         }
-    case 7: // edit config
+    case 8: // edit config
         m.mode = modeConfig
         return m, nil
-    case 8:
-        return m, tea.Quit
     }
     return m, nil
 }
 
 // Commands
+// download tracks bytes fetched by runDownload so the tick handler can drive the progress bar.
+var download struct{ done, total atomic.Int64 }
+
 func (m model) runDownload() tea.Cmd {
     return func() tea.Msg {
-        args := []string{"scripts/download_scryfall.py", "-k", "oracle_cards", "-o", m.cfg.ScryfallJSON}
-        return runProcess(args, nil)
+        download.done.Store(0)
+        download.total.Store(0)
+        err := scryfall.DownloadBulk(context.Background(), "oracle_cards", m.cfg.ScryfallJSON, func(done, total int64) {
+            download.done.Store(done)
+            download.total.Store(total)
+        })
+        if err != nil { return doneMsg{err: err} }
+        return doneMsg{note: fmt.Sprintf("Saved oracle_cards to %s (%.1f MB)", m.cfg.ScryfallJSON, float64(download.done.Load())/(1<<20))}
     }
 }
 
@@ -366,6 +529,7 @@ func (m model) runApplySchema() tea.Cmd {
 }
 
 func (m model) runSingleBatch() tea.Cmd {
+    if m.cfg.EmbedURL != "" { return m.runGoBatch() }
     return func() tea.Msg {
         // embed one batch with current checkpoint/offset
         env := []string{"MODEL=" + m.cfg.Model, "EMBED_QUIET=1", fmt.Sprintf("EMBED_TAGS_WEIGHT=%d", m.cfg.TagsWeight)}
@@ -379,7 +543,9 @@ func (m model) runSingleBatch() tea.Cmd {
         if m.cfg.IncludeName { embed = append(embed, "--include-name") }
         if msg := runProcess(embed, env); isErr(msg) { return msg }
         ingest := []string{"./scripts/ingest_batch.sh", out, m.cfg.WeaviateURL}
-        return runProcess(ingest, nil)
+        if msg := runProcess(ingest, nil); isErr(msg) { return msg }
+        note, err := verifyBatch(m.cfg, offset, out)
+        return doneMsg{err: err, note: note}
     }
 }
 
@@ -392,11 +558,32 @@ func (m model) runContinuous() tea.Cmd {
     }
 }
 
-func (m model) runClean() tea.Cmd {
+// cleaned counts the Card objects deleted by runClean so far, for the run view.
+var cleaned atomic.Int64
+
+// cleanFiles lists the local files Clean Embeddings removes: the checkpoint and the batch files.
+func cleanFiles(cfg config) []string {
+    var files []string
+    if _, err := os.Stat(cfg.Checkpoint); err == nil { files = append(files, cfg.Checkpoint) }
+    batches, _ := filepath.Glob(filepath.Join(cfg.OutDir, "weaviate_batch*.json"))
+    return append(files, batches...)
+}
+
+// runClean deletes every Card object (keeping the class) and then the confirmed local files.
+// Local files stay when the delete fails or is canceled, so a retry starts from the same state.
+func (m model) runClean(ctx context.Context) tea.Cmd {
+    files := m.cleanFiles
     return func() tea.Msg {
-        env := []string{"WEAVIATE_URL=" + m.cfg.WeaviateURL, "OUTDIR=" + m.cfg.OutDir, "CHECKPOINT=" + m.cfg.Checkpoint}
-        args := []string{"./scripts/clean_embeddings.sh"}
-        return runProcess(args, env)
+        cleaned.Store(0)
+        err := wv.NewClient(m.cfg.WeaviateURL).DeleteAllCards(ctx, func(n int) { cleaned.Store(int64(n)) })
+        if err != nil { return doneMsg{err: fmt.Errorf("delete cards after %d: %w", cleaned.Load(), err)} }
+        var errs []error
+        removed := 0
+        for _, f := range files {
+            if err := os.Remove(f); err != nil && !errors.Is(err, fs.ErrNotExist) { errs = append(errs, err); continue }
+            removed++
+        }
+        return doneMsg{err: errors.Join(errs...), note: fmt.Sprintf("Deleted %d Card objects and %d local files", cleaned.Load(), removed)}
     }
 }
 
@@ -0,0 +1,48 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    tea "github.com/charmbracelet/bubbletea"
+    prg "github.com/domano/decktech/pkg/progress"
+    wv "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// modelCheckMsg carries the result of checkModel for the menu action sel.
+type modelCheckMsg struct {
+    sel      int
+    problems []string
+}
+
+// modelGuarded reports whether menu action sel adds vectors to the existing collection, so
+// embedding with a different model than the one already ingested would mix vector spaces.
+// Re-embed Full resets the checkpoint on purpose and rewrites every card, so it is exempt.
+func modelGuarded(sel int) bool { return sel == 2 || sel == 3 || sel == 6 }
+
+// checkModel compares cfg.Model with the model recorded in the checkpoint and with the Card
+// class's vectorizer settings. A missing checkpoint or class means nothing was ingested yet.
+func (m model) checkModel(sel int) tea.Cmd {
+    return func() tea.Msg {
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        return modelCheckMsg{sel: sel, problems: modelMismatches(ctx, m.cfg)}
+    }
+}
+
+func modelMismatches(ctx context.Context, cfg config) []string {
+    var problems []string
+    if cp, err := prg.ReadCheckpoint(cfg.Checkpoint); err == nil && cp.NextOffset > 0 && cp.Model != "" && cp.Model != cfg.Model {
+        problems = append(problems, fmt.Sprintf("checkpoint %s: %d cards were embedded with %q, config uses %q", cfg.Checkpoint, cp.NextOffset, cp.Model, cfg.Model))
+    }
+    class, err := wv.NewClient(cfg.WeaviateURL).CardClassConfig(ctx)
+    if err != nil { return problems } // no class yet, or unreachable: the ingest step reports it
+    if class.Vectorizer != "" && class.Vectorizer != "none" {
+        problems = append(problems, fmt.Sprintf("Weaviate Card class uses vectorizer %q, but DeckTech supplies its own vectors", class.Vectorizer))
+    }
+    if class.Model != "" && class.Model != cfg.Model {
+        problems = append(problems, fmt.Sprintf("Weaviate Card class is configured for model %q, config uses %q", class.Model, cfg.Model))
+    }
+    return problems
+}
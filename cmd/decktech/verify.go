@@ -0,0 +1,51 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "os"
+    "time"
+
+    prg "github.com/domano/decktech/pkg/progress"
+    wv "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// batchIDs returns the object IDs (Scryfall IDs) in a Weaviate batch file.
+func batchIDs(path string) ([]string, error) {
+    b, err := os.ReadFile(path)
+    if err != nil { return nil, err }
+    var batch struct{ Objects []struct{ ID string `json:"id"` } `json:"objects"` }
+    if err := json.Unmarshal(b, &batch); err != nil { return nil, fmt.Errorf("%s: %w", path, err) }
+    ids := make([]string, 0, len(batch.Objects))
+    for _, o := range batch.Objects {
+        if o.ID != "" { ids = append(ids, o.ID) }
+    }
+    return ids, nil
+}
+
+// verifyBatch checks that every object of the batch file out (ingested from offset) landed in
+// Weaviate, re-ingesting it up to cfg.VerifyRetries times while some are missing. The result
+// is recorded in the checkpoint's BatchCounts/Mismatches; note says what was found and err is
+// set when the batch is still incomplete or could not be checked.
+func verifyBatch(cfg config, offset int, out string) (note string, err error) {
+    ids, err := batchIDs(out)
+    if err != nil { return "", fmt.Errorf("verify batch: %w", err) }
+    cli := wv.NewClient(cfg.WeaviateURL)
+    // Read back at ALL so a replica that has not applied the batch yet counts as missing.
+    ctx, cancel := context.WithTimeout(wv.WithConsistency(context.Background(), wv.ConsistencyAll), 5*time.Minute)
+    defer cancel()
+    found, err := cli.CountPresent(ctx, ids)
+    for attempt := 0; err == nil && found < len(ids) && attempt < cfg.VerifyRetries; attempt++ {
+        if msg := runProcess([]string{"./scripts/ingest_batch.sh", out, cfg.WeaviateURL}, nil); isErr(msg) { return "", msg.(doneMsg).err }
+        found, err = cli.CountPresent(ctx, ids)
+    }
+    if err != nil { return "", fmt.Errorf("verify batch: %w", err) }
+
+    cp, _ := prg.ReadCheckpoint(cfg.Checkpoint)
+    cp.RecordBatch(offset, len(ids), found)
+    if werr := prg.WriteCheckpoint(cfg.Checkpoint, cp); werr != nil { return "", fmt.Errorf("write checkpoint: %w", werr) }
+    mm := prg.BatchMismatch{Offset: offset, Expected: len(ids), Found: found}
+    if found < len(ids) { return "", fmt.Errorf("%s", mm) }
+    return "Verified " + mm.String(), nil
+}
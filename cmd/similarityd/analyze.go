@@ -0,0 +1,227 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "io"
+    "net/http"
+    "slices"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/domano/decktech/pkg/decklist"
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// AnalyzeRequest is accepted by the /analyze/* endpoints: a raw decklist
+// ("4 Lightning Bolt" lines) and/or a plain list of names (one copy each).
+type AnalyzeRequest struct {
+    Decklist string   `json:"decklist,omitempty"`
+    Names    []string `json:"names,omitempty"`
+}
+
+// ColorBreakdown counts cards per WUBRG color plus multicolor/colorless buckets.
+// A multicolor card counts toward each of its colors and toward Multicolor.
+type ColorBreakdown struct {
+    Counts        map[string]int     `json:"counts"`
+    Percent       map[string]float64 `json:"percent"`
+    Multicolor    int                `json:"multicolor"`
+    MulticolorPct float64            `json:"multicolor_percent"`
+    Colorless     int                `json:"colorless"`
+    ColorlessPct  float64            `json:"colorless_percent"`
+}
+
+// ColorAnalysis is the /analyze/colors response.
+type ColorAnalysis struct {
+    Cards         int            `json:"cards"`
+    Colors        ColorBreakdown `json:"colors"`
+    ColorIdentity ColorBreakdown `json:"color_identity"`
+    Unresolved    []string       `json:"unresolved,omitempty"`
+}
+
+var wubrg = []string{"W", "U", "B", "R", "G"}
+
+// CurveBucket is one mana-value column of the curve; the last bucket is "7+".
+type CurveBucket struct {
+    CMC     string  `json:"cmc"`
+    Count   int     `json:"count"`
+    Percent float64 `json:"percent"`
+}
+
+// CurveAnalysis is the /analyze/curve response. The curve covers non-land cards only; a card
+// with several card types (an artifact creature) counts toward each of them.
+type CurveAnalysis struct {
+    Cards       int                `json:"cards"`
+    NonLand     int                `json:"nonland"`
+    Curve       []CurveBucket      `json:"curve"`
+    Types       map[string]int     `json:"types"`
+    TypePercent map[string]float64 `json:"type_percent"`
+    Unresolved  []string           `json:"unresolved,omitempty"`
+}
+
+var cardTypes = []string{"creature", "instant", "sorcery", "artifact", "enchantment", "planeswalker", "land"}
+
+// decodeAnalyzeRequest reads JSON, a text/plain decklist body, or GET ?names=a,b.
+func decodeAnalyzeRequest(r *http.Request) ([]decklist.Entry, error) {
+    var req AnalyzeRequest
+    switch {
+    case r.Method == http.MethodGet:
+        req.Names = similarRequestFromQuery(r.URL.Query()).Names
+    case strings.HasPrefix(r.Header.Get("Content-Type"), "text/plain"):
+        b, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+        if err != nil { return nil, &httpError{http.StatusBadRequest, "bad request: " + err.Error()} }
+        req.Decklist = string(b)
+    default:
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            return nil, &httpError{http.StatusBadRequest, "bad request: " + err.Error()}
+        }
+    }
+    entries := decklist.ParseString(req.Decklist)
+    for _, n := range req.Names {
+        if n = strings.TrimSpace(n); n != "" { entries = append(entries, decklist.Entry{Count: 1, Name: n}) }
+    }
+    if len(entries) == 0 { return nil, &httpError{http.StatusBadRequest, "decklist or names required"} }
+    return entries, nil
+}
+
+// resolveEntries bulk-looks up the decklist names, returning resolved cards (by lower-cased name)
+// and the names that did not resolve.
+func resolveEntries(ctx context.Context, cli *client.Client, entries []decklist.Entry) (map[string]client.Card, []string, error) {
+    names := decklist.Names(entries)
+    found, err := cli.GetCardsByNames(ctx, names)
+    if err != nil { return nil, nil, err }
+    var missing []string
+    for _, n := range names {
+        if _, ok := found[strings.ToLower(n)]; !ok { missing = append(missing, n) }
+    }
+    return found, missing, nil
+}
+
+func handleAnalyzeColors(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost && r.Method != http.MethodGet {
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        entries, err := decodeAnalyzeRequest(r)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+        defer cancel()
+        found, missing, err := resolveEntries(ctx, cli, entries)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        writeJSON(w, analyzeColors(entries, found, missing))
+    }
+}
+
+func handleAnalyzeCurve(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost && r.Method != http.MethodGet {
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        entries, err := decodeAnalyzeRequest(r)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+        defer cancel()
+        found, missing, err := resolveEntries(ctx, cli, entries)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        writeJSON(w, analyzeCurve(entries, found, missing))
+    }
+}
+
+// analyzeCurve builds the mana-value histogram (0..6, 7+) of non-land cards and the card-type
+// counts, weighted by decklist counts. Type percentages are relative to all resolved cards,
+// curve percentages to the non-land ones.
+func analyzeCurve(entries []decklist.Entry, found map[string]client.Card, missing []string) CurveAnalysis {
+    out := CurveAnalysis{Curve: make([]CurveBucket, 8), Types: map[string]int{}, TypePercent: map[string]float64{}, Unresolved: missing}
+    for i := range out.Curve { out.Curve[i].CMC = strconv.Itoa(i) }
+    out.Curve[7].CMC = "7+"
+    for _, t := range cardTypes { out.Types[t] = 0 }
+    for _, e := range entries {
+        c, ok := found[strings.ToLower(e.Name)]
+        if !ok { continue }
+        out.Cards += e.Count
+        types := typesOf(c.TypeLine)
+        for _, t := range types { out.Types[t] += e.Count }
+        if slices.Contains(types, "land") { continue }
+        out.NonLand += e.Count
+        out.Curve[min(max(int(c.CMC), 0), 7)].Count += e.Count
+    }
+    for i := range out.Curve { out.Curve[i].Percent = percent(out.Curve[i].Count, out.NonLand) }
+    for t, n := range out.Types { out.TypePercent[t] = percent(n, out.Cards) }
+    return out
+}
+
+// typesOf returns the card types on the front face of a type line, e.g.
+// "Legendary Artifact Creature — Golem" -> [artifact creature]; "Creature // Land" -> [creature].
+func typesOf(typeLine string) []string {
+    front, _, _ := strings.Cut(typeLine, "//")
+    front, _, _ = strings.Cut(front, "—")
+    var out []string
+    for _, w := range strings.Fields(strings.ToLower(front)) {
+        if slices.Contains(cardTypes, w) { out = append(out, w) }
+    }
+    return out
+}
+
+func percent(n, total int) float64 {
+    if total == 0 { return 0 }
+    return 100 * float64(n) / float64(total)
+}
+
+// analyzeColors tallies colors and color identity weighted by decklist counts.
+func analyzeColors(entries []decklist.Entry, found map[string]client.Card, missing []string) ColorAnalysis {
+    out := ColorAnalysis{Colors: newBreakdown(), ColorIdentity: newBreakdown(), Unresolved: missing}
+    for _, e := range entries {
+        c, ok := found[strings.ToLower(e.Name)]
+        if !ok { continue }
+        out.Cards += e.Count
+        out.Colors.add(c.Colors, e.Count)
+        out.ColorIdentity.add(c.ColorID, e.Count)
+    }
+    out.Colors.finish(out.Cards)
+    out.ColorIdentity.finish(out.Cards)
+    return out
+}
+
+func newBreakdown() ColorBreakdown {
+    b := ColorBreakdown{Counts: map[string]int{}, Percent: map[string]float64{}}
+    for _, c := range wubrg { b.Counts[c] = 0 }
+    return b
+}
+
+func (b *ColorBreakdown) add(colors []string, n int) {
+    switch {
+    case len(colors) == 0:
+        b.Colorless += n
+    case len(colors) > 1:
+        b.Multicolor += n
+    }
+    for _, c := range colors { b.Counts[strings.ToUpper(c)] += n }
+}
+
+func (b *ColorBreakdown) finish(total int) {
+    for c, n := range b.Counts { b.Percent[c] = percent(n, total) }
+    b.MulticolorPct, b.ColorlessPct = percent(b.Multicolor, total), percent(b.Colorless, total)
+}
+
+// writeJSON writes v as indented JSON, matching the /similar response style.
+func writeJSON(w http.ResponseWriter, v any) {
+    w.Header().Set("Content-Type", "application/json")
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    _ = enc.Encode(v)
+}
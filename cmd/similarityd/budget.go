@@ -0,0 +1,113 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "math"
+    "net/http"
+    "strconv"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// BudgetRequest is a SimilarRequest limited to cards at or under MaxPriceUSD.
+type BudgetRequest struct {
+    SimilarRequest
+    MaxPriceUSD float64 `json:"max_price_usd"`
+    // IncludeUnpriced keeps cards without a USD price (e.g. not sold in paper); they add
+    // nothing to the total.
+    IncludeUnpriced bool `json:"include_unpriced,omitempty"`
+}
+
+// BudgetResponse lists the under-budget suggestions and what buying all of them costs.
+type BudgetResponse struct {
+    Results       []CardResult `json:"results"`
+    TotalPriceUSD float64      `json:"total_price_usd"`
+    Unpriced      int          `json:"unpriced"`
+    HasMore       bool         `json:"has_more"` // another under-budget card follows this page
+    Unresolved    []string     `json:"unresolved,omitempty"` // seed names matching no card
+}
+
+// errBudgetFull stops the result walk once the page of under-budget cards is known to be full.
+var errBudgetFull = errors.New("budget results full")
+
+func handleBudget(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var req BudgetRequest
+        switch r.Method {
+        case http.MethodGet:
+            q := r.URL.Query()
+            req.SimilarRequest = similarRequestFromQuery(q)
+            delete(req.Filters, "max_price_usd")
+            delete(req.Filters, "include_unpriced")
+            req.MaxPriceUSD, _ = strconv.ParseFloat(q.Get("max_price_usd"), 64)
+            req.IncludeUnpriced = q.Get("include_unpriced") == "1"
+        case http.MethodPost:
+            if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+                writeError(w, r, &httpError{http.StatusBadRequest, "bad request: " + err.Error()})
+                return
+            }
+        default:
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+        defer cancel()
+        resp, sq, err := runBudget(ctx, cli, req)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        sq.seedHeaders(w.Header())
+        writeJSON(w, resp)
+    }
+}
+
+// runBudget runs the /similar search overfetchFactor times wider, since many of the nearest
+// cards may be over the price cap, and keeps, in rank order,
+// the first K cards priced at or under the cap (plus unpriced ones if requested). Offset
+// counts under-budget cards, so it is applied here rather than by the search.
+func runBudget(ctx context.Context, cli *client.Client, req BudgetRequest) (*BudgetResponse, *similarQuery, error) {
+    if !(req.MaxPriceUSD > 0) || math.IsInf(req.MaxPriceUSD, 0) {
+        return nil, nil, &httpError{http.StatusBadRequest, "max_price_usd must be a positive number"}
+    }
+    if req.Diversity != 0 {
+        // MMR over the widened window would compare thousands of vectors per pick.
+        return nil, nil, &httpError{http.StatusBadRequest, "diversity is not supported by /similar/budget"}
+    }
+    sq, err := prepareSimilar(ctx, cli, req.SimilarRequest)
+    if err != nil {
+        return nil, nil, err
+    }
+    k, skip := sq.req.K, sq.req.Offset
+    sq.req.K, sq.req.Offset = overfetch(skip+k), 0
+    resp := &BudgetResponse{Results: []CardResult{}, Unresolved: sq.missing}
+    err = sq.run(ctx, cli, func(c CardResult) error {
+        price, err := strconv.ParseFloat(c.PriceUSD, 64)
+        switch {
+        case (c.PriceUSD == "" || err != nil) && !req.IncludeUnpriced:
+            return nil
+        case err == nil && price > req.MaxPriceUSD:
+            return nil
+        case skip > 0:
+            skip--
+            return nil
+        case len(resp.Results) == k:
+            resp.HasMore = true
+            return errBudgetFull
+        case c.PriceUSD == "" || err != nil:
+            resp.Unpriced++
+        default:
+            resp.TotalPriceUSD += price
+        }
+        resp.Results = append(resp.Results, c)
+        return nil
+    })
+    if err != nil && !errors.Is(err, errBudgetFull) {
+        return nil, nil, err
+    }
+    resp.TotalPriceUSD = math.Round(resp.TotalPriceUSD*100) / 100
+    return resp, sq, nil
+}
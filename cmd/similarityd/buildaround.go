@@ -0,0 +1,99 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// BuildAroundRequest names a commander to find on-identity suggestions for.
+type BuildAroundRequest struct {
+    Commander string `json:"commander"`
+    K         int    `json:"k"`
+}
+
+// BuildAroundResponse echoes the resolved commander and its color identity alongside the suggestions.
+type BuildAroundResponse struct {
+    Commander     string       `json:"commander"`
+    ColorIdentity []string     `json:"color_identity"`
+    Results       []CardResult `json:"results"`
+}
+
+// basicsSlack over-fetches enough to cover basic lands (incl. snow basics and Wastes)
+// that are dropped after the search.
+const basicsSlack = 12
+
+func handleBuildAround(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var req BuildAroundRequest
+        switch r.Method {
+        case http.MethodGet:
+            req.Commander = r.URL.Query().Get("commander")
+            req.K, _ = strconv.Atoi(r.URL.Query().Get("k"))
+        case http.MethodPost:
+            if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+                writeError(w, r, &httpError{http.StatusBadRequest, "bad request: " + err.Error()})
+                return
+            }
+        default:
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+        defer cancel()
+        resp, err := runBuildAround(ctx, cli, req)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        writeJSON(w, resp)
+    }
+}
+
+// runBuildAround looks up the commander's color identity and vector, then runs a nearVector
+// search restricted to that identity, excluding the commander and basic lands.
+func runBuildAround(ctx context.Context, cli *client.Client, req BuildAroundRequest) (*BuildAroundResponse, error) {
+    name := strings.TrimSpace(req.Commander)
+    if name == "" { return nil, &httpError{http.StatusBadRequest, "commander required"} }
+    k, err := resolveK(req.K)
+    if err != nil { return nil, err }
+    req.K = k
+
+    found, err := cli.GetCardsByNames(ctx, []string{name})
+    if err != nil { return nil, err }
+    cmdr, ok := found[strings.ToLower(name)]
+    if !ok { return nil, &httpError{http.StatusNotFound, "commander not found: " + name} }
+    vec, _, err := cli.FetchVectorByScryfallID(ctx, cmdr.ScryfallID)
+    if err != nil { return nil, err }
+    if len(vec) == 0 { return nil, &httpError{http.StatusNotFound, "no vector for commander: " + cmdr.Name} }
+
+    cards, err := cli.SearchNearVectorFiltered(ctx, vec, req.K+basicsSlack, client.WithinIdentity(cmdr.ColorID))
+    if err != nil { return nil, err }
+    resp := &BuildAroundResponse{Commander: cmdr.Name, ColorIdentity: normalizeColors(cmdr.ColorID), Results: []CardResult{}}
+    for _, c := range cards {
+        if c.ID == cmdr.ID || strings.EqualFold(c.Name, cmdr.Name) || isBasicLand(c.TypeLine) { continue }
+        resp.Results = append(resp.Results, toCardResult(c))
+        if len(resp.Results) == req.K { break }
+    }
+    return resp, nil
+}
+
+// normalizeColors returns identity upper-cased in WUBRG order.
+func normalizeColors(identity []string) []string {
+    in := map[string]bool{}
+    for _, c := range identity { in[strings.ToUpper(c)] = true }
+    out := []string{}
+    for _, c := range wubrg {
+        if in[c] { out = append(out, c) }
+    }
+    return out
+}
+
+func isBasicLand(typeLine string) bool {
+    return strings.HasPrefix(typeLine, "Basic Land") || strings.HasPrefix(typeLine, "Basic Snow Land")
+}
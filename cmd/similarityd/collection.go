@@ -0,0 +1,126 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/domano/decktech/pkg/decklist"
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// collectionPrintings caps how many printings of one name a collection update marks.
+const collectionPrintings = 100
+
+// CollectionUpdate is the POST /collection response. Every printing of a resolved name is
+// marked, so Printings may exceed Cards; Failed lists the Scryfall IDs whose update failed.
+type CollectionUpdate struct {
+    Owned      bool     `json:"owned"`
+    Cards      int      `json:"cards"`
+    Printings  int      `json:"printings"`
+    Updated    int      `json:"updated"`
+    Failed     []string `json:"failed,omitempty"`
+    Unresolved []string `json:"unresolved,omitempty"`
+}
+
+// OwnedCard is one owned printing in the GET /collection response.
+type OwnedCard struct {
+    ID          string `json:"id"`
+    Name        string `json:"name"`
+    TypeLine    string `json:"type_line"`
+    ManaCost    string `json:"mana_cost"`
+    Set         string `json:"set"`
+    Rarity      string `json:"rarity"`
+    ImageNormal string `json:"image_normal"`
+    PriceUSD    string `json:"price_usd,omitempty"`
+}
+
+// CollectionList is the GET /collection response, sorted by name.
+type CollectionList struct {
+    Count int         `json:"count"`
+    Cards []OwnedCard `json:"cards"`
+}
+
+// handleCollection lists owned cards on GET and marks the cards of a decklist as owned on POST
+// (?owned=0 unmarks them instead).
+func handleCollection(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        switch r.Method {
+        case http.MethodGet:
+            ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+            defer cancel()
+            cards, err := cli.ListOwned(ctx)
+            if err != nil {
+                writeError(w, r, err)
+                return
+            }
+            out := CollectionList{Count: len(cards), Cards: make([]OwnedCard, len(cards))}
+            for i, c := range cards {
+                out.Cards[i] = OwnedCard{ID: c.ID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, Set: c.Set, Rarity: c.Rarity, ImageNormal: c.ImageNormal, PriceUSD: c.PriceUSD}
+            }
+            sort.SliceStable(out.Cards, func(i, j int) bool { return out.Cards[i].Name < out.Cards[j].Name })
+            writeJSON(w, out)
+        case http.MethodPost:
+            entries, err := decodeAnalyzeRequest(r)
+            if err != nil {
+                writeError(w, r, err)
+                return
+            }
+            ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+            defer cancel()
+            resp, err := updateCollection(ctx, cli, entries, r.URL.Query().Get("owned") != "0")
+            if err != nil {
+                writeError(w, r, err)
+                return
+            }
+            writeJSON(w, resp)
+        default:
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        }
+    }
+}
+
+// updateCollection resolves each decklist name to all its printings and sets their owned flag.
+// Failed updates are reported in the response; only a run where every update failed is an error.
+func updateCollection(ctx context.Context, cli *client.Client, entries []decklist.Entry, owned bool) (*CollectionUpdate, error) {
+    resp := &CollectionUpdate{Owned: owned}
+    var ids []string
+    for _, name := range decklist.Names(entries) {
+        prints, err := cli.ListPrintingsByName(ctx, name, collectionPrintings)
+        if err != nil { return nil, err }
+        if len(prints) == 0 {
+            resp.Unresolved = append(resp.Unresolved, name)
+            continue
+        }
+        resp.Cards++
+        for _, p := range prints { ids = append(ids, p.ID) }
+    }
+    resp.Printings = len(ids)
+    err := cli.SetOwned(ctx, ids, owned)
+    var oe *client.OwnedError
+    switch {
+    case errors.As(err, &oe):
+        resp.Updated = oe.Updated
+        for id := range oe.Failed { resp.Failed = append(resp.Failed, id) }
+        sort.Strings(resp.Failed)
+        if oe.Updated == 0 { return nil, err }
+    case err != nil:
+        return nil, err
+    default:
+        resp.Updated = len(ids)
+    }
+    return resp, nil
+}
+
+// ownedNames returns the lower-cased names of every owned card, for exclude_owned. Names
+// rather than IDs, so other printings of an owned card are dropped too.
+func ownedNames(ctx context.Context, cli *client.Client) ([]string, error) {
+    cards, err := cli.ListOwned(ctx)
+    if err != nil { return nil, err }
+    names := make([]string, len(cards))
+    for i, c := range cards { names[i] = strings.ToLower(c.Name) }
+    return names, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// themeSeeds is how many oracle-text matches a free-text theme is averaged from.
+const themeSeeds = 8
+
+// CommanderSuggestions lists legendary creatures near a theme. Seeds are the cards the theme
+// vector was averaged from: the named cards, or the best oracle-text matches for free text.
+type CommanderSuggestions struct {
+    Theme   string       `json:"theme"`
+    Seeds   []string     `json:"seeds"`
+    Results []CardResult `json:"results"`
+}
+
+// handleCommanders serves GET /commanders?theme=...&k=20.
+func handleCommanders(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet {
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        k, _ := strconv.Atoi(r.URL.Query().Get("k"))
+        ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+        defer cancel()
+        resp, err := runCommanders(ctx, cli, r.URL.Query().Get("theme"), k)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        writeJSON(w, resp)
+    }
+}
+
+// runCommanders resolves the theme to a vector and runs a nearVector search restricted to
+// commander-eligible cards, one result per name, leaving out the seeds.
+func runCommanders(ctx context.Context, cli *client.Client, theme string, k int) (*CommanderSuggestions, error) {
+    theme = strings.TrimSpace(theme)
+    if theme == "" { return nil, &httpError{http.StatusBadRequest, "theme required"} }
+    k, err := resolveK(k)
+    if err != nil { return nil, err }
+    seeds, vectors, err := themeVectors(ctx, cli, theme)
+    if err != nil { return nil, err }
+    if len(vectors) == 0 { return nil, &httpError{http.StatusNotFound, "no cards match theme: " + theme} }
+
+    // Over-fetch for other printings, seeds and back-face legends dropped below.
+    cards, err := cli.SearchNearVectorFiltered(ctx, averageVectors(vectors), overfetch(k)+len(seeds), client.CommanderEligible())
+    if err != nil { return nil, err }
+    skip := map[string]bool{}
+    for _, s := range seeds { skip[strings.ToLower(s)] = true }
+    resp := &CommanderSuggestions{Theme: theme, Seeds: seeds, Results: []CardResult{}}
+    for _, c := range client.DedupeByName(cards) {
+        if skip[strings.ToLower(c.Name)] || !isCommanderType(c.TypeLine) { continue }
+        resp.Results = append(resp.Results, toCardResult(c))
+        if len(resp.Results) == k { break }
+    }
+    return resp, nil
+}
+
+// themeVectors treats theme as comma-separated card names when they all exist, and otherwise
+// as rules text, averaging the cards whose name or oracle text contains it.
+func themeVectors(ctx context.Context, cli *client.Client, theme string) ([]string, [][]float64, error) {
+    sv, err := fetchVectorsForNames(ctx, cli, strings.Split(theme, ","))
+    if err != nil { return nil, nil, err }
+    if len(sv.Missing) == 0 && len(sv.Vectors) > 0 { return sv.Names, sv.Vectors, nil }
+
+    matches, err := cli.SearchCards(ctx, theme, themeSeeds)
+    if err != nil { return nil, nil, err }
+    var names []string
+    var vectors [][]float64
+    for _, c := range matches {
+        vec, _, err := cli.FetchVectorByScryfallID(ctx, c.ScryfallID)
+        if errors.Is(err, client.ErrNoVector) || errors.Is(err, client.ErrCardNotFound) { continue }
+        if err != nil { return nil, nil, err }
+        names, vectors = append(names, c.Name), append(vectors, vec)
+    }
+    return names, vectors, nil
+}
+
+// isCommanderType reports whether the front face of a type line is a legendary creature.
+func isCommanderType(typeLine string) bool {
+    front, _, _ := strings.Cut(typeLine, " // ")
+    return strings.Contains(front, "Legendary") && strings.Contains(front, "Creature")
+}
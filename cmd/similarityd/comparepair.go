@@ -0,0 +1,126 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "slices"
+    "strings"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// PairComparison is the /compare-pair response: how close two cards are in embedding space and
+// what they visibly share, to help judge whether B could stand in for A.
+type PairComparison struct {
+    A              string   `json:"a"`
+    B              string   `json:"b"`
+    Similarity     float64  `json:"similarity"`
+    SharedColors   []string `json:"shared_colors"`
+    SharedKeywords []string `json:"shared_keywords"`
+    SharedTypes    []string `json:"shared_types"`
+    Summary        string   `json:"summary"`
+}
+
+func handleComparePair(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet {
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        a, b := strings.TrimSpace(r.URL.Query().Get("a")), strings.TrimSpace(r.URL.Query().Get("b"))
+        if a == "" || b == "" {
+            writeError(w, r, &httpError{http.StatusBadRequest, "both a and b card names are required"})
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+        defer cancel()
+        out, err := comparePair(ctx, cli, a, b)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        writeJSON(w, out)
+    }
+}
+
+// comparePair fetches both vectors for the cosine similarity and both full cards for the
+// colors, keywords and type words they have in common.
+func comparePair(ctx context.Context, cli *client.Client, a, b string) (*PairComparison, error) {
+    va, _, err := cli.FetchVectorForName(ctx, a)
+    if err != nil { return nil, fmt.Errorf("fetch vector for %q: %w", a, err) }
+    vb, _, err := cli.FetchVectorForName(ctx, b)
+    if err != nil { return nil, fmt.Errorf("fetch vector for %q: %w", b, err) }
+    cards, err := cli.GetCardsByNames(ctx, []string{a, b})
+    if err != nil { return nil, err }
+    ca, ok := cards[strings.ToLower(a)]
+    if !ok { return nil, fmt.Errorf("card %q: %w", a, client.ErrCardNotFound) }
+    cb, ok := cards[strings.ToLower(b)]
+    if !ok { return nil, fmt.Errorf("card %q: %w", b, client.ErrCardNotFound) }
+    out := &PairComparison{
+        A:              ca.Name,
+        B:              cb.Name,
+        Similarity:     cosine(va, vb),
+        SharedColors:   intersect(ca.Colors, cb.Colors),
+        SharedKeywords: intersect(ca.Keywords, cb.Keywords),
+        SharedTypes:    intersect(typeWords(ca.TypeLine), typeWords(cb.TypeLine)),
+    }
+    out.Summary = pairSummary(out)
+    return out, nil
+}
+
+// typeWords splits a type line into its supertypes, types and subtypes, dropping the dash and
+// the face separator of double-faced cards.
+func typeWords(typeLine string) []string {
+    var out []string
+    for _, w := range strings.Fields(typeLine) {
+        if w == "—" || w == "-" || w == "//" { continue }
+        out = append(out, w)
+    }
+    return out
+}
+
+// intersect returns the values of a also in b, compared case-insensitively, in a's order and
+// without duplicates. It never returns nil so the JSON shows an empty list.
+func intersect(a, b []string) []string {
+    out := []string{}
+    for _, v := range a {
+        if slices.ContainsFunc(b, func(w string) bool { return strings.EqualFold(v, w) }) &&
+            !slices.ContainsFunc(out, func(w string) bool { return strings.EqualFold(v, w) }) {
+            out = append(out, v)
+        }
+    }
+    return out
+}
+
+var colorNames = map[string]string{"W": "white", "U": "blue", "B": "black", "R": "red", "G": "green"}
+
+// pairSummary phrases a comparison as one sentence, e.g.
+// "Lightning Bolt and Shock are very similar (0.93); both are red and share the types Instant."
+func pairSummary(p *PairComparison) string {
+    var degree string
+    switch s := p.Similarity; {
+    case s >= 0.9:
+        degree = "very similar"
+    case s >= 0.75:
+        degree = "similar"
+    case s >= 0.5:
+        degree = "somewhat similar"
+    default:
+        degree = "not very similar"
+    }
+    var shared []string
+    if len(p.SharedColors) > 0 {
+        colors := make([]string, len(p.SharedColors))
+        for i, c := range p.SharedColors {
+            if n, ok := colorNames[strings.ToUpper(c)]; ok { colors[i] = n } else { colors[i] = c }
+        }
+        shared = append(shared, "both are "+strings.Join(colors, "/"))
+    }
+    if len(p.SharedTypes) > 0 { shared = append(shared, "share the types "+strings.Join(p.SharedTypes, ", ")) }
+    if len(p.SharedKeywords) > 0 { shared = append(shared, "share the keywords "+strings.Join(p.SharedKeywords, ", ")) }
+    s := fmt.Sprintf("%s and %s are %s (%.2f)", p.A, p.B, degree, p.Similarity)
+    if len(shared) == 0 { return s + "; they share no colors, types or keywords." }
+    return s + "; " + strings.Join(shared, " and ") + "."
+}
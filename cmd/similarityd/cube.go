@@ -0,0 +1,142 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "math/rand"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// maxCubePool caps a generated pool; cubes are usually 360 or 540 cards.
+const maxCubePool = 1000
+
+// CubePoolRequest asks for a pool with per-color (W, U, B, R, G, M for multicolored, C for
+// colorless) and optional per-rarity card counts, drawn from cards legal in Format when set.
+type CubePoolRequest struct {
+    Colors   map[string]int `json:"colors"`
+    Rarities map[string]int `json:"rarities,omitempty"`
+    Format   string         `json:"format,omitempty"`
+    Seed     int64          `json:"seed,omitempty"`
+}
+
+// CubeCard is one card of a generated pool.
+type CubeCard struct {
+    ID          string   `json:"id"`
+    Name        string   `json:"name"`
+    TypeLine    string   `json:"type_line"`
+    ManaCost    string   `json:"mana_cost"`
+    CMC         float64  `json:"cmc"`
+    Colors      []string `json:"colors"`
+    Rarity      string   `json:"rarity"`
+    Set         string   `json:"set"`
+    ImageNormal string   `json:"image_normal"`
+}
+
+// CubeShortfall lists the targets a pool could not meet and by how many cards.
+type CubeShortfall struct {
+    Colors   map[string]int `json:"colors,omitempty"`
+    Rarities map[string]int `json:"rarities,omitempty"`
+}
+
+// CubePoolResponse is the drawn pool. Seed repeats the draw on an unchanged collection; Unmet
+// is set when the collection ran out of cards for some target.
+type CubePoolResponse struct {
+    Seed     int64          `json:"seed"`
+    Size     int            `json:"size"`
+    Count    int            `json:"count"`
+    ByColor  map[string]int `json:"by_color"`
+    ByRarity map[string]int `json:"by_rarity"`
+    Cards    []CubeCard     `json:"cards"`
+    Unmet    *CubeShortfall `json:"unmet,omitempty"`
+}
+
+// handleCubePool serves POST /cube/pool with a CubePoolRequest, or
+// GET /cube/pool?colors=W:45,U:45,...&rarities=common:200,...&format=vintage&seed=1.
+func handleCubePool(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var req CubePoolRequest
+        switch r.Method {
+        case http.MethodGet:
+            var err error
+            if req, err = cubePoolRequestFromQuery(r.URL.Query()); err != nil {
+                writeError(w, r, err)
+                return
+            }
+        case http.MethodPost:
+            if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+                writeError(w, r, &httpError{http.StatusBadRequest, "bad request: " + err.Error()})
+                return
+            }
+        default:
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+        defer cancel()
+        resp, err := runCubePool(ctx, cli, req)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        writeJSON(w, resp)
+    }
+}
+
+// cubePoolRequestFromQuery reads the GET form, where counts are comma-separated key:count pairs.
+func cubePoolRequestFromQuery(q url.Values) (CubePoolRequest, error) {
+    req := CubePoolRequest{Format: q.Get("format")}
+    var err error
+    if req.Colors, err = parseCounts(q.Get("colors")); err != nil { return req, &httpError{http.StatusBadRequest, "colors: " + err.Error()} }
+    if req.Rarities, err = parseCounts(q.Get("rarities")); err != nil { return req, &httpError{http.StatusBadRequest, "rarities: " + err.Error()} }
+    if v := q.Get("seed"); v != "" {
+        if req.Seed, err = strconv.ParseInt(v, 10, 64); err != nil { return req, &httpError{http.StatusBadRequest, "seed must be an integer"} }
+    }
+    return req, nil
+}
+
+// parseCounts reads "W:45,U:45" into a map.
+func parseCounts(s string) (map[string]int, error) {
+    out := map[string]int{}
+    for _, part := range strings.Split(s, ",") {
+        part = strings.TrimSpace(part)
+        if part == "" { continue }
+        k, v, ok := strings.Cut(part, ":")
+        n, err := strconv.Atoi(strings.TrimSpace(v))
+        if !ok || err != nil { return nil, fmt.Errorf("%q is not key:count", part) }
+        out[strings.TrimSpace(k)] += n
+    }
+    return out, nil
+}
+
+// runCubePool validates the request and draws the pool with Client.SampleCards.
+func runCubePool(ctx context.Context, cli *client.Client, req CubePoolRequest) (*CubePoolResponse, error) {
+    spec := client.SampleSpec{Colors: map[string]int{}, Rarities: map[string]int{}, Seed: req.Seed}
+    for k, n := range req.Colors { spec.Colors[strings.ToUpper(strings.TrimSpace(k))] += n }
+    for k, n := range req.Rarities { spec.Rarities[strings.ToLower(strings.TrimSpace(k))] += n }
+    if err := spec.Validate(); err != nil { return nil, &httpError{http.StatusBadRequest, err.Error()} }
+    size := spec.Size()
+    if size == 0 { return nil, &httpError{http.StatusBadRequest, "colors required, e.g. {\"W\": 45, \"U\": 45}"} }
+    if size > maxCubePool { return nil, &httpError{http.StatusBadRequest, fmt.Sprintf("pool size %d exceeds %d", size, maxCubePool)} }
+    if f := strings.TrimSpace(req.Format); f != "" { spec.Where = client.LegalIn(f) }
+    if spec.Seed == 0 { spec.Seed = rand.Int63n(1<<31) + 1 }
+
+    cards, err := cli.SampleCards(ctx, spec)
+    if err != nil { return nil, err }
+    resp := &CubePoolResponse{Seed: spec.Seed, Size: size, Count: len(cards), ByColor: map[string]int{}, ByRarity: map[string]int{}, Cards: make([]CubeCard, len(cards))}
+    for i, c := range cards {
+        resp.Cards[i] = CubeCard{ID: c.ID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, CMC: c.CMC, Colors: c.Colors, Rarity: c.Rarity, Set: c.Set, ImageNormal: c.ImageNormal}
+        resp.ByColor[client.ColorBucket(c.Colors)]++
+        resp.ByRarity[c.Rarity]++
+    }
+    if colors, rarities := spec.Shortfall(cards); len(colors) > 0 || len(rarities) > 0 {
+        resp.Unmet = &CubeShortfall{Colors: colors, Rarities: rarities}
+    }
+    return resp, nil
+}
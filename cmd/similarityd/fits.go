@@ -0,0 +1,35 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "strings"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// handleFits answers /fits?commander=Name&card=Name with whether the card's color identity is
+// within the commander's, listing the offending colors when it is not.
+func handleFits(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet {
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        q := r.URL.Query()
+        commander, card := strings.TrimSpace(q.Get("commander")), strings.TrimSpace(q.Get("card"))
+        if commander == "" || card == "" {
+            writeError(w, r, &httpError{http.StatusBadRequest, "both commander and card names are required"})
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+        defer cancel()
+        fit, err := cli.FitsIdentity(ctx, commander, card)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        writeJSON(w, fit)
+    }
+}
@@ -4,37 +4,118 @@ import (
     "context"
     "encoding/json"
     "errors"
+    "flag"
     "fmt"
-    "log"
+    "log/slog"
     "math"
     "net/http"
+    "net/url"
     "os"
     "os/signal"
+    "sort"
+    "strconv"
     "strings"
     "syscall"
     "time"
 
+    "github.com/domano/decktech/pkg/logging"
+    "github.com/domano/decktech/pkg/requestid"
+    "github.com/domano/decktech/pkg/server"
     client "github.com/domano/decktech/pkg/weaviateclient"
 )
 
 type SimilarRequest struct {
-    Names   []string               `json:"names"`
-    K       int                    `json:"k"`
-    Filters map[string]interface{} `json:"filters,omitempty"`
+    Names        []string               `json:"names"`
+    K            int                    `json:"k"`
+    Filters      map[string]interface{} `json:"filters,omitempty"`
+    DedupeByName bool                   `json:"dedupe_by_name,omitempty"`
+    // ExcludeNames and ExcludeIDs drop cards the caller already has (e.g. a collection) from the results.
+    ExcludeNames []string `json:"exclude_names,omitempty"`
+    ExcludeIDs   []string `json:"exclude_ids,omitempty"`
+    // ExcludeOwned drops every card marked owned through /collection.
+    ExcludeOwned bool `json:"exclude_owned,omitempty"`
+    // IncludeVectors returns each result's embedding; this multiplies the response size.
+    IncludeVectors bool `json:"include_vectors,omitempty"`
+    // MinSimilarity drops results below this similarity (clamped to [0,1] unless METRIC=dot,
+    // where similarities are unbounded); 0 means no threshold. With a high threshold fewer
+    // than K results may come back.
+    MinSimilarity float64 `json:"min_similarity,omitempty"`
+    // Ef raises the HNSW candidate list for this search: higher recall, slower queries.
+    // 0 uses DEFAULT_EF; at most MAX_EF.
+    Ef int `json:"ef,omitempty"`
+    // Autocut stops at the Nth natural gap in the result distances instead of always returning
+    // K; K is then an upper bound. 0 means off.
+    Autocut int `json:"autocut,omitempty"`
+    // Offset skips this many results (after exclusions) for paging: the search fetches
+    // offset+K and returns the window, so deep pages cost as much as one large K.
+    Offset int `json:"offset,omitempty"`
+    // Diversity (0..1) reranks the results by Maximal Marginal Relevance, trading similarity
+    // to the seeds for variety among the results; 0 keeps plain similarity order.
+    Diversity float64 `json:"diversity,omitempty"`
+}
+
+// SimilarResponse is the /similar answer with ?envelope=1: the results plus the seed names
+// the search went ahead without, which the default bare array only reports in headers.
+type SimilarResponse struct {
+    Results    []CardResult `json:"results"`
+    Unresolved []string     `json:"unresolved"` // seed names matching no card; empty when all resolved
+    HasMore    bool         `json:"has_more"`
+}
+
+// Config is the effective configuration reported by /config.
+type Config struct {
+    WeaviateURL string `json:"weaviate_url"`
+    DefaultK    int    `json:"default_k"`
+    MaxK        int    `json:"max_k"`
+    Metric      string `json:"metric"`
+    DefaultEf   int    `json:"default_ef"`
+    MaxEf       int    `json:"max_ef"`
+    Overfetch   int    `json:"overfetch_factor"`
+    Timeouts    map[client.Operation]string `json:"weaviate_timeouts"`
+    EmbedURL    string `json:"embed_url,omitempty"`
 }
 
 type CardResult struct {
-    ID            string   `json:"id"`
-    Name          string   `json:"name"`
-    TypeLine      string   `json:"type_line"`
-    ManaCost      string   `json:"mana_cost"`
-    OracleText    string   `json:"oracle_text"`
-    Colors        []string `json:"colors"`
-    ImageNormal   string   `json:"image_normal"`
-    Distance      float64  `json:"distance"`
-    Similarity    float64  `json:"similarity"`
+    ID            string    `json:"id"`
+    Name          string    `json:"name"`
+    TypeLine      string    `json:"type_line"`
+    ManaCost      string    `json:"mana_cost"`
+    OracleText    string    `json:"oracle_text"`
+    Colors        []string  `json:"colors"`
+    ImageNormal   string    `json:"image_normal"`
+    Distance      float64   `json:"distance"`
+    Similarity    float64   `json:"similarity"`
+    Score         float64   `json:"score,omitempty"` // bm25 score from /similar/text; no distance then
+    Printings     int       `json:"printings,omitempty"`
+    PriceUSD      string    `json:"price_usd,omitempty"` // Scryfall's USD price; empty when unknown
+    Vector        []float64 `json:"vector,omitempty"`
 }
 
+// defaultK and maxK bound the result count; overridable via DEFAULT_K / MAX_K.
+var (
+    defaultK = 10
+    maxK     = 500
+)
+
+// maxWindow caps offset+k at Weaviate's default QUERY_MAXIMUM_RESULTS, beyond which the
+// over-fetching search would fail.
+const maxWindow = 10000
+
+// overfetchFactor is how many times the wanted results a search fetches when filtering after
+// the search (min_similarity, dedupe_by_name, diversity, price caps) may thin them out;
+// overridable via OVERFETCH_FACTOR.
+var overfetchFactor = 3
+
+// overfetch is the Weaviate limit for n wanted results that post-filtering may thin out.
+func overfetch(n int) int { return min(n*overfetchFactor, maxWindow) }
+
+// defaultEf and maxEf bound the per-request HNSW ef; overridable via DEFAULT_EF / MAX_EF.
+// A default of 0 leaves ef to the Card class's vector index configuration.
+var (
+    defaultEf = 0
+    maxEf     = 1000
+)
+
 type graphQLResponse struct {
     Data   json.RawMessage   `json:"data"`
     Errors []graphQLError    `json:"errors"`
@@ -45,97 +126,121 @@ type graphQLError struct {
 }
 
 func main() {
+    tlsCert := flag.String("tls-cert", os.Getenv("TLS_CERT"), "TLS certificate file (enables HTTPS with -tls-key)")
+    tlsKey := flag.String("tls-key", os.Getenv("TLS_KEY"), "TLS private key file")
+    cfgPath := flag.String("config", "", "JSON config file; env vars override its settings")
+    flag.Parse()
+    applied, cfgErr := server.ApplyConfig(*cfgPath)
+    logging.Setup()
+    if cfgErr != nil {
+        slog.Error("invalid config file", "path", *cfgPath, "err", cfgErr)
+        os.Exit(1)
+    }
+    if *cfgPath != "" { slog.Info("config file loaded", "path", *cfgPath, "applied", applied) }
+    tlsCfg, err := server.LoadTLS(*tlsCert, *tlsKey)
+    if err != nil {
+        slog.Error("invalid TLS configuration", "err", err)
+        os.Exit(1)
+    }
+    if err := checkOpenAPI(openapiSpec); err != nil {
+        slog.Error("embedded OpenAPI spec is invalid or out of date", "err", err)
+        os.Exit(1)
+    }
     weaviateURL := os.Getenv("WEAVIATE_URL")
     if weaviateURL == "" {
         weaviateURL = "http://localhost:8080"
     }
+    cli := client.NewClient(weaviateURL, client.RateLimitFromEnv(), client.TimeoutsFromEnv(), client.WithLogger(slog.Default()))
+    maxK = envInt("MAX_K", maxK)
+    defaultK = min(envInt("DEFAULT_K", defaultK), maxK)
+    maxEf = envInt("MAX_EF", maxEf)
+    defaultEf = min(envInt("DEFAULT_EF", defaultEf), maxEf)
+    overfetchFactor = envInt("OVERFETCH_FACTOR", overfetchFactor)
+    metric = metricFromEnv()
+    textEmbedder := textEmbedderFromEnv()
 
     mux := http.NewServeMux()
     mux.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
-        _ = json.NewEncoder(w).Encode(map[string]string{"weaviate_url": weaviateURL})
+        timeouts := map[client.Operation]string{}
+        for _, op := range client.Operations { timeouts[op] = cli.Timeout(op).String() }
+        writeJSON(w, Config{WeaviateURL: weaviateURL, DefaultK: defaultK, MaxK: maxK, Metric: metric, DefaultEf: defaultEf, MaxEf: maxEf, Overfetch: overfetchFactor, Timeouts: timeouts, EmbedURL: os.Getenv("EMBED_URL")})
     })
+    mux.HandleFunc("/openapi.json", handleOpenAPI)
     mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
         w.WriteHeader(http.StatusOK)
         _, _ = w.Write([]byte("ok"))
     })
+    mux.HandleFunc("/readyz", handleReadyz(cli))
     mux.HandleFunc("/similar", func(w http.ResponseWriter, r *http.Request) {
-        if r.Method != http.MethodPost {
-            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-            return
-        }
         var req SimilarRequest
-        dec := json.NewDecoder(r.Body)
-        if err := dec.Decode(&req); err != nil {
-            log.Printf("/similar decode error: %v", err)
-            http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
-            return
-        }
-        if len(req.Names) == 0 {
-            log.Printf("/similar missing names")
-            http.Error(w, "names required", http.StatusBadRequest)
+        switch r.Method {
+        case http.MethodGet:
+            req = similarRequestFromQuery(r.URL.Query())
+        case http.MethodPost:
+            dec := json.NewDecoder(r.Body)
+            if err := dec.Decode(&req); err != nil {
+                slog.WarnContext(r.Context(), "decode error", "path", r.URL.Path, "err", err)
+                http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+                return
+            }
+        default:
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
             return
         }
-        if req.K <= 0 {
-            req.K = 10
-        }
 
         ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
         defer cancel()
 
-        cli := client.NewClient(weaviateURL)
-        vectors, ids, err := fetchVectorsForNames(ctx, cli, req.Names)
-        if err != nil {
-            http.Error(w, err.Error(), http.StatusBadGateway)
-            return
-        }
-        if len(vectors) == 0 {
-            http.Error(w, "no vectors found for input names", http.StatusNotFound)
+        if r.URL.Query().Get("stream") == "ndjson" {
+            streamSimilar(ctx, w, r, cli, req)
             return
         }
-        qvec := averageVectors(vectors)
-
-        resultsC, err := cli.SearchNearVector(ctx, qvec, req.K)
+        filtered, sq, err := runSimilar(ctx, cli, req)
         if err != nil {
-            log.Printf("/similar search error: %v", err)
-            http.Error(w, err.Error(), http.StatusBadGateway)
+            writeError(w, r, err)
             return
         }
+        sq.seedHeaders(w.Header())
+        // With autocut (or min_similarity) fewer than k may come back; say how many without a body change.
+        w.Header().Set("X-Result-Count", strconv.Itoa(len(filtered)))
+        w.Header().Set("X-Has-More", strconv.FormatBool(sq.more))
 
-        // Exclude input IDs from results
-        idset := map[string]struct{}{}
-        for _, id := range ids {
-            idset[id] = struct{}{}
+        if r.Method == http.MethodGet {
+            // Results are deterministic for a given index, so GETs may be cached by proxies/CDNs.
+            w.Header().Set("Cache-Control", "public, max-age=300")
         }
-        filtered := make([]CardResult, 0, len(resultsC))
-        for _, c := range resultsC {
-            if _, ok := idset[c.ID]; ok {
-                continue
-            }
-            filtered = append(filtered, CardResult{
-                ID:          c.ID,
-                Name:        c.Name,
-                TypeLine:    c.TypeLine,
-                ManaCost:    c.ManaCost,
-                OracleText:  c.OracleText,
-                Colors:      c.Colors,
-                ImageNormal: c.ImageNormal,
-                Distance:    c.Distance,
-                Similarity:  c.Similarity,
-            })
-        }
-
-        w.Header().Set("Content-Type", "application/json")
-        enc := json.NewEncoder(w)
-        enc.SetIndent("", "  ")
-        _ = enc.Encode(filtered)
+        if r.URL.Query().Get("envelope") == "1" {
+            unresolved := sq.missing
+            if unresolved == nil { unresolved = []string{} }
+            writeJSON(w, SimilarResponse{Results: filtered, Unresolved: unresolved, HasMore: sq.more})
+            return
+        }
+        writeJSON(w, filtered)
     })
+    mux.HandleFunc("/similar/budget", handleBudget(cli))
+    mux.HandleFunc("/similar/text", handleTextSimilar(cli, textEmbedder))
+    mux.HandleFunc("/analyze/colors", handleAnalyzeColors(cli))
+    mux.HandleFunc("/analyze/curve", handleAnalyzeCurve(cli))
+    mux.HandleFunc("/build-around", handleBuildAround(cli))
+    mux.HandleFunc("/fits", handleFits(cli))
+    mux.HandleFunc("/commanders", handleCommanders(cli))
+    mux.HandleFunc("/staples", handleStaples(cli))
+    mux.HandleFunc("/cube/pool", handleCubePool(cli))
+    mux.HandleFunc("/neighbors", handleNeighbors(cli))
+    mux.HandleFunc("/synergy", handleSynergy(cli))
+    mux.HandleFunc("/compare-pair", handleComparePair(cli))
+    mux.HandleFunc("/collection", handleCollection(cli))
+    if envBool("ENABLE_VECTORS_ENDPOINT") {
+        mux.HandleFunc("/vectors", handleVectors(cli))
+    }
 
-    srv := &http.Server{Addr: ":8088", Handler: mux}
+    srv := &http.Server{Addr: server.ListenAddr(":8088"), Handler: requestid.Middleware(logging.Requests(server.CORS(server.CORSOriginsFromEnv(), server.Gzip(mux)))), TLSConfig: tlsCfg}
 
     go func() {
-        log.Printf("similarity service listening on %s (WEAVIATE_URL=%s)", srv.Addr, weaviateURL)
-        if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-            log.Fatalf("server error: %v", err)
+        slog.Info("similarity service listening", "addr", srv.Addr, "weaviate_url", weaviateURL, "tls", tlsCfg != nil)
+        if err := server.ListenAndServe(srv); err != nil && !errors.Is(err, http.ErrServerClosed) {
+            slog.Error("server error", "err", err)
+            os.Exit(1)
         }
     }()
 
@@ -149,25 +254,371 @@ func main() {
     _ = srv.Shutdown(ctx)
 }
 
-func fetchVectorsForNames(ctx context.Context, cli *client.Client, names []string) ([][]float64, []string, error) {
-    vectors := make([][]float64, 0, len(names))
-    ids := make([]string, 0, len(names))
+// httpError carries the status code a handler should answer with.
+type httpError struct {
+    status int
+    msg    string
+}
+
+func (e *httpError) Error() string { return e.msg }
+
+// writeError maps err onto an HTTP error response (502 unless it is an *httpError).
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+    status := http.StatusBadGateway
+    var he *httpError
+    if errors.As(err, &he) { status = he.status }
+    if errors.Is(err, client.ErrNoVector) || errors.Is(err, client.ErrCardNotFound) { status = http.StatusNotFound }
+    if status >= 500 {
+        slog.ErrorContext(r.Context(), "request failed", "path", r.URL.Path, "status", status, "err", err)
+    } else {
+        slog.WarnContext(r.Context(), "bad request", "path", r.URL.Path, "status", status, "err", err)
+    }
+    msg := err.Error()
+    if id := requestid.FromContext(r.Context()); id != "" && !strings.Contains(msg, id) {
+        msg += " (request_id=" + id + ")"
+    }
+    http.Error(w, msg, status)
+}
+
+// resolveK applies defaultK to unset/non-positive k and rejects k above maxK.
+func resolveK(k int) (int, error) {
+    if k <= 0 { return defaultK, nil }
+    if k > maxK { return 0, &httpError{http.StatusBadRequest, fmt.Sprintf("k=%d exceeds the maximum of %d", k, maxK)} }
+    return k, nil
+}
+
+// resolveEf applies defaultEf to an unset ef and rejects negative values or ones above maxEf.
+func resolveEf(ef int) (int, error) {
+    if ef == 0 { return defaultEf, nil }
+    if ef < 0 || ef > maxEf {
+        return 0, &httpError{http.StatusBadRequest, fmt.Sprintf("ef must be between 1 and %d", maxEf)}
+    }
+    return ef, nil
+}
+
+// envInt reads a positive int from the environment, falling back to def when unset or invalid.
+func envInt(key string, def int) int {
+    v := strings.TrimSpace(os.Getenv(key))
+    if v == "" { return def }
+    n, err := strconv.Atoi(v)
+    if err != nil || n <= 0 {
+        slog.Warn("ignoring invalid env value", "key", key, "value", v)
+        return def
+    }
+    return n
+}
+
+// envBool reads a boolean flag ("1", "true", ...) from the environment; unset or invalid is false.
+func envBool(key string) bool {
+    v := strings.TrimSpace(os.Getenv(key))
+    if v == "" { return false }
+    b, err := strconv.ParseBool(v)
+    if err != nil { slog.Warn("ignoring invalid env value", "key", key, "value", v) }
+    return b
+}
+
+// similarRequestFromQuery maps `?names=a,b,c&k=10&exclude_names=d,e` onto a SimilarRequest.
+// Any other non-empty query params are passed through as string filters, which
+// prepareSimilar checks (see similarFilter).
+func similarRequestFromQuery(q url.Values) SimilarRequest {
+    list := func(key string) []string {
+        var out []string
+        for _, v := range q[key] {
+            for _, n := range strings.Split(v, ",") {
+                if n = strings.TrimSpace(n); n != "" { out = append(out, n) }
+            }
+        }
+        return out
+    }
+    var req SimilarRequest
+    req.Names = list("names")
+    req.ExcludeNames = list("exclude_names")
+    req.ExcludeIDs = list("exclude_ids")
+    req.K, _ = strconv.Atoi(q.Get("k"))
+    if v := q.Get("offset"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil { n = -1 } // let prepareSimilar reject junk
+        req.Offset = n
+    }
+    req.DedupeByName = q.Get("dedupe_by_name") == "1"
+    req.ExcludeOwned = q.Get("exclude_owned") == "1"
+    req.IncludeVectors = q.Get("include_vectors") == "1"
+    req.MinSimilarity, _ = strconv.ParseFloat(q.Get("min_similarity"), 64)
+    if v := q.Get("diversity"); v != "" {
+        d, err := strconv.ParseFloat(v, 64)
+        if err != nil { d = -1 } // let prepareSimilar reject junk
+        req.Diversity = d
+    }
+    if v := q.Get("ef"); v != "" {
+        if req.Ef, _ = strconv.Atoi(v); req.Ef == 0 { req.Ef = -1 } // let prepareSimilar reject junk
+    }
+    if v := q.Get("autocut"); v != "" {
+        if req.Autocut, _ = strconv.Atoi(v); req.Autocut == 0 { req.Autocut = -1 }
+    }
+    for key := range q {
+        switch key {
+        case "names", "k", "offset", "dedupe_by_name", "exclude_names", "exclude_ids", "exclude_owned", "include_vectors", "min_similarity", "ef", "autocut", "diversity", "stream", "envelope":
+            continue
+        }
+        if q.Get(key) == "" { continue }
+        if req.Filters == nil { req.Filters = map[string]interface{}{} }
+        req.Filters[key] = q.Get(key)
+    }
+    return req
+}
+
+// runSimilar resolves the seed names, averages their vectors, runs the nearVector search,
+// and returns results with the seeds and any excluded names/IDs removed, over-fetching so that
+// up to K results remain. The returned query reports whether a further page has results and
+// which seeds were skipped or unresolved. Shared by the GET and POST handlers.
+func runSimilar(ctx context.Context, cli *client.Client, req SimilarRequest) ([]CardResult, *similarQuery, error) {
+    sq, err := prepareSimilar(ctx, cli, req)
+    if err != nil {
+        return nil, nil, err
+    }
+    filtered := make([]CardResult, 0, sq.req.K)
+    err = sq.run(ctx, cli, func(c CardResult) error {
+        filtered = append(filtered, c)
+        return nil
+    })
+    if err != nil {
+        return nil, nil, err
+    }
+    return filtered, sq, nil
+}
+
+// similarQuery is a validated /similar request with its seeds resolved, ready to search.
+type similarQuery struct {
+    req     SimilarRequest
+    qvec    []float64
+    idset   map[string]struct{}
+    nameset map[string]struct{}
+    skipped []string            // seeds without an embedding, left out of the centroid
+    missing []string            // seeds matching no card
+    where   *client.WhereFilter // the request's filters, applied inside the nearVector search
+    more    bool                // set by run when results continue past the returned window
+}
+
+// seedHeaders lists the seeds the search went ahead without, one X-Skipped-Card (no
+// embedding) or X-Unresolved-Card (no such card) header each, since /similar answers with a
+// bare array unless asked for an envelope.
+func (sq *similarQuery) seedHeaders(h http.Header) {
+    for _, name := range sq.skipped { h.Add("X-Skipped-Card", name) }
+    for _, name := range sq.missing { h.Add("X-Unresolved-Card", name) }
+}
+
+// prepareSimilar validates req and resolves the seed vectors. Its errors carry the HTTP
+// status to answer with, so callers can still fail the request before writing a body.
+func prepareSimilar(ctx context.Context, cli *client.Client, req SimilarRequest) (*similarQuery, error) {
+    if len(req.Names) == 0 {
+        return nil, &httpError{http.StatusBadRequest, "names required"}
+    }
+    k, err := resolveK(req.K)
+    if err != nil {
+        return nil, err
+    }
+    req.K = k
+    if req.Ef, err = resolveEf(req.Ef); err != nil {
+        return nil, err
+    }
+    if req.Autocut < 0 {
+        return nil, &httpError{http.StatusBadRequest, "autocut must be at least 1"}
+    }
+    if req.Offset < 0 {
+        return nil, &httpError{http.StatusBadRequest, "offset must be a non-negative integer"}
+    }
+    if req.Offset+req.K > maxWindow {
+        return nil, &httpError{http.StatusBadRequest, fmt.Sprintf("offset+k must be at most %d", maxWindow)}
+    }
+    if !(req.Diversity >= 0 && req.Diversity <= 1) { // also rejects NaN
+        return nil, &httpError{http.StatusBadRequest, "diversity must be between 0 and 1"}
+    }
+    if req.Diversity > 0 && req.Offset+req.K > mmrMaxWindow {
+        return nil, &httpError{http.StatusBadRequest, fmt.Sprintf("offset+k must be at most %d with diversity", mmrMaxWindow)}
+    }
+    if boundedSimilarity() { req.MinSimilarity = min(1, max(0, req.MinSimilarity)) }
+    where, err := similarFilter(req.Filters)
+    if err != nil {
+        return nil, err
+    }
+
+    sv, err := fetchVectorsForNames(ctx, cli, req.Names)
+    if err != nil {
+        return nil, err
+    }
+    // A typo in one seed shouldn't sink the blend: go on with the seeds that resolved and
+    // report the rest; only a request where none resolve fails.
+    vectors, skipped := sv.Vectors, sv.NoVec
+    ids := append(sv.IDs, sv.NoVecID...) // seeds without vectors are still excluded from results
+    if len(vectors) == 0 {
+        msg := "no vectors found for input names"
+        if len(sv.Missing) > 0 { msg += " (no such card: " + strings.Join(sv.Missing, ", ") + ")" }
+        if len(skipped) > 0 { msg += " (without embeddings: " + strings.Join(skipped, ", ") + ")" }
+        return nil, &httpError{http.StatusNotFound, msg}
+    }
+    if len(sv.Missing) > 0 { slog.WarnContext(ctx, "skipping unresolved seeds", "names", sv.Missing) }
+
+    // Exclude seeds, excluded IDs and excluded names (normalized like seeds, matched case-insensitively).
+    sq := &similarQuery{req: req, qvec: averageVectors(vectors), idset: map[string]struct{}{}, nameset: map[string]struct{}{}, skipped: skipped, missing: sv.Missing, where: where}
+    for _, id := range ids { sq.idset[id] = struct{}{} }
+    for _, id := range req.ExcludeIDs {
+        if id = strings.TrimSpace(id); id != "" { sq.idset[id] = struct{}{} }
+    }
+    for _, n := range req.ExcludeNames {
+        if n = strings.TrimSpace(n); n != "" { sq.nameset[strings.ToLower(n)] = struct{}{} }
+    }
+    if req.ExcludeOwned {
+        owned, err := ownedNames(ctx, cli)
+        if err != nil {
+            return nil, err
+        }
+        for _, n := range owned { sq.nameset[n] = struct{}{} }
+    }
+    return sq, nil
+}
+
+// similarFilter turns a request's filters into a where filter. Only "format" is supported:
+// cards legal in it, e.g. "modern". Any other key is rejected rather than silently ignored.
+func similarFilter(filters map[string]interface{}) (*client.WhereFilter, error) {
+    var ops []*client.WhereFilter
+    for key, v := range filters {
+        switch key {
+        case "format":
+            f, _ := v.(string)
+            if f = strings.TrimSpace(f); f == "" {
+                return nil, &httpError{http.StatusBadRequest, "filter format must be a format name, e.g. modern"}
+            }
+            ops = append(ops, client.LegalIn(f))
+        default:
+            return nil, &httpError{http.StatusBadRequest, fmt.Sprintf("unsupported filter %q (supported: format)", key)}
+        }
+    }
+    return client.And(ops...), nil
+}
+
+// run searches and passes up to K results after the first Offset to emit in rank order,
+// stopping at emit's first error. Exclusions apply before the offset, so pages never shift
+// with them; sq.more reports whether another result followed the window. With Diversity the
+// rank is the MMR order over the over-fetched candidates (see rerankMMR).
+func (sq *similarQuery) run(ctx context.Context, cli *client.Client, emit func(CardResult) error) error {
+    req := sq.req
+    diverse := req.Diversity > 0
+    // over-fetch so exclusions still leave offset+K results, plus one to tell whether more follow
+    limit := req.Offset + req.K + 1 + len(sq.idset) + len(sq.nameset)
+    if req.MinSimilarity != 0 || req.DedupeByName || diverse {
+        // nearVector can't threshold server-side, collapsing printings shrinks the list, and
+        // MMR needs candidates to choose from, so fetch more and trim below
+        limit = overfetch(limit)
+    }
+    limit = min(limit, maxWindow)
+    search := cli.SearchNearVectorFiltered
+    if req.IncludeVectors || diverse { search = cli.SearchNearVectorWithVectors } // MMR compares the candidates' vectors
+    if req.Ef > 0 { ctx = client.WithEf(ctx, req.Ef) }
+    where := sq.where
+    if req.Autocut > 0 {
+        ctx = client.WithAutocut(ctx, req.Autocut)
+        // Exclude seeds and excluded IDs in Weaviate: a seed sits at distance ~0, so left in the
+        // results it would form the first group on its own and autocut would stop right after it.
+        ids := make([]string, 0, len(sq.idset))
+        for id := range sq.idset { ids = append(ids, id) }
+        sort.Strings(ids) // stable query text
+        ops := make([]*client.WhereFilter, len(ids))
+        for i, id := range ids { ops[i] = client.TextFilter("NotEqual", "id", id) }
+        where = client.And(append(ops, sq.where)...)
+    }
+    resultsC, err := search(ctx, sq.qvec, limit, where)
+    if err != nil {
+        return err
+    }
+    if req.DedupeByName {
+        resultsC = client.DedupeByName(resultsC)
+    }
+
+    cands := resultsC[:0]
+    for _, c := range resultsC {
+        if _, ok := sq.idset[c.ID]; ok {
+            continue
+        }
+        if _, ok := sq.nameset[strings.ToLower(c.Name)]; ok {
+            continue
+        }
+        if req.MinSimilarity != 0 && similarity(c.Distance) < req.MinSimilarity {
+            break // results are ordered by distance, so the rest are below the threshold too
+        }
+        cands = append(cands, c)
+    }
+    if diverse { cands = rerankMMR(cands, sq.qvec, req.Diversity, req.Offset+req.K+1) }
+
+    n, skip := 0, req.Offset
+    for _, c := range cands {
+        if skip > 0 {
+            skip--
+            continue
+        }
+        if n == req.K {
+            sq.more = true
+            break
+        }
+        if !req.IncludeVectors { c.Vector = nil }
+        if err := emit(toCardResult(c)); err != nil {
+            return err
+        }
+        n++
+    }
+    return nil
+}
+
+func toCardResult(c client.Card) CardResult {
+    return CardResult{
+        ID:          c.ID,
+        Name:        c.Name,
+        TypeLine:    c.TypeLine,
+        ManaCost:    c.ManaCost,
+        OracleText:  c.OracleText,
+        Colors:      c.Colors,
+        ImageNormal: c.ImageNormal,
+        Distance:    c.Distance,
+        Similarity:  similarity(c.Distance),
+        Printings:   c.Printings,
+        PriceUSD:    c.PriceUSD,
+        Vector:      c.Vector,
+    }
+}
+
+// seedVectors is what fetchVectorsForNames resolved from a list of input names.
+type seedVectors struct {
+    Names   []string    // input names with an embedding, parallel to Vectors and IDs
+    Vectors [][]float64
+    IDs     []string
+    NoVec   []string // resolved but without an embedding
+    NoVecID []string // object IDs of NoVec, so callers can still exclude them
+    Missing []string // no card found
+}
+
+// fetchVectorsForNames resolves names to vectors and object IDs. Cards that exist but have no
+// embedding, and names that match no card, are reported rather than failing the request;
+// callers decide how strict to be.
+func fetchVectorsForNames(ctx context.Context, cli *client.Client, names []string) (seedVectors, error) {
+    var sv seedVectors
     for _, name := range names {
         name = strings.TrimSpace(name)
         if name == "" {
             continue
         }
         vec, id, err := cli.FetchVectorForName(ctx, name)
-        if err != nil {
-            return nil, nil, fmt.Errorf("fetch vector for %q: %w", name, err)
-        }
-        if len(vec) == 0 {
-            continue
+        switch {
+        case errors.Is(err, client.ErrNoVector):
+            slog.WarnContext(ctx, "skipping seed without vector", "name", name)
+            sv.NoVec, sv.NoVecID = append(sv.NoVec, name), append(sv.NoVecID, id)
+        case errors.Is(err, client.ErrCardNotFound):
+            sv.Missing = append(sv.Missing, name)
+        case err != nil:
+            return sv, fmt.Errorf("fetch vector for %q: %w", name, err)
+        default:
+            sv.Names, sv.Vectors, sv.IDs = append(sv.Names, name), append(sv.Vectors, vec), append(sv.IDs, id)
         }
-        vectors = append(vectors, vec)
-        ids = append(ids, id)
     }
-    return vectors, ids, nil
+    return sv, nil
 }
 // Removed raw GraphQL helpers; use pkg/weaviateclient instead.
 
@@ -197,3 +648,36 @@ func averageVectors(vectors [][]float64) []float64 {
     }
     return out
 }
+
+// cosine returns the cosine similarity of a and b (0 if either is a zero vector).
+func cosine(a, b []float64) float64 {
+    var dot, na, nb float64
+    for i := range min(len(a), len(b)) {
+        dot += a[i] * b[i]
+        na += a[i] * a[i]
+        nb += b[i] * b[i]
+    }
+    if na == 0 || nb == 0 { return 0 }
+    return dot / math.Sqrt(na*nb)
+}
+
+// readyTimeout bounds the /readyz probe query, well inside typical orchestrator probe timeouts.
+const readyTimeout = 3 * time.Second
+
+// handleReadyz serves GET /readyz: ok once a one-card Get on the Card class succeeds, so
+// Weaviate is up and the schema exists; otherwise 503 with the error. /healthz stays a
+// liveness check that never touches Weaviate.
+func handleReadyz(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+        defer cancel()
+        w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+        if _, err := cli.ListCards(ctx, 0, 1); err != nil {
+            slog.WarnContext(ctx, "readiness check failed", "err", err)
+            w.WriteHeader(http.StatusServiceUnavailable)
+            _, _ = w.Write([]byte("not ready: " + err.Error()))
+            return
+        }
+        _, _ = w.Write([]byte("ok"))
+    }
+}
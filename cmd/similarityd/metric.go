@@ -0,0 +1,47 @@
+package main
+
+import (
+    "log/slog"
+    "os"
+    "strings"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// metric is the distance metric of the Weaviate Card class, set via METRIC (cosine, dot or
+// l2). It must match the class's vectorIndexConfig.distance, since it decides how the
+// distances Weaviate returns are turned into the reported similarity.
+var metric = "cosine"
+
+// metricFromEnv reads METRIC, falling back to cosine when unset or unknown.
+// Weaviate's own name "l2-squared" is accepted for l2.
+func metricFromEnv() string {
+    v := strings.ToLower(strings.TrimSpace(os.Getenv("METRIC")))
+    switch v {
+    case "":
+        return "cosine"
+    case "cosine", "dot", "l2":
+        return v
+    case "l2-squared":
+        return "l2"
+    }
+    slog.Warn("ignoring invalid env value", "key", "METRIC", "value", v)
+    return "cosine"
+}
+
+// similarity converts a distance under metric into a similarity, higher meaning closer:
+// cosine gives 1-d clamped to [0,1], dot the negated distance (Weaviate reports dot distance
+// as the negative dot product, so this is the dot product itself, unbounded), and l2
+// 1/(1+d) in (0,1].
+func similarity(d float64) float64 {
+    switch metric {
+    case "dot":
+        return -d
+    case "l2":
+        return 1 / (1 + d)
+    }
+    return client.SimilarityFromDistance(d)
+}
+
+// boundedSimilarity reports whether similarity stays within [0,1] under metric.
+func boundedSimilarity() bool { return metric != "dot" }
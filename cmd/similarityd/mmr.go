@@ -0,0 +1,45 @@
+package main
+
+import (
+    "math"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// mmrMaxWindow caps offset+k for a diversified search, since reranking its over-fetched
+// candidates costs candidates×window vector comparisons.
+const mmrMaxWindow = 500
+
+// rerankMMR reorders cands, which carry their vectors, by Maximal Marginal Relevance and
+// returns the first n. Each pick maximises
+//
+//	(1-diversity)·sim(query, c) − diversity·max sim(c, already picked)
+//
+// with cosine similarity of the embeddings, so diversity 0 keeps the similarity order and
+// higher values trade closeness to the query for distance from the results above.
+func rerankMMR(cands []client.Card, query []float64, diversity float64, n int) []client.Card {
+    n = min(n, len(cands))
+    rel := make([]float64, len(cands))
+    closest := make([]float64, len(cands)) // highest similarity to a picked card so far
+    for i, c := range cands {
+        rel[i] = cosine(query, c.Vector)
+        closest[i] = math.Inf(-1)
+    }
+    picked := make([]bool, len(cands))
+    out := make([]client.Card, 0, n)
+    for len(out) < n {
+        best, bestScore := -1, math.Inf(-1)
+        for i := range cands {
+            if picked[i] { continue }
+            score := (1 - diversity) * rel[i]
+            if len(out) > 0 { score -= diversity * closest[i] }
+            if score > bestScore { best, bestScore = i, score }
+        }
+        picked[best] = true
+        out = append(out, cands[best])
+        for i := range cands {
+            if !picked[i] { closest[i] = max(closest[i], cosine(cands[i].Vector, cands[best].Vector)) }
+        }
+    }
+    return out
+}
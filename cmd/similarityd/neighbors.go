@@ -0,0 +1,225 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "math"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// Limits for /neighbors. Every expanded node costs one nearVector search, so max_nodes also
+// bounds the work of a request.
+const (
+    defaultNeighborDepth   = 2
+    maxNeighborDepth       = 3
+    defaultNeighborBreadth = 8
+    maxNeighborBreadth     = 25
+    defaultNeighborNodes   = 50
+    maxNeighborNodes       = 200
+)
+
+// NeighborsRequest names the seed card (by name, or by Scryfall ID) and how far to expand
+// the graph around it.
+type NeighborsRequest struct {
+    Name          string  `json:"name,omitempty"`
+    ID            string  `json:"id,omitempty"`
+    Depth         int     `json:"depth,omitempty"`
+    Breadth       int     `json:"breadth,omitempty"`
+    MinSimilarity float64 `json:"min_similarity,omitempty"`
+    MaxNodes      int     `json:"max_nodes,omitempty"`
+}
+
+// NeighborNode is one card of the graph; Depth is its hop distance from the seed.
+type NeighborNode struct {
+    ID          string   `json:"id"`
+    Name        string   `json:"name"`
+    TypeLine    string   `json:"type_line"`
+    ManaCost    string   `json:"mana_cost"`
+    Colors      []string `json:"colors"`
+    ImageNormal string   `json:"image_normal"`
+    Depth       int      `json:"depth"`
+}
+
+// NeighborLink connects two nodes by ID whose similarity is at least the threshold.
+type NeighborLink struct {
+    Source     string  `json:"source"`
+    Target     string  `json:"target"`
+    Similarity float64 `json:"similarity"`
+}
+
+// NeighborsResponse is the graph in d3-force's shape: nodes with IDs, and links whose
+// source and target are node IDs. Truncated is set when max_nodes stopped the expansion.
+type NeighborsResponse struct {
+    Seed      string         `json:"seed"`
+    Nodes     []NeighborNode `json:"nodes"`
+    Links     []NeighborLink `json:"links"`
+    Truncated bool           `json:"truncated"`
+}
+
+// handleNeighbors serves POST /neighbors with a NeighborsRequest, or
+// GET /neighbors?name=Lightning+Bolt&depth=2&breadth=8&min_similarity=0.6&max_nodes=50.
+func handleNeighbors(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var req NeighborsRequest
+        switch r.Method {
+        case http.MethodGet:
+            var err error
+            if req, err = neighborsRequestFromQuery(r.URL.Query()); err != nil {
+                writeError(w, r, err)
+                return
+            }
+        case http.MethodPost:
+            if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+                writeError(w, r, &httpError{http.StatusBadRequest, "bad request: " + err.Error()})
+                return
+            }
+        default:
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+        defer cancel()
+        resp, err := runNeighbors(ctx, cli, req)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        writeJSON(w, resp)
+    }
+}
+
+func neighborsRequestFromQuery(q url.Values) (NeighborsRequest, error) {
+    req := NeighborsRequest{Name: q.Get("name"), ID: q.Get("id")}
+    ints := []struct {
+        key string
+        dst *int
+    }{{"depth", &req.Depth}, {"breadth", &req.Breadth}, {"max_nodes", &req.MaxNodes}}
+    for _, p := range ints {
+        v := q.Get(p.key)
+        if v == "" { continue }
+        n, err := strconv.Atoi(v)
+        if err != nil { return req, &httpError{http.StatusBadRequest, p.key + " must be an integer"} }
+        *p.dst = n
+    }
+    if v := q.Get("min_similarity"); v != "" {
+        f, err := strconv.ParseFloat(v, 64)
+        if err != nil { return req, &httpError{http.StatusBadRequest, "min_similarity must be a number"} }
+        req.MinSimilarity = f
+    }
+    return req, nil
+}
+
+// validate applies the defaults and rejects out-of-range values.
+func (req *NeighborsRequest) validate() error {
+    req.Name, req.ID = strings.TrimSpace(req.Name), strings.TrimSpace(req.ID)
+    if req.Name == "" && req.ID == "" { return &httpError{http.StatusBadRequest, "name or id required"} }
+    bound := func(v *int, key string, def, hi int) error {
+        if *v == 0 { *v = def }
+        if *v < 1 || *v > hi { return &httpError{http.StatusBadRequest, fmt.Sprintf("%s must be between 1 and %d", key, hi)} }
+        return nil
+    }
+    if err := bound(&req.Depth, "depth", defaultNeighborDepth, maxNeighborDepth); err != nil { return err }
+    if err := bound(&req.Breadth, "breadth", defaultNeighborBreadth, maxNeighborBreadth); err != nil { return err }
+    if err := bound(&req.MaxNodes, "max_nodes", defaultNeighborNodes, maxNeighborNodes); err != nil { return err }
+    if math.IsNaN(req.MinSimilarity) || math.IsInf(req.MinSimilarity, 0) { return &httpError{http.StatusBadRequest, "min_similarity must be a number"} }
+    return nil
+}
+
+// runNeighbors expands the graph breadth-first from the seed: each node up to Depth-1 hops
+// out gets a nearVector search for its Breadth nearest cards, and every neighbor at or above
+// MinSimilarity becomes a link, and a node unless MaxNodes is reached (links between nodes
+// already in the graph are still added). Printings sharing a name collapse into one node.
+// The searches return their cards' vectors, which are kept for expanding those cards in turn,
+// so no node's vector is fetched twice.
+func runNeighbors(ctx context.Context, cli *client.Client, req NeighborsRequest) (*NeighborsResponse, error) {
+    if err := req.validate(); err != nil { return nil, err }
+    var (
+        seedVec []float64
+        seedID  string
+        err     error
+    )
+    if req.ID != "" {
+        seedVec, seedID, err = cli.FetchVectorByScryfallID(ctx, req.ID)
+    } else {
+        seedVec, seedID, err = cli.FetchVectorForName(ctx, req.Name)
+    }
+    if err != nil {
+        if errors.Is(err, client.ErrCardNotFound) || errors.Is(err, client.ErrNoVector) { return nil, &httpError{http.StatusNotFound, err.Error()} }
+        return nil, err
+    }
+
+    resp := &NeighborsResponse{Seed: seedID, Nodes: []NeighborNode{{ID: seedID, Name: seedLabel(req), Colors: []string{}}}, Links: []NeighborLink{}}
+    index := map[string]int{seedID: 0} // node ID -> position in resp.Nodes
+    byName := map[string]string{}      // lower-cased name -> node ID
+    if req.Name != "" { byName[strings.ToLower(req.Name)] = seedID }
+    vectors := map[string][]float64{seedID: seedVec}
+    linked := map[[2]string]bool{}
+    link := func(a, b string, sim float64) {
+        if a == b { return }
+        key := [2]string{min(a, b), max(a, b)}
+        if linked[key] { return }
+        linked[key] = true
+        resp.Links = append(resp.Links, NeighborLink{Source: a, Target: b, Similarity: sim})
+    }
+
+    for frontier := []string{seedID}; len(frontier) > 0; {
+        var next []string
+        for _, id := range frontier {
+            depth := resp.Nodes[index[id]].Depth
+            if depth >= req.Depth || len(vectors[id]) == 0 { continue }
+            cards, err := cli.SearchNearVectorWithVectors(ctx, vectors[id], req.Breadth+1, nil) // +1: the node itself
+            if err != nil { return nil, err }
+            n := 0
+            for _, c := range cards {
+                if c.ID == id {
+                    if id == seedID { fillNode(&resp.Nodes[0], c, byName) } // the seed's own card details
+                    continue
+                }
+                if n == req.Breadth { break }
+                n++
+                sim := similarity(c.Distance)
+                if sim < req.MinSimilarity { break } // nearest first, so the rest are further
+                to := c.ID
+                if known, ok := byName[strings.ToLower(c.Name)]; ok { to = known }
+                if _, ok := index[to]; !ok {
+                    if len(resp.Nodes) >= req.MaxNodes {
+                        resp.Truncated = true
+                        continue
+                    }
+                    node := NeighborNode{Depth: depth + 1}
+                    fillNode(&node, c, byName)
+                    index[to] = len(resp.Nodes)
+                    resp.Nodes = append(resp.Nodes, node)
+                    vectors[to] = c.Vector
+                    next = append(next, to)
+                }
+                link(id, to, sim)
+            }
+        }
+        frontier = next
+    }
+    return resp, nil
+}
+
+// fillNode copies c's details into node and records its name, so other printings of the
+// card map to it.
+func fillNode(node *NeighborNode, c client.Card, byName map[string]string) {
+    colors := c.Colors
+    if colors == nil { colors = []string{} }
+    node.ID, node.Name, node.TypeLine, node.ManaCost, node.Colors, node.ImageNormal = c.ID, c.Name, c.TypeLine, c.ManaCost, colors, c.ImageNormal
+    byName[strings.ToLower(c.Name)] = c.ID
+}
+
+// seedLabel is the seed's display name until its own search result fills in the details.
+func seedLabel(req NeighborsRequest) string {
+    if req.Name != "" { return req.Name }
+    return req.ID
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+    _ "embed"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "reflect"
+    "sort"
+    "strings"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// openapiSpec is the hand-maintained OpenAPI 3 description served at /openapi.json.
+//
+//go:embed openapi.json
+var openapiSpec []byte
+
+// specSchemas maps each component schema in openapiSpec to the Go type it documents;
+// checkOpenAPI fails startup when their JSON properties drift apart.
+var specSchemas = map[string]any{
+    "Config":               Config{},
+    "SimilarRequest":       SimilarRequest{},
+    "SimilarResponse":      SimilarResponse{},
+    "BudgetRequest":        BudgetRequest{},
+    "BudgetResponse":       BudgetResponse{},
+    "TextSimilarRequest":   TextSimilarRequest{},
+    "TextSimilarResponse":  TextSimilarResponse{},
+    "CardResult":           CardResult{},
+    "BuildAroundRequest":   BuildAroundRequest{},
+    "BuildAroundResponse":  BuildAroundResponse{},
+    "IdentityFit":          client.IdentityFit{},
+    "CommanderSuggestions": CommanderSuggestions{},
+    "Staple":               Staple{},
+    "StaplesResponse":      StaplesResponse{},
+    "CubePoolRequest":      CubePoolRequest{},
+    "CubeCard":             CubeCard{},
+    "CubeShortfall":        CubeShortfall{},
+    "CubePoolResponse":     CubePoolResponse{},
+    "NeighborsRequest":     NeighborsRequest{},
+    "NeighborNode":         NeighborNode{},
+    "NeighborLink":         NeighborLink{},
+    "NeighborsResponse":    NeighborsResponse{},
+    "AnalyzeRequest":       AnalyzeRequest{},
+    "ColorBreakdown":       ColorBreakdown{},
+    "ColorAnalysis":        ColorAnalysis{},
+    "CurveBucket":          CurveBucket{},
+    "CurveAnalysis":        CurveAnalysis{},
+    "CardSynergy":          CardSynergy{},
+    "SynergyAnalysis":      SynergyAnalysis{},
+    "PairComparison":       PairComparison{},
+    "CollectionUpdate":     CollectionUpdate{},
+    "OwnedCard":            OwnedCard{},
+    "CollectionList":       CollectionList{},
+    "VectorsRequest":       VectorsRequest{},
+    "CardVector":           CardVector{},
+    "VectorsResponse":      VectorsResponse{},
+}
+
+// checkOpenAPI parses the embedded spec and compares each schema in specSchemas with the
+// JSON field names of its Go struct, reporting every mismatch at once.
+func checkOpenAPI(spec []byte) error {
+    var doc struct {
+        OpenAPI    string `json:"openapi"`
+        Paths      map[string]json.RawMessage `json:"paths"`
+        Components struct {
+            Schemas map[string]struct {
+                Properties map[string]json.RawMessage `json:"properties"`
+            } `json:"schemas"`
+        } `json:"components"`
+    }
+    if err := json.Unmarshal(spec, &doc); err != nil { return fmt.Errorf("parse openapi.json: %w", err) }
+    if !strings.HasPrefix(doc.OpenAPI, "3.") { return fmt.Errorf("openapi.json: unsupported openapi version %q", doc.OpenAPI) }
+    if len(doc.Paths) == 0 { return errors.New("openapi.json: no paths") }
+    var errs []error
+    names := make([]string, 0, len(specSchemas))
+    for name := range specSchemas { names = append(names, name) }
+    sort.Strings(names)
+    for _, name := range names {
+        schema, ok := doc.Components.Schemas[name]
+        if !ok { errs = append(errs, fmt.Errorf("schema %s: missing from openapi.json", name)); continue }
+        fields := jsonFields(reflect.TypeOf(specSchemas[name]))
+        for f := range fields {
+            if _, ok := schema.Properties[f]; !ok { errs = append(errs, fmt.Errorf("schema %s: property %q is not documented", name, f)) }
+        }
+        for p := range schema.Properties {
+            if !fields[p] { errs = append(errs, fmt.Errorf("schema %s: documented property %q does not exist", name, p)) }
+        }
+    }
+    return errors.Join(errs...)
+}
+
+// jsonFields returns the JSON names encoding/json uses for t's exported fields.
+func jsonFields(t reflect.Type) map[string]bool {
+    out := map[string]bool{}
+    for i := 0; i < t.NumField(); i++ {
+        f := t.Field(i)
+        if !f.IsExported() { continue }
+        name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+        if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+            for n := range jsonFields(f.Type) { out[n] = true } // promoted, as encoding/json does
+            continue
+        }
+        if name == "-" { continue }
+        if name == "" { name = f.Name }
+        out[name] = true
+    }
+    return out
+}
+
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+    w.Header().Set("Cache-Control", "public, max-age=300")
+    _, _ = w.Write(openapiSpec)
+}
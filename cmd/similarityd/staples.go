@@ -0,0 +1,77 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+const (
+    defaultStaples = 50
+    maxStaples     = 200
+)
+
+// Staple is one format staple, ranked by EDHREC popularity (1 is the most played).
+type Staple struct {
+    Rank        int    `json:"rank"`
+    ID          string `json:"id"`
+    Name        string `json:"name"`
+    TypeLine    string `json:"type_line"`
+    ManaCost    string `json:"mana_cost"`
+    Rarity      string `json:"rarity"`
+    ImageNormal string `json:"image_normal"`
+    PriceUSD    string `json:"price_usd,omitempty"`
+}
+
+// StaplesResponse lists a format's staples; Message explains an empty list.
+type StaplesResponse struct {
+    Format  string   `json:"format"`
+    Count   int      `json:"count"`
+    Cards   []Staple `json:"cards"`
+    Message string   `json:"message,omitempty"`
+}
+
+// handleStaples serves GET /staples?format=modern&limit=50.
+func handleStaples(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet {
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+        if format == "" {
+            writeError(w, r, &httpError{http.StatusBadRequest, "format required"})
+            return
+        }
+        limit := defaultStaples
+        if v := r.URL.Query().Get("limit"); v != "" {
+            n, err := strconv.Atoi(v)
+            if err != nil || n < 1 || n > maxStaples {
+                writeError(w, r, &httpError{http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxStaples)})
+                return
+            }
+            limit = n
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+        defer cancel()
+        cards, err := cli.Staples(ctx, format, limit)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        resp := StaplesResponse{Format: format, Count: len(cards), Cards: make([]Staple, len(cards))}
+        for i, c := range cards {
+            resp.Cards[i] = Staple{Rank: c.EDHRECRank, ID: c.ID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, Rarity: c.Rarity, ImageNormal: c.ImageNormal, PriceUSD: c.PriceUSD}
+        }
+        if len(cards) == 0 {
+            resp.Message = fmt.Sprintf("no ranked cards are legal in %q; check the format name (e.g. modern, commander, pauper) and that legalities and EDHREC ranks were ingested", format)
+        }
+        w.Header().Set("Cache-Control", "public, max-age=3600")
+        writeJSON(w, resp)
+    }
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "log/slog"
+    "net/http"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// streamFlushEvery is how many NDJSON lines are written between flushes.
+const streamFlushEvery = 25
+
+// streamSimilar answers /similar?stream=ndjson with one CardResult object per line, in rank
+// order. Errors before the first line (bad request, unknown card) get the usual status and
+// plain-text body; once streaming has begun the status is already 200, so a failure is
+// reported as a final {"error": ...} line instead.
+func streamSimilar(ctx context.Context, w http.ResponseWriter, r *http.Request, cli *client.Client, req SimilarRequest) {
+    sq, err := prepareSimilar(ctx, cli, req)
+    if err != nil {
+        writeError(w, r, err)
+        return
+    }
+    sq.seedHeaders(w.Header())
+    w.Header().Set("Content-Type", "application/x-ndjson")
+    w.WriteHeader(http.StatusOK)
+    rc := http.NewResponseController(w)
+    enc := json.NewEncoder(w) // Encode terminates each value with a newline
+    n := 0
+    err = sq.run(ctx, cli, func(c CardResult) error {
+        if err := enc.Encode(c); err != nil { return err }
+        if n++; n%streamFlushEvery == 0 { _ = rc.Flush() }
+        return nil
+    })
+    if err != nil {
+        slog.ErrorContext(ctx, "similar stream failed", "path", r.URL.Path, "results", n, "err", err)
+        _ = enc.Encode(map[string]string{"error": err.Error()})
+    }
+    _ = rc.Flush()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "sort"
+    "time"
+
+    "github.com/domano/decktech/pkg/decklist"
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// leastSynergistic is how many cut candidates /synergy reports.
+const leastSynergistic = 3
+
+// CardSynergy is one card's mean cosine similarity to the rest of the deck.
+type CardSynergy struct {
+    Name          string  `json:"name"`
+    AvgSimilarity float64 `json:"avg_similarity"`
+}
+
+// SynergyAnalysis is the /synergy response. Synergy is the mean pairwise cosine similarity over
+// the distinct resolved cards; Least lists the cards with the lowest average, i.e. what to cut.
+type SynergyAnalysis struct {
+    Cards      int           `json:"cards"`
+    Synergy    float64       `json:"synergy"`
+    PerCard    []CardSynergy `json:"per_card"`
+    Least      []CardSynergy `json:"least_synergistic"`
+    Unresolved []string      `json:"unresolved,omitempty"`
+    Skipped    []string      `json:"skipped,omitempty"`
+}
+
+func handleSynergy(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost && r.Method != http.MethodGet {
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        entries, err := decodeAnalyzeRequest(r)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+        defer cancel()
+        sv, err := fetchVectorsForNames(ctx, cli, decklist.Names(entries))
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        if len(sv.Vectors) < 2 {
+            writeError(w, r, &httpError{http.StatusBadRequest, "synergy needs at least 2 cards with embeddings"})
+            return
+        }
+        writeJSON(w, analyzeSynergy(sv))
+    }
+}
+
+// analyzeSynergy walks each pair once, accumulating the total and per-card sums, so memory
+// stays linear in the deck size. Copies of a card count once; they would only add 1.0 pairs.
+func analyzeSynergy(sv seedVectors) SynergyAnalysis {
+    n := len(sv.Vectors)
+    sums := make([]float64, n)
+    var total float64
+    for i := 0; i < n; i++ {
+        for j := i + 1; j < n; j++ {
+            sim := cosine(sv.Vectors[i], sv.Vectors[j])
+            total += sim
+            sums[i] += sim
+            sums[j] += sim
+        }
+    }
+    out := SynergyAnalysis{Cards: n, Synergy: total / float64(n*(n-1)/2), PerCard: make([]CardSynergy, n), Unresolved: sv.Missing, Skipped: sv.NoVec}
+    for i := range sums {
+        out.PerCard[i] = CardSynergy{Name: sv.Names[i], AvgSimilarity: sums[i] / float64(n-1)}
+    }
+    out.Least = append([]CardSynergy(nil), out.PerCard...)
+    sort.SliceStable(out.Least, func(a, b int) bool { return out.Least[a].AvgSimilarity < out.Least[b].AvgSimilarity })
+    out.Least = out.Least[:min(leastSynergistic, n)]
+    return out
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/domano/decktech/pkg/embed"
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// maxTextLen caps the text of a /similar/text request; oracle texts run to about 1000 characters.
+const maxTextLen = 4000
+
+// Text search modes, in the order runTextSimilar tries them.
+const (
+    textModeEmbed    = "embed"     // embedded in Go via EMBED_URL, then nearVector
+    textModeNearText = "near_text" // embedded by the Card class's vectorizer module
+    textModeBM25     = "bm25"      // keyword ranking, no vectors
+)
+
+// TextSimilarRequest asks for the cards closest to a free-form rules text.
+type TextSimilarRequest struct {
+    Text string `json:"text"`
+    K    int    `json:"k"`
+}
+
+// TextSimilarResponse names the mode that ranked the results: with bm25 they carry a score
+// instead of a distance and similarity.
+type TextSimilarResponse struct {
+    Mode    string       `json:"mode"`
+    Results []CardResult `json:"results"`
+}
+
+// textEmbedderFromEnv returns an embedder for EMBED_URL (model EMBED_MODEL), or nil when unset.
+// It must produce the vectors the collection was ingested with, e.g. the importer's Embed URL
+// and Model.
+func textEmbedderFromEnv() embed.Embedder {
+    url := strings.TrimSpace(os.Getenv("EMBED_URL"))
+    if url == "" { return nil }
+    e := embed.NewHTTPEmbedder(url, strings.TrimSpace(os.Getenv("EMBED_MODEL")))
+    e.Retries = 0 // a request waits on it; the caller can retry
+    return e
+}
+
+// handleTextSimilar serves POST /similar/text with a TextSimilarRequest, or
+// GET /similar/text?text=...&k=10.
+func handleTextSimilar(cli *client.Client, emb embed.Embedder) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var req TextSimilarRequest
+        switch r.Method {
+        case http.MethodGet:
+            req.Text = r.URL.Query().Get("text")
+            req.K, _ = strconv.Atoi(r.URL.Query().Get("k"))
+        case http.MethodPost:
+            if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+                writeError(w, r, &httpError{http.StatusBadRequest, "bad request: " + err.Error()})
+                return
+            }
+        default:
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+        defer cancel()
+        resp, err := runTextSimilar(ctx, cli, emb, req)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        writeJSON(w, resp)
+    }
+}
+
+// runTextSimilar embeds the text with emb when configured and runs a nearVector search; else
+// it lets the Card class's vectorizer module embed it (nearText) when the class has one; else
+// it ranks by bm25. Only a failing bm25 fallback means no text search is available (503).
+func runTextSimilar(ctx context.Context, cli *client.Client, emb embed.Embedder, req TextSimilarRequest) (*TextSimilarResponse, error) {
+    text := strings.TrimSpace(req.Text)
+    if text == "" { return nil, &httpError{http.StatusBadRequest, "text required"} }
+    if len(text) > maxTextLen { return nil, &httpError{http.StatusBadRequest, fmt.Sprintf("text must be at most %d characters", maxTextLen)} }
+    k, err := resolveK(req.K)
+    if err != nil { return nil, err }
+
+    resp := &TextSimilarResponse{Results: []CardResult{}}
+    var cards []client.Card
+    switch {
+    case emb != nil:
+        resp.Mode = textModeEmbed
+        // Cards are embedded as labelled fields (see embed_cards.py); label the text the same way.
+        vecs, err := emb.Embed(ctx, []string{"Oracle: " + text})
+        if err != nil { return nil, fmt.Errorf("embed text: %w", err) }
+        if len(vecs) != 1 { return nil, fmt.Errorf("embed text: got %d vectors, want 1", len(vecs)) }
+        cards, err = cli.SearchNearVector(ctx, embed.Normalize(vecs[0]), k)
+        if err != nil { return nil, err }
+    case hasVectorizer(ctx, cli):
+        resp.Mode = textModeNearText
+        cards, err = cli.SearchNearText(ctx, text, k, nil)
+        if err != nil { return nil, err }
+    default:
+        resp.Mode = textModeBM25
+        cards, err = cli.SearchBM25(ctx, text, k, nil)
+        if err != nil {
+            return nil, &httpError{http.StatusServiceUnavailable, "no text search available: EMBED_URL is unset, the Card class has no vectorizer module, and bm25 failed: " + err.Error()}
+        }
+    }
+    for _, c := range cards {
+        res := toCardResult(c)
+        if resp.Mode == textModeBM25 { res.Distance, res.Similarity, res.Score = 0, 0, c.Score }
+        resp.Results = append(resp.Results, res)
+    }
+    return resp, nil
+}
+
+// hasVectorizer reports whether the Card class embeds text itself. An unreadable schema
+// counts as no, leaving bm25 to report whether Weaviate can search at all.
+func hasVectorizer(ctx context.Context, cli *client.Client) bool {
+    cfg, err := cli.CardClassConfig(ctx)
+    if err != nil {
+        if !errors.Is(err, client.ErrClassNotFound) { slog.WarnContext(ctx, "read Card class config", "err", err) }
+        return false
+    }
+    return cfg.Vectorizer != "" && cfg.Vectorizer != "none"
+}
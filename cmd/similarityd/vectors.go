@@ -0,0 +1,64 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// VectorsRequest is the POST /vectors body.
+type VectorsRequest struct {
+    Names []string `json:"names"`
+}
+
+// CardVector is one resolved card's raw embedding.
+type CardVector struct {
+    Name   string    `json:"name"`
+    ID     string    `json:"id"`
+    Vector []float64 `json:"vector"`
+}
+
+// VectorsResponse is the /vectors response. Names that match no card are listed in
+// unresolved; cards without an embedding in skipped.
+type VectorsResponse struct {
+    Dimension  int          `json:"dimension"`
+    Vectors    []CardVector `json:"vectors"`
+    Unresolved []string     `json:"unresolved,omitempty"`
+    Skipped    []string     `json:"skipped,omitempty"`
+}
+
+// handleVectors returns raw embeddings for a list of names. The responses are large, so the
+// endpoint is only registered when ENABLE_VECTORS_ENDPOINT is set.
+func handleVectors(cli *client.Client) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost {
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        var req VectorsRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            writeError(w, r, &httpError{http.StatusBadRequest, "bad request: " + err.Error()})
+            return
+        }
+        if len(req.Names) == 0 {
+            writeError(w, r, &httpError{http.StatusBadRequest, "names required"})
+            return
+        }
+        ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+        defer cancel()
+        sv, err := fetchVectorsForNames(ctx, cli, req.Names)
+        if err != nil {
+            writeError(w, r, err)
+            return
+        }
+        resp := VectorsResponse{Vectors: make([]CardVector, len(sv.Vectors)), Unresolved: sv.Missing, Skipped: sv.NoVec}
+        for i, vec := range sv.Vectors {
+            resp.Vectors[i] = CardVector{Name: sv.Names[i], ID: sv.IDs[i], Vector: vec}
+        }
+        if len(sv.Vectors) > 0 { resp.Dimension = len(sv.Vectors[0]) }
+        writeJSON(w, resp)
+    }
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "strings"
+    "time"
+)
+
+const (
+    autocompleteDefault = 10
+    autocompleteMax     = 25
+)
+
+// handleAutocomplete answers /autocomplete?q=light&limit=10 with a JSON array of distinct card
+// names for a search-box typeahead. No matches (or an empty q) give [], never a 404.
+func (s *Server) handleAutocomplete(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    limit := atoiDefault(q.Get("limit"), autocompleteDefault)
+    if limit <= 0 { limit = autocompleteDefault }
+    if limit > autocompleteMax { limit = autocompleteMax }
+
+    ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+    defer cancel()
+    names, err := s.cli.SuggestNames(ctx, strings.TrimSpace(q.Get("q")), limit)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadGateway)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.Header().Set("Cache-Control", "public, max-age=60") // keystrokes repeat; let the browser absorb them
+    _ = json.NewEncoder(w).Encode(names)
+}
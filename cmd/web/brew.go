@@ -0,0 +1,94 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "math/rand"
+    "net/http"
+    "net/url"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// brewSize is how many suggestions a starter list holds; brewPool is how many in-identity
+// neighbours a rerolled list is drawn from.
+const (
+    brewSize = 20
+    brewPool = 60
+)
+
+// handleBrew is the "surprise me" deck seed: /brew picks a random legendary creature and
+// redirects to /brew?commander=<scryfall id>, which lists its brewSize nearest in-identity
+// cards. &seed=N (any non-zero value) instead samples brewSize cards from the nearest
+// brewPool, so the page can reroll suggestions while keeping the commander. &export=mtga or
+// &export=text downloads the commander and list instead (see writeDeckExport).
+func (s *Server) handleBrew(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    id := strings.TrimSpace(q.Get("commander"))
+    seed, _ := strconv.ParseInt(q.Get("seed"), 10, 64)
+    export := q.Get("export")
+    if export != "" && !validExport(export) {
+        http.Error(w, fmt.Sprintf("unknown export format %q (want %s or %s)", export, exportMTGA, exportText), http.StatusBadRequest)
+        return
+    }
+    ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+    defer cancel()
+    if id == "" {
+        picks, err := s.randomLegends(ctx, 1)
+        if err == nil && len(picks) == 0 { err = fmt.Errorf("no legendary creatures found") }
+        if err != nil {
+            s.render(w, r, "brew.html", Page{Title: "Brew", Error: err.Error()})
+            return
+        }
+        http.Redirect(w, r, "/brew?commander="+url.QueryEscape(picks[0].ScryfallID), http.StatusSeeOther)
+        return
+    }
+    cmdr, err := s.getCardByScryfallID(ctx, id)
+    if err != nil {
+        s.render(w, r, "brew.html", Page{Title: "Brew", Error: err.Error()})
+        return
+    }
+    cards, err := s.brewSuggestions(ctx, cmdr, seed)
+    if err != nil {
+        s.render(w, r, "brew.html", Page{Title: "Brew — " + cmdr.Name, Card: &cmdr, CardID: id, Error: err.Error()})
+        return
+    }
+    if export != "" {
+        writeDeckExport(w, export, "brew-"+cmdr.ScryfallID, cmdr, cards)
+        return
+    }
+    pg := Page{Title: "Brew — " + cmdr.Name, Card: &cmdr, CardID: id, Cards: cards, Seed: rand.Int63n(1<<31) + 1, Params: stickyParams(q)}
+    if len(cards) < brewSize { pg.Notice = fmt.Sprintf("Only %d in-identity suggestions found.", len(cards)) }
+    s.render(w, r, "brew.html", pg)
+}
+
+// brewSuggestions runs a nearVector search from the commander restricted to its color
+// identity, dropping the commander itself, basic lands and duplicate printings. The list is
+// ordered by mana value, then name, for printing.
+func (s *Server) brewSuggestions(ctx context.Context, cmdr Card, seed int64) ([]Card, error) {
+    vec, _, err := s.cli.FetchVectorByScryfallID(ctx, cmdr.ScryfallID)
+    if err != nil { vec, _, err = s.cli.FetchVectorForName(ctx, cmdr.Name) }
+    if err != nil { return nil, err }
+    res, err := s.cli.SearchNearVectorFiltered(ctx, vec, brewPool+1, client.WithinIdentity(cmdr.ColorID))
+    if err != nil { return nil, err }
+    var pool []client.Card
+    for _, c := range client.DedupeByName(res) {
+        if strings.EqualFold(c.Name, cmdr.Name) || strings.HasPrefix(c.TypeLine, "Basic ") { continue }
+        pool = append(pool, c)
+    }
+    if seed != 0 {
+        rng := rand.New(rand.NewSource(seed))
+        rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+    }
+    if len(pool) > brewSize { pool = pool[:brewSize] }
+    cards := toWebCards(pool)
+    sort.SliceStable(cards, func(i, j int) bool {
+        if cards[i].CMC != cards[j].CMC { return cards[i].CMC < cards[j].CMC }
+        return cards[i].Name < cards[j].Name
+    })
+    return cards, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+    "container/list"
+    "log/slog"
+    "net/http"
+    "net/url"
+    "os"
+    "slices"
+    "strings"
+    "sync"
+    "time"
+)
+
+// resultCache is a small TTL cache of fetched+filtered card slices keyed by the
+// normalized request (path + sorted query params), so filtered and unfiltered
+// results never collide. Rendered HTML is not cached. Entries expire after the TTL;
+// when full, the least recently used entry is evicted.
+type resultCache struct {
+    mu      sync.Mutex
+    ttl     time.Duration
+    size    int
+    entries map[string]*list.Element // values are *cacheEntry
+    lru     *list.List               // front = most recently used
+    hits    int64
+    misses  int64
+}
+
+type cacheEntry struct {
+    key     string
+    cards   []Card
+    total   int // cachedPage: the number of matches across all pages
+    expires time.Time
+}
+
+// newResultCacheFromEnv reads WEB_CACHE_TTL (duration, default 60s; 0 disables)
+// and WEB_CACHE_SIZE (max entries, default 256).
+func newResultCacheFromEnv() *resultCache {
+    ttl := 60 * time.Second
+    if v := os.Getenv("WEB_CACHE_TTL"); v != "" {
+        if d, err := time.ParseDuration(v); err == nil { ttl = d }
+    }
+    size := atoiDefault(os.Getenv("WEB_CACHE_SIZE"), 256)
+    return &resultCache{ttl: ttl, size: size, entries: map[string]*list.Element{}, lru: list.New()}
+}
+
+// key returns the cache key for r, and false when caching is disabled or bypassed via ?nocache=1.
+// Params listed in ignore (e.g. paging params applied after the fetch) are left out of the key.
+func (c *resultCache) key(r *http.Request, ignore ...string) (string, bool) {
+    if c == nil || c.ttl <= 0 || c.size <= 0 { return "", false }
+    q := r.URL.Query()
+    if q.Get("nocache") == "1" { return "", false }
+    norm := url.Values{}
+    for k, vs := range q {
+        if k == "nocache" || slices.Contains(ignore, k) { continue }
+        for _, v := range vs {
+            if v = strings.TrimSpace(v); v != "" { norm.Add(k, v) }
+        }
+    }
+    return r.URL.Path + "?" + norm.Encode(), true // Encode sorts by key
+}
+
+func (c *resultCache) get(key string) (*cacheEntry, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    el, ok := c.entries[key]
+    if ok && time.Now().After(el.Value.(*cacheEntry).expires) {
+        c.remove(el)
+        ok = false
+    }
+    if !ok {
+        c.misses++
+        return nil, false
+    }
+    c.hits++
+    c.lru.MoveToFront(el)
+    return el.Value.(*cacheEntry), true
+}
+
+func (c *resultCache) put(key string, cards []Card, total int) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    e := &cacheEntry{key: key, cards: cards, total: total, expires: time.Now().Add(c.ttl)}
+    if el, ok := c.entries[key]; ok {
+        el.Value = e
+        c.lru.MoveToFront(el)
+        return
+    }
+    for c.lru.Len() >= c.size { c.remove(c.lru.Back()) }
+    c.entries[key] = c.lru.PushFront(e)
+}
+
+// remove drops el from both the list and the index; callers hold c.mu.
+func (c *resultCache) remove(el *list.Element) {
+    c.lru.Remove(el)
+    delete(c.entries, el.Value.(*cacheEntry).key)
+}
+
+// hitRate returns hits/(hits+misses) so far.
+func (c *resultCache) hitRate() float64 {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if c.hits+c.misses == 0 { return 0 }
+    return float64(c.hits) / float64(c.hits+c.misses)
+}
+
+// cached returns the cached cards for r, or calls fetch and stores its result on success.
+func (c *resultCache) cached(r *http.Request, fetch func() ([]Card, error), ignore ...string) ([]Card, error) {
+    cards, _, err := c.cachedPage(r, func() ([]Card, int, error) {
+        cards, err := fetch()
+        return cards, 0, err
+    }, ignore...)
+    return cards, err
+}
+
+// cachedPage is cached for fetches that also return a total match count, e.g. one page of a
+// listing; the count is cached with the page.
+func (c *resultCache) cachedPage(r *http.Request, fetch func() ([]Card, int, error), ignore ...string) ([]Card, int, error) {
+    key, ok := c.key(r, ignore...)
+    if ok {
+        e, hit := c.get(key)
+        slog.DebugContext(r.Context(), "result cache", "key", key, "hit", hit, "hit_rate", c.hitRate())
+        if hit { return e.cards, e.total, nil }
+    }
+    cards, total, err := fetch()
+    if err == nil && ok { c.put(key, cards, total) }
+    return cards, total, err
+}
+
@@ -0,0 +1,60 @@
+package main
+
+import (
+    "context"
+    "hash/fnv"
+    "math/rand"
+    "sync"
+    "time"
+)
+
+// maxDailyOffset keeps the daily pick inside Weaviate's default QUERY_MAXIMUM_RESULTS, past
+// which offset paging fails; larger collections draw from their first 10000 cards.
+const maxDailyOffset = 9999
+
+// dailyCache holds the card of the day for the UTC date it was picked on.
+type dailyCache struct {
+    mu   sync.Mutex
+    day  string
+    card *Card
+}
+
+// cardOfTheDay returns today's featured card, picking it on the first call of each UTC day.
+// The pick hashes the date into an offset over the collection, so every server instance and
+// every visitor sees the same card all day (as long as the card count doesn't change mid-day).
+func (s *Server) cardOfTheDay(ctx context.Context) (*Card, error) {
+    day := time.Now().UTC().Format(time.DateOnly)
+    s.daily.mu.Lock()
+    defer s.daily.mu.Unlock()
+    if s.daily.card != nil && s.daily.day == day { return s.daily.card, nil }
+    c, err := s.featuredAt(ctx, func(n int) int { return dailyOffset(day, n) })
+    if err != nil || c == nil { return nil, err }
+    s.daily.day, s.daily.card = day, c
+    return c, nil
+}
+
+// seededCard is the featured card for a pinned ?seed=: a per-request source seeded with it
+// picks the offset, so a seed shows the same card on any day. It is not cached.
+func (s *Server) seededCard(ctx context.Context, seed int64) (*Card, error) {
+    rng := rand.New(rand.NewSource(seed))
+    return s.featuredAt(ctx, func(n int) int { return rng.Intn(min(n, maxDailyOffset+1)) })
+}
+
+// featuredAt loads the full details of the card at the offset pick chooses for a collection
+// of n cards; nil when the collection is empty.
+func (s *Server) featuredAt(ctx context.Context, pick func(n int) int) (*Card, error) {
+    n, err := s.cli.CountCards(ctx)
+    if err != nil || n == 0 { return nil, err }
+    res, err := s.cli.ListCards(ctx, pick(n), 1)
+    if err != nil || len(res) == 0 { return nil, err }
+    c, err := s.getCardByScryfallID(ctx, res[0].ScryfallID)
+    if err != nil { return nil, err }
+    return &c, nil
+}
+
+// dailyOffset maps a date onto an offset in [0, min(n, maxDailyOffset+1)).
+func dailyOffset(day string, n int) int {
+    h := fnv.New64a()
+    h.Write([]byte(day))
+    return int(h.Sum64() % uint64(min(n, maxDailyOffset+1)))
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "strings"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// Curve is the mana curve rendered on /curve.
+type Curve struct {
+    Total int
+    // mana value mean and range over the matching cards, from Weaviate's numeric aggregate
+    Mean, Min, Max float64
+    Bars []Bar // 0..6 and 7+
+}
+
+// handleCurve serves /curve[?set=mh3][&format=modern]: the average mana value of the matching
+// cards as the headline, over their mana-value histogram.
+func (s *Server) handleCurve(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+    defer cancel()
+    q := r.URL.Query()
+    page := Page{Title: "Mana Curve", Set: strings.ToLower(strings.TrimSpace(q.Get("set"))), Format: strings.ToLower(strings.TrimSpace(q.Get("format")))}
+    var ops []*client.WhereFilter
+    if page.Set != "" { ops = append(ops, client.TextFilter("Equal", "set", page.Set)) }
+    if page.Format != "" { ops = append(ops, client.LegalIn(page.Format)) }
+    curve, err := s.loadCurve(ctx, client.And(ops...))
+    if err != nil {
+        page.Error = err.Error()
+        s.render(w, r, "curve.html", page)
+        return
+    }
+    page.Curve = curve
+    s.render(w, r, "curve.html", page)
+}
+
+// loadCurve runs the count, cmc and cmc groupBy aggregates over the cards matching where (all
+// of them for nil).
+func (s *Server) loadCurve(ctx context.Context, where *client.WhereFilter) (*Curve, error) {
+    total, err := s.cli.CountWhere(ctx, where)
+    if err != nil { return nil, err }
+    mean, lo, hi, err := s.cli.AggregateCMC(ctx, where)
+    if err != nil { return nil, err }
+    cmc, err := s.cli.AggregateByFieldWhere(ctx, "cmc", where)
+    if err != nil { return nil, err }
+    return &Curve{Total: total, Mean: mean, Min: lo, Max: hi, Bars: cmcHistogram(cmc)}, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+)
+
+// Deck export formats for ?export=: Arena's "1 Lightning Bolt (M21) 139" lines, which pin the
+// printing, and plain "1 Lightning Bolt" lines for deckbuilders that pick their own.
+const (
+    exportMTGA = "mtga"
+    exportText = "text"
+)
+
+func validExport(format string) bool { return format == exportMTGA || format == exportText }
+
+// exportLine formats one copy of c. Arena needs both the set and the collector number, so a
+// card missing either gets the plain line, which deckbuilders resolve to a default printing.
+func exportLine(format string, c Card) string {
+    if format == exportMTGA && c.Set != "" && c.Collector != "" {
+        return fmt.Sprintf("1 %s (%s) %s", c.Name, strings.ToUpper(c.Set), c.Collector)
+    }
+    return "1 " + c.Name
+}
+
+// writeDeckExport sends the commander and its list as a text download named filename.txt.
+// The Arena format adds the Commander and Deck section headers Arena expects; the plain
+// format puts the commander on the first line.
+func writeDeckExport(w http.ResponseWriter, format, filename string, cmdr Card, cards []Card) {
+    var b strings.Builder
+    if format == exportMTGA { b.WriteString("Commander\n") }
+    b.WriteString(exportLine(format, cmdr) + "\n")
+    if format == exportMTGA { b.WriteString("\nDeck\n") }
+    for _, c := range cards { b.WriteString(exportLine(format, c) + "\n") }
+    w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+    w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.txt"`, filename))
+    _, _ = io.WriteString(w, b.String())
+}
+
+// browseExport is the /cards?format=json body: one browse page with the paging and filters
+// that produced it, so a script can walk the collection by following offset+limit while
+// hasNext holds. Total counts the matching cards across all pages.
+type browseExport struct {
+    Offset  int          `json:"offset"`
+    Limit   int          `json:"limit"`
+    Total   int          `json:"total"`
+    HasNext bool         `json:"hasNext"`
+    Set     string       `json:"set,omitempty"`
+    Rarity  string       `json:"rarity,omitempty"`
+    Cards   []exportCard `json:"cards"`
+}
+
+// exportCard is the stable JSON shape of a browsed card; every field is always present
+// (empty when not ingested), unlike the template-facing Card.
+type exportCard struct {
+    ScryfallID  string   `json:"scryfall_id"`
+    Name        string   `json:"name"`
+    TypeLine    string   `json:"type_line"`
+    ManaCost    string   `json:"mana_cost"`
+    CMC         float64  `json:"cmc"`
+    Colors      []string `json:"colors"`
+    OracleText  string   `json:"oracle_text"`
+    Set         string   `json:"set"`
+    Rarity      string   `json:"rarity"`
+    ImageNormal string   `json:"image_normal"`
+    PriceUSD    string   `json:"price_usd"`
+    PriceEUR    string   `json:"price_eur"`
+    PriceTix    string   `json:"price_tix"`
+}
+
+// writeBrowseJSON sends env with cards as its card list.
+func writeBrowseJSON(w http.ResponseWriter, env browseExport, cards []Card) {
+    env.Cards = make([]exportCard, len(cards))
+    for i, c := range cards {
+        colors := c.Colors
+        if colors == nil { colors = []string{} }
+        env.Cards[i] = exportCard{ScryfallID: c.ScryfallID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, CMC: c.CMC, Colors: colors, OracleText: c.OracleText, Set: c.Set, Rarity: c.Rarity, ImageNormal: c.ImageNormal, PriceUSD: c.PriceUSD, PriceEUR: c.PriceEUR, PriceTix: c.PriceTix}
+    }
+    w.Header().Set("Content-Type", "application/json")
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    _ = enc.Encode(env)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+    "html/template"
+    "regexp"
+    "strings"
+)
+
+// highlight escapes text and wraps each case-insensitive occurrence of term in <mark>. The
+// escaping happens per segment, before the marks go in, so card text can't inject markup.
+func highlight(text, term string) template.HTML {
+    term = strings.TrimSpace(term)
+    if term == "" { return template.HTML(template.HTMLEscapeString(text)) }
+    re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(term))
+    var b strings.Builder
+    last := 0
+    for _, m := range re.FindAllStringIndex(text, -1) {
+        b.WriteString(template.HTMLEscapeString(text[last:m[0]]))
+        b.WriteString("<mark>")
+        b.WriteString(template.HTMLEscapeString(text[m[0]:m[1]]))
+        b.WriteString("</mark>")
+        last = m[1]
+    }
+    b.WriteString(template.HTMLEscapeString(text[last:]))
+    return template.HTML(b.String())
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+    "bytes"
+    "container/list"
+    "context"
+    "errors"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "net/url"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/domano/decktech/pkg/scryfall"
+    client "github.com/domano/decktech/pkg/weaviateclient"
+    "golang.org/x/sync/singleflight"
+)
+
+// imageMaxAge is how long browsers may reuse a proxied image without asking again.
+const imageMaxAge = 24 * time.Hour
+
+// imageCache is the /img proxy's on-disk store of Scryfall card images, bounded by total size
+// with the least recently used files evicted first. Files older than the TTL are fetched again,
+// so re-scanned art eventually replaces stale copies. Fetches share one limiter, keeping the
+// proxy within Scryfall's request rate however many images a page shows, and concurrent
+// misses for one file share a single fetch.
+type imageCache struct {
+    dir     string
+    max     int64 // bytes
+    ttl     time.Duration
+    limiter *client.Limiter
+    fetches singleflight.Group // keyed by file name
+
+    mu    sync.Mutex
+    files map[string]*list.Element // values are *imageFile
+    lru   *list.List               // front = most recently used
+    total int64
+}
+
+type imageFile struct {
+    name string
+    size int64
+}
+
+// newImageCacheFromEnv reads WEB_IMG_CACHE_DIR (default data/img-cache), WEB_IMG_CACHE_MB
+// (default 512; 0 disables the proxy, so pages link Scryfall directly), WEB_IMG_TTL (default
+// 720h) and SCRYFALL_RPS (image fetches per second, default 10). Files already in the
+// directory are indexed oldest first. It returns nil when the proxy is disabled or the
+// directory can't be created.
+func newImageCacheFromEnv() *imageCache {
+    mb := atoiDefault(os.Getenv("WEB_IMG_CACHE_MB"), 512)
+    if mb <= 0 { return nil }
+    dir := coalesce(os.Getenv("WEB_IMG_CACHE_DIR"), filepath.Join("data", "img-cache"))
+    ttl := 720 * time.Hour
+    if v := os.Getenv("WEB_IMG_TTL"); v != "" {
+        if d, err := time.ParseDuration(v); err == nil && d > 0 { ttl = d } else { slog.Warn("ignoring invalid env value", "key", "WEB_IMG_TTL", "value", v) }
+    }
+    rps := 10.0
+    if v := os.Getenv("SCRYFALL_RPS"); v != "" {
+        if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 { rps = f } else { slog.Warn("ignoring invalid env value", "key", "SCRYFALL_RPS", "value", v) }
+    }
+    c, err := openImageCache(dir, int64(mb)<<20, ttl, client.NewLimiter(rps, 1))
+    if err != nil {
+        slog.Warn("image proxy disabled", "dir", dir, "err", err)
+        return nil
+    }
+    slog.Info("image proxy", "dir", dir, "max_mb", mb, "cached_mb", c.total>>20, "ttl", ttl, "scryfall_rps", rps)
+    return c
+}
+
+// openImageCache creates dir if needed and indexes the images already in it.
+func openImageCache(dir string, max int64, ttl time.Duration, limiter *client.Limiter) (*imageCache, error) {
+    if err := os.MkdirAll(dir, 0o755); err != nil { return nil, err }
+    entries, err := os.ReadDir(dir)
+    if err != nil { return nil, err }
+    type found struct {
+        imageFile
+        mod time.Time
+    }
+    var fs []found
+    for _, e := range entries {
+        if !e.Type().IsRegular() || strings.HasSuffix(e.Name(), ".tmp") { continue }
+        if fi, err := e.Info(); err == nil { fs = append(fs, found{imageFile{e.Name(), fi.Size()}, fi.ModTime()}) }
+    }
+    sort.Slice(fs, func(i, j int) bool { return fs[i].mod.After(fs[j].mod) })
+    c := &imageCache{dir: dir, max: max, ttl: ttl, limiter: limiter, files: map[string]*list.Element{}, lru: list.New()}
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    for _, f := range fs {
+        c.files[f.name] = c.lru.PushBack(&imageFile{f.name, f.size})
+        c.total += f.size
+    }
+    c.evict()
+    return c, nil
+}
+
+// get returns a cached image and when it was fetched, or false when it is missing or expired.
+func (c *imageCache) get(name string) ([]byte, time.Time, bool) {
+    c.mu.Lock()
+    el, ok := c.files[name]
+    if ok { c.lru.MoveToFront(el) }
+    c.mu.Unlock()
+    if !ok { return nil, time.Time{}, false }
+    path := filepath.Join(c.dir, name)
+    fi, err := os.Stat(path)
+    if err != nil || time.Since(fi.ModTime()) > c.ttl { return nil, time.Time{}, false }
+    data, err := os.ReadFile(path)
+    if err != nil { return nil, time.Time{}, false }
+    return data, fi.ModTime(), true
+}
+
+// put stores an image, replacing an older copy, then evicts down to the size bound. Writes go
+// through a uniquely named temporary file, so a concurrent get never reads a partial image and
+// concurrent puts never write into each other's file.
+func (c *imageCache) put(name string, data []byte) error {
+    f, err := os.CreateTemp(c.dir, name+".*.tmp")
+    if err != nil { return err }
+    tmp := f.Name()
+    _, err = f.Write(data)
+    if cerr := f.Close(); err == nil { err = cerr }
+    if err == nil { err = os.Chmod(tmp, 0o644) }
+    if err == nil { err = os.Rename(tmp, filepath.Join(c.dir, name)) }
+    if err != nil { _ = os.Remove(tmp); return err }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if el, ok := c.files[name]; ok {
+        f := el.Value.(*imageFile)
+        c.total += int64(len(data)) - f.size
+        f.size = int64(len(data))
+        c.lru.MoveToFront(el)
+    } else {
+        c.files[name] = c.lru.PushFront(&imageFile{name, int64(len(data))})
+        c.total += int64(len(data))
+    }
+    c.evict()
+    return nil
+}
+
+// evict removes least recently used files until the cache fits; callers hold c.mu.
+func (c *imageCache) evict() {
+    for c.total > c.max && c.lru.Len() > 0 {
+        el := c.lru.Back()
+        f := el.Value.(*imageFile)
+        c.lru.Remove(el)
+        delete(c.files, f.name)
+        c.total -= f.size
+        if err := os.Remove(filepath.Join(c.dir, f.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+            slog.Warn("image cache: evict", "file", f.name, "err", err)
+        }
+    }
+}
+
+// imageName is the cache file for one image: the Scryfall ID (checked by validImageID, so it
+// can't escape the directory), then the size and face when they aren't the defaults.
+func imageName(id, size string, face int) string {
+    name := id
+    if size != "" { name += "-" + size }
+    if face >= 0 { name += "-f" + strconv.Itoa(face) }
+    return name
+}
+
+// validImageID accepts Scryfall IDs: lowercase hex UUIDs.
+func validImageID(id string) bool {
+    if len(id) != 36 { return false }
+    for i, r := range id {
+        switch {
+        case i == 8 || i == 13 || i == 18 || i == 23:
+            if r != '-' { return false }
+        case (r < '0' || r > '9') && (r < 'a' || r > 'f'):
+            return false
+        }
+    }
+    return true
+}
+
+// imageSource is Scryfall's URL for a card's image in size (see Card.Image), or for face >= 0
+// that face's image; "" when the card has none.
+func imageSource(card Card, size string, face int) string {
+    if face < 0 { return card.Image(size) }
+    if face < len(card.Faces) { return card.Faces[face].ImageNormal }
+    return ""
+}
+
+// imageURL is the src for a card's image (see imageSource): the /img proxy when it is enabled,
+// else Scryfall's URL. It is "" when the card has no such image, so templates can fall back to
+// a placeholder.
+func (c *imageCache) imageURL(card Card, size string, face int) string {
+    direct := imageSource(card, size, face)
+    if direct == "" || c == nil || !validImageID(card.ScryfallID) { return direct }
+    q := url.Values{"id": {card.ScryfallID}}
+    if face >= 0 {
+        q.Set("face", strconv.Itoa(face))
+    } else if direct != card.ImageNormal {
+        q.Set("size", size) // only when the card has that size; otherwise the normal image is served
+    }
+    return "/img?" + q.Encode()
+}
+
+// handleImage serves /img?id=<scryfall id>[&size=small|large][&face=N] from the image cache,
+// fetching the card's image from Scryfall on a miss. Like Card.Image, a size the card lacks
+// falls back to the normal image.
+func (s *Server) handleImage(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    id := q.Get("id")
+    if !validImageID(id) {
+        http.Error(w, "id must be a Scryfall ID", http.StatusBadRequest)
+        return
+    }
+    size, face := imageSize(q.Get("size")), -1
+    if v := q.Get("face"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 0 {
+            http.Error(w, "face must be a face index (0, 1, ...)", http.StatusBadRequest)
+            return
+        }
+        face = n
+    }
+    name := imageName(id, size, face)
+    data, mod, ok := s.images.get(name)
+    if !ok {
+        var status int
+        var err error
+        data, status, err = s.loadImage(r.Context(), name, id, size, face)
+        if err != nil {
+            slog.WarnContext(r.Context(), "image proxy", "id", id, "size", size, "face", face, "err", err)
+            http.Error(w, err.Error(), status)
+            return
+        }
+        mod = time.Now()
+    }
+    w.Header().Set("Content-Type", http.DetectContentType(data))
+    w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(imageMaxAge.Seconds())))
+    http.ServeContent(w, r, "", mod, bytes.NewReader(data))
+}
+
+// loadImage fetches an image missing from the cache and stores it under name. Concurrent
+// misses for one name share a single fetch, which outlives the cancellation of the request
+// that started it so the others still get the image.
+func (s *Server) loadImage(ctx context.Context, name, id, size string, face int) ([]byte, int, error) {
+    type fetched struct {
+        data   []byte
+        status int
+    }
+    v, err, _ := s.images.fetches.Do(name, func() (any, error) {
+        data, status, err := s.fetchImage(context.WithoutCancel(ctx), id, size, face)
+        if err != nil { return fetched{status: status}, err }
+        if err := s.images.put(name, data); err != nil { slog.WarnContext(ctx, "image cache: store", "file", name, "err", err) }
+        return fetched{data: data}, nil
+    })
+    f := v.(fetched)
+    return f.data, f.status, err
+}
+
+// fetchImage looks up the card's image URL and downloads it, paced by the cache's limiter.
+// The status is the one to answer with when it fails.
+func (s *Server) fetchImage(ctx context.Context, id, size string, face int) ([]byte, int, error) {
+    ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+    defer cancel()
+    card, err := s.getCardByScryfallID(ctx, id)
+    if errors.Is(err, client.ErrCardNotFound) { return nil, http.StatusNotFound, err }
+    if err != nil { return nil, http.StatusBadGateway, err }
+    uri := imageSource(card, size, face)
+    if uri == "" { return nil, http.StatusNotFound, fmt.Errorf("card %s has no such image", id) }
+    if err := s.images.limiter.Wait(ctx); err != nil { return nil, http.StatusServiceUnavailable, err }
+    data, _, err := scryfall.FetchImage(ctx, uri)
+    if err != nil { return nil, http.StatusBadGateway, err }
+    return data, 0, nil
+}
@@ -3,15 +3,24 @@ package main
 import (
     "context"
     "embed"
+    "flag"
     "fmt"
     "html/template"
+    "io/fs"
     "math/rand"
-    "log"
+    "net/url"
+    "log/slog"
     "net/http"
     "os"
+    "path"
+    "sort"
     "strconv"
     "strings"
     "time"
+    "github.com/domano/decktech/pkg/cardfilter"
+    "github.com/domano/decktech/pkg/logging"
+    "github.com/domano/decktech/pkg/requestid"
+    "github.com/domano/decktech/pkg/server"
     client "github.com/domano/decktech/pkg/weaviateclient"
 )
 
@@ -20,13 +29,22 @@ var webFS embed.FS
 
 type Server struct {
     weaviateURL string
-    tpl         *template.Template
+    pages       map[string]*template.Template
     cli         *client.Client
+    stats       statsCache
+    sets        setsCache
+    daily       dailyCache
+    cache       *resultCache
+    images      *imageCache // nil when the /img proxy is disabled
+    checkpoint  string        // embedding checkpoint polled by /progress
+    waitMax     time.Duration // ceiling on how long /wait-import holds a request
+    sessionKey  []byte        // signs the recently viewed cookie
 }
 
 type Card struct {
     ID          string
     ScryfallID  string
+    OracleID    string
     Name        string
     TypeLine    string
     ManaCost    string
@@ -41,37 +59,86 @@ type Card struct {
     Collector   string
     Rarity      string
     Layout      string
+    ImageSmall  string
     ImageNormal string
+    ImageLarge  string
     Distance    float64
     Similarity  float64
     Legalities  map[string]string
+    Printings   int
+    Faces       []client.CardFace // set for multi-faced cards on the detail page
+    PriceUSD    string // Scryfall price strings; empty when prices were not ingested
+    PriceEUR    string
+    PriceTix    string
 }
 
 type Page struct {
     Title       string
     Query       string
     Cards       []Card
+    Groups      []CardGroup // similar page with group_by=type: Cards split into sections
+    Recent      []Card      // index page: the visitor's recently viewed cards, newest first
+    Featured    *Card       // index page: the card of the day
     Card        *Card
     Prints      []Card
     Offset      int
     Limit       int
+    Total       int // browse page: cards matching the filters across all pages
     HasPrev     bool
     HasNext     bool
     NextOffset  int
     PrevOffset  int
+    Params      template.URL // current query minus offset, for sticky page links
+    Set         string
+    SetList     []Bar // sets page: set codes with card counts
+    Rarity      string
+    Format      string // curve page: only cards legal in this format; empty for any
     K           int
+    Stats       *Stats
+    Curve       *Curve
+    Progress    *ImportProgress
+    Suggestions []string // search page: "did you mean" names offered when nothing matched
+    Notice      string
+    Seed        int64  // brew page: shuffles the suggestion pool, kept in links so a list can be reprinted; index page: the pinned ?seed=, 0 for the card of the day
+    Highlight   string // search page: term to mark in oracle text, set only for rules-text searches
+    CardID      string // similar page: Scryfall ID of the source card, for the back-link
     Error       string
+    RequestID   string // shown next to errors so users can quote it
+    ImgSize     string // ?img=small|large: card image size for the grids; empty means normal
 }
 
 func main() {
+    tlsCert := flag.String("tls-cert", os.Getenv("TLS_CERT"), "TLS certificate file (enables HTTPS with -tls-key)")
+    tlsKey := flag.String("tls-key", os.Getenv("TLS_KEY"), "TLS private key file")
+    cfgPath := flag.String("config", "", "JSON config file; env vars override its settings")
+    flag.Parse()
+    applied, cfgErr := server.ApplyConfig(*cfgPath)
+    logging.Setup()
+    if cfgErr != nil {
+        slog.Error("invalid config file", "path", *cfgPath, "err", cfgErr)
+        os.Exit(1)
+    }
+    if *cfgPath != "" { slog.Info("config file loaded", "path", *cfgPath, "applied", applied) }
+    tlsCfg, err := server.LoadTLS(*tlsCert, *tlsKey)
+    if err != nil {
+        slog.Error("invalid TLS configuration", "err", err)
+        os.Exit(1)
+    }
     weaviateURL := os.Getenv("WEAVIATE_URL")
     if weaviateURL == "" {
         weaviateURL = "http://localhost:8080"
     }
 
+    images := newImageCacheFromEnv()
     funcMap := template.FuncMap{
         "join": func(ss []string, sep string) string { return strings.Join(ss, sep) },
         "uc":   func(s string) string { return strings.ToUpper(s) },
+        "list": func(ss ...string) []string { return ss },
+        "add":  func(a, b int) int { return a + b },
+        "manaSymbols": manaSymbols,
+        "highlight":   highlight,
+        "img":         func(c Card, size string) string { return images.imageURL(c, size, -1) },
+        "faceImg":     func(c Card, face int) string { return images.imageURL(c, "", face) },
         "scryfallURL": func(c Card) string {
             if c.Set != "" && c.Collector != "" {
                 return fmt.Sprintf("https://scryfall.com/card/%s/%s", c.Set, c.Collector)
@@ -82,50 +149,69 @@ func main() {
             return "https://scryfall.com/"
         },
     }
-    tpl := template.Must(template.New("base").Funcs(funcMap).ParseFS(webFS, "templates/*.html"))
-    s := &Server{weaviateURL: weaviateURL, tpl: tpl, cli: client.NewClient(weaviateURL)}
+    s := &Server{weaviateURL: weaviateURL, pages: parsePages(funcMap), cli: client.NewClient(weaviateURL, client.RateLimitFromEnv(), client.TimeoutsFromEnv(), client.WithLogger(slog.Default())), cache: newResultCacheFromEnv(), images: images, checkpoint: checkpointPathFromEnv(), waitMax: waitMaxFromEnv(), sessionKey: sessionKeyFromEnv()}
 
     mux := http.NewServeMux()
     mux.Handle("/assets/", http.FileServer(http.FS(webFS)))
     mux.HandleFunc("/", s.handleIndex)
     mux.HandleFunc("/cards", s.handleBrowse)
+    mux.HandleFunc("/sets", s.handleSets)
     mux.HandleFunc("/search", s.handleSearch)
+    mux.HandleFunc("/autocomplete", s.handleAutocomplete)
     mux.HandleFunc("/similar", s.handleSimilar)
     mux.HandleFunc("/card", s.handleCard)
+    mux.HandleFunc("/keyword", s.handleKeyword)
+    mux.HandleFunc("/brew", s.handleBrew)
+    mux.HandleFunc("/stats", s.handleStats)
+    mux.HandleFunc("/curve", s.handleCurve)
+    mux.HandleFunc("/progress", s.handleProgress)
+    mux.HandleFunc("/wait-import", s.handleWaitImport)
+    if images != nil { mux.HandleFunc("/img", s.handleImage) }
 
-    addr := ":8090"
-    log.Printf("web browsing server on %s (WEAVIATE_URL=%s)", addr, weaviateURL)
-    if err := http.ListenAndServe(addr, logRequest(mux)); err != nil {
-        log.Fatal(err)
+    srv := &http.Server{Addr: server.ListenAddr(":8090"), Handler: logRequest(server.CORS(server.CORSOriginsFromEnv(), mux)), TLSConfig: tlsCfg}
+    slog.Info("web browsing server listening", "addr", srv.Addr, "weaviate_url", weaviateURL, "tls", tlsCfg != nil)
+    if err := server.ListenAndServe(srv); err != nil {
+        slog.Error("server error", "err", err)
+        os.Exit(1)
     }
 }
 
-func logRequest(next http.Handler) http.Handler {
-    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-        start := time.Now()
-        next.ServeHTTP(w, r)
-        log.Printf("%s %s %s", r.Method, r.URL.Path, time.Since(start))
-    })
-}
+// logRequest assigns/propagates X-Request-ID and logs method, path, status, and duration as structured fields.
+func logRequest(next http.Handler) http.Handler { return requestid.Middleware(logging.Requests(server.Gzip(next))) }
 
 func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
     ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
     defer cancel()
+    // ?seed= pins the featured card for reproducible links and screenshots; otherwise it is
+    // the card of the day.
+    seed, _ := strconv.ParseInt(r.URL.Query().Get("seed"), 10, 64)
+    var (
+        featured *Card
+        err      error
+    )
+    if seed != 0 {
+        featured, err = s.seededCard(ctx, seed)
+    } else {
+        featured, err = s.cardOfTheDay(ctx)
+    }
+    if err != nil { slog.WarnContext(ctx, "featured card unavailable", "seed", seed, "err", err) }
+    s.render(w, r, "index.html", Page{Title: "DeckTech — Browse & Search", Featured: featured, Seed: seed, Recent: s.recentCards(ctx, w, r)})
+}
+
+// randomLegends returns up to n legendary creatures in random order, drawn from a
+// name/rules-text match on "Legendary".
+func (s *Server) randomLegends(ctx context.Context, n int) ([]Card, error) {
     pool, err := s.findByNameLike(ctx, "Legendary", 400)
-    if err != nil { pool = nil }
-    picks := make([]Card, 0, 24)
+    if err != nil { return nil, err }
+    picks := make([]Card, 0, n)
     for _, c := range pool {
         if strings.Contains(c.TypeLine, "Legendary") && strings.Contains(c.TypeLine, "Creature") {
             picks = append(picks, c)
         }
     }
-    rand.Seed(time.Now().UnixNano())
-    for i := range picks {
-        j := rand.Intn(i+1)
-        picks[i], picks[j] = picks[j], picks[i]
-    }
-    if len(picks) > 24 { picks = picks[:24] }
-    s.render(w, "index.html", Page{Title: "DeckTech — Browse & Search", Cards: picks})
+    rand.Shuffle(len(picks), func(i, j int) { picks[i], picks[j] = picks[j], picks[i] })
+    if len(picks) > n { picks = picks[:n] }
+    return picks, nil
 }
 
 func (s *Server) handleBrowse(w http.ResponseWriter, r *http.Request) {
@@ -136,24 +222,41 @@ func (s *Server) handleBrowse(w http.ResponseWriter, r *http.Request) {
 
     ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
     defer cancel()
-    cards, err := s.listCards(ctx, offset, limit+1) // fetch one extra to detect next
+    set, rarity := strings.TrimSpace(q.Get("set")), strings.TrimSpace(q.Get("rarity"))
+    lctx := ctx
+    if q.Get("sort") != "" { lctx, _ = withServerSort(ctx, q) } // otherwise Weaviate's own order
+    asJSON := q.Get("format") == "json"
+    cards, total, err := s.cache.cachedPage(r, func() ([]Card, int, error) {
+        return s.listCards(lctx, offset, limit, set, rarity)
+    }, "img", "format")
+    if err != nil && asJSON {
+        http.Error(w, err.Error(), http.StatusBadGateway)
+        return
+    }
     if err != nil {
-        s.render(w, "browse.html", Page{Title: "Browse", Error: err.Error()})
+        s.render(w, r, "browse.html", Page{Title: "Browse", Error: err.Error()})
+        return
+    }
+    hasNext := offset+len(cards) < total
+    if asJSON {
+        writeBrowseJSON(w, browseExport{Offset: offset, Limit: limit, Total: total, HasNext: hasNext, Set: set, Rarity: rarity}, cards)
         return
     }
-    hasNext := false
-    if len(cards) > limit { cards = cards[:limit]; hasNext = true }
     pg := Page{
         Title:      "Browse",
         Cards:      cards,
         Offset:     offset,
         Limit:      limit,
+        Total:      total,
         HasPrev:    offset > 0,
         HasNext:    hasNext,
         PrevOffset: max(0, offset-limit),
         NextOffset: offset + limit,
+        Params:     stickyParams(q),
+        Set:        set,
+        Rarity:     rarity,
     }
-    s.render(w, "browse.html", pg)
+    s.render(w, r, "browse.html", pg)
 }
 
 func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
@@ -164,13 +267,75 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
     }
     ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
     defer cancel()
-    res, err := s.findByNameLike(ctx, q, 200)
+    byName := r.URL.Query().Get("field") == "name"
+    res, err := s.cache.cached(r, func() ([]Card, error) {
+        search := s.searchCards
+        if byName { search = s.findByNameLike }
+        sctx, sorted := withServerSort(ctx, r.URL.Query())
+        res, err := search(sctx, q, 200)
+        if err != nil { return nil, err }
+        // Without a sort, keep SearchCards' ranking: exact names first.
+        if sorted || r.URL.Query().Get("sort") == "" { return cardfilter.Filter(res, r.URL.Query()), nil }
+        return cardfilter.Apply(res, r.URL.Query(), false), nil
+    }, pageParams...)
     if err != nil {
-        s.render(w, "results.html", Page{Title: "Search", Query: q, Error: err.Error()})
+        s.render(w, r, "results.html", Page{Title: "Search", Query: q, Error: err.Error()})
         return
     }
-    res = applyFiltersSort(res, r.URL.Query(), false)
-    s.render(w, "results.html", Page{Title: "Search", Query: q, Cards: res})
+    if len(res) == 0 {
+        // No substring match: fall back to typo-tolerant matching, offering the closest names.
+        // Only empty searches pay for the broadened query.
+        pg := Page{Title: "Search", Query: q, Notice: "No cards match “" + q + "”."}
+        if fz, err := s.cli.SearchNameFuzzy(ctx, q, 24); err == nil && len(fz) > 0 {
+            pg.Cards, pg.Suggestions = toWebCards(fz), suggestionNames(fz, maxSuggestions)
+        }
+        s.render(w, r, "results.html", pg)
+        return
+    }
+    pg := Page{Title: "Search", Query: q}
+    if !byName { pg.Highlight = q }
+    paginate(&pg, res, r.URL.Query())
+    s.render(w, r, "results.html", pg)
+}
+
+// maxSuggestions caps the "did you mean" names shown for an empty search.
+const maxSuggestions = 8
+
+// suggestionNames returns up to n distinct names from fuzzy matches, best first.
+func suggestionNames(cards []client.Card, n int) []string {
+    var out []string
+    seen := map[string]bool{}
+    for _, c := range cards {
+        if key := strings.ToLower(c.Name); !seen[key] {
+            seen[key] = true
+            out = append(out, c.Name)
+        }
+        if len(out) == n { break }
+    }
+    return out
+}
+
+// handleKeyword lists cards sharing one or more keywords, e.g. /keyword?kw=Flashback.
+// Multiple keywords are comma-separated; match=all requires every keyword, otherwise any.
+func (s *Server) handleKeyword(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    kw := strings.TrimSpace(q.Get("kw"))
+    if kw == "" {
+        http.Redirect(w, r, "/", http.StatusSeeOther)
+        return
+    }
+    matchAll := q.Get("match") == "all"
+    ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+    defer cancel()
+    sctx, sorted := withServerSort(ctx, q)
+    res, err := s.cli.FindByKeywords(sctx, strings.Split(kw, ","), matchAll, 200)
+    if err != nil {
+        s.render(w, r, "results.html", Page{Title: "Keyword", Query: kw, Error: err.Error()})
+        return
+    }
+    cards := toWebCards(res)
+    if sorted { cards = cardfilter.Filter(cards, q) } else { cards = cardfilter.Apply(cards, q, false) }
+    s.render(w, r, "results.html", Page{Title: "Keyword", Query: kw, Cards: cards})
 }
 
 func (s *Server) handleSimilar(w http.ResponseWriter, r *http.Request) {
@@ -178,203 +343,289 @@ func (s *Server) handleSimilar(w http.ResponseWriter, r *http.Request) {
     name := strings.TrimSpace(q.Get("name"))
     id := strings.TrimSpace(q.Get("id"))
     k := atoiDefault(q.Get("k"), 200)
-    if k < 200 { k = 200 }
+    if k < 1 { k = 200 }
     if k > 500 { k = 500 }
+    format := strings.ToLower(strings.TrimSpace(q.Get("format")))
+    minSim, _ := strconv.ParseFloat(q.Get("min_sim"), 64)
+    if minSim < 0 { minSim = 0 }
+    if minSim > 1 { minSim = 1 }
     if name == "" && id == "" {
         http.Redirect(w, r, "/", http.StatusSeeOther)
         return
     }
     ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
     defer cancel()
-    var vec []float64
-    var err error
-    if id != "" {
-        vec, _, err = s.cli.FetchVectorByScryfallID(ctx, id)
-    } else {
-        vec, _, err = s.cli.FetchVectorForName(ctx, name)
-    }
-    if err != nil {
-        s.render(w, "results.html", Page{Title: "Similar", Query: coalesce(name, id), Error: err.Error()})
-        return
-    }
-    resC, err := s.cli.SearchNearVector(ctx, vec, k)
+    cards, err := s.cache.cached(r, func() ([]Card, error) {
+        var vec []float64
+        var err error
+        if id != "" {
+            vec, _, err = s.cli.FetchVectorByScryfallID(ctx, id)
+            if err != nil && name != "" {
+                // some ingested cards lack scryfall_id (or this printing has no vector); try by name
+                slog.DebugContext(ctx, "similar: id lookup failed, falling back to name", "id", id, "name", name, "err", err)
+                vec, _, err = s.cli.FetchVectorForName(ctx, name)
+            }
+        } else {
+            vec, _, err = s.cli.FetchVectorForName(ctx, name)
+        }
+        if err != nil { return nil, err }
+        var where *client.WhereFilter
+        if format != "" { where = client.LegalIn(format) } // filter in Weaviate so the top-k is all format-legal
+        fetch := k
+        if minSim > 0 { fetch = 2 * k } // nearVector can't threshold server-side; over-fetch, then trim
+        resC, err := s.cli.SearchNearVectorFiltered(ctx, vec, fetch, where)
+        if err != nil { return nil, err }
+        if q.Get("dedupe_by_name") == "1" {
+            resC = client.DedupeByName(resC) // before sorting, so the best printing per name is kept
+        }
+        if minSim > 0 {
+            n := 0
+            for n < len(resC) && n < k && resC[n].Similarity >= minSim { n++ } // still in distance order
+            resC = resC[:n]
+        }
+        cards := make([]Card, 0, len(resC))
+        for _, c := range resC {
+            cards = append(cards, Card{ID: c.ID, ScryfallID: c.ScryfallID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, OracleText: c.OracleText, ImageSmall: c.ImageSmall, ImageNormal: c.ImageNormal, ImageLarge: c.ImageLarge, Distance: c.Distance, Similarity: c.Similarity, Printings: c.Printings, PriceUSD: c.PriceUSD, PriceEUR: c.PriceEUR, PriceTix: c.PriceTix})
+        }
+        return cardfilter.Apply(cards, r.URL.Query(), true), nil
+    }, pageParams...)
     if err != nil {
-        s.render(w, "results.html", Page{Title: "Similar", Query: coalesce(name, id), Error: err.Error()})
+        s.render(w, r, "results.html", Page{Title: "Similar", Query: coalesce(name, id), CardID: id, Error: err.Error()})
         return
     }
-    cards := make([]Card, 0, len(resC))
-    for _, c := range resC {
-        cards = append(cards, Card{ID: c.ID, ScryfallID: c.ScryfallID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, OracleText: c.OracleText, ImageNormal: c.ImageNormal, Distance: c.Distance, Similarity: c.Similarity})
+    pg := Page{Title: "Similar", Query: coalesce(name, id), K: k, CardID: id}
+    switch {
+    case minSim > 0 && len(cards) < k:
+        pg.Notice = fmt.Sprintf("Only %d matches at ≥ %.2f similarity (asked for %d).", len(cards), minSim, k)
+    case format != "" && len(cards) < k:
+        pg.Notice = fmt.Sprintf("Only %d %s-legal matches found (asked for %d).", len(cards), format, k)
     }
-    cards = applyFiltersSort(cards, r.URL.Query(), true)
-    s.render(w, "results.html", Page{Title: "Similar", Query: coalesce(name, id), Cards: cards, K: k})
+    paginate(&pg, cards, q)
+    if q.Get("group_by") == "type" { pg.Groups = groupByType(pg.Cards) }
+    s.render(w, r, "results.html", pg)
 }
 
 func (s *Server) handleCard(w http.ResponseWriter, r *http.Request) {
-    id := strings.TrimSpace(r.URL.Query().Get("id"))
-    if id == "" {
+    q := r.URL.Query()
+    id := strings.TrimSpace(q.Get("id"))
+    set, cn := strings.TrimSpace(q.Get("set")), strings.TrimSpace(q.Get("cn"))
+    if id == "" && (set == "" || cn == "") {
         http.Redirect(w, r, "/", http.StatusSeeOther)
         return
     }
     ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
     defer cancel()
-    card, err := s.getCardByScryfallID(ctx, id)
+    ctx = client.WithPartialResults(ctx) // render what resolved rather than an error page
+    var card Card
+    var err error
+    if id != "" {
+        card, err = s.getCardByScryfallID(ctx, id)
+    } else {
+        card, err = s.getCardBySetAndCollector(ctx, set, cn) // a printing deep link: /card?set=neo&cn=100
+    }
     if err != nil {
-        s.render(w, "card.html", Page{Title: "Card", Error: err.Error()})
+        s.render(w, r, "card.html", Page{Title: "Card", Error: err.Error()})
         return
     }
-    // Attempt to load all printings by name (works without oracle_id)
-    prints, _ := s.listPrintingsByName(ctx, card.Name, 200)
-    s.render(w, "card.html", Page{Title: card.Name, Card: &card, Prints: prints})
+    // Printings share an oracle_id, which also groups split/adventure/DFC printings correctly;
+    // collections ingested before oracle_id fall back to matching by name.
+    var prints []Card
+    if card.OracleID != "" {
+        prints, err = s.listPrintingsByOracleID(ctx, card.OracleID, 200)
+    }
+    if card.OracleID == "" || err != nil || len(prints) == 0 {
+        prints, _ = s.listPrintingsByName(ctx, card.Name, 200)
+    }
+    if card.ScryfallID != "" { s.setRecent(w, r, pushRecent(s.recentIDs(r), card.ScryfallID)) }
+    s.render(w, r, "card.html", Page{Title: card.Name, Card: &card, Prints: prints})
+}
+
+// pageParams are applied after fetching, so they're excluded from result cache keys.
+var pageParams = []string{"offset", "limit", "img"}
+
+// paginate slices an already filtered/sorted result list using ?offset=&limit= (default 20, max 100)
+// and fills the pager fields, keeping all other query params sticky in the page links.
+func paginate(pg *Page, cards []Card, q url.Values) {
+    offset := atoiDefault(q.Get("offset"), 0)
+    limit := atoiDefault(q.Get("limit"), 20)
+    if limit <= 0 || limit > 100 { limit = 20 }
+    if offset < 0 { offset = 0 }
+    end := min(offset+limit, len(cards))
+    if offset < len(cards) { pg.Cards = cards[offset:end] }
+    pg.Offset, pg.Limit = offset, limit
+    pg.HasPrev, pg.HasNext = offset > 0, end < len(cards)
+    pg.PrevOffset, pg.NextOffset = max(0, offset-limit), offset+limit
+    pg.Params = stickyParams(q)
+}
+
+// stickyParams encodes the non-empty query params except offset, for pager links.
+func stickyParams(q url.Values) template.URL {
+    rest := url.Values{}
+    for k, vs := range q {
+        if k == "offset" { continue }
+        for _, v := range vs { if v != "" { rest.Add(k, v) } }
+    }
+    return template.URL(rest.Encode())
 }
 
 // Rendering
-func (s *Server) render(w http.ResponseWriter, name string, data Page) {
+
+// parsePages builds one template set per page (base.html + the page) so that each
+// page's "content" block doesn't get overwritten by the last file parsed.
+func parsePages(funcMap template.FuncMap) map[string]*template.Template {
+    base := template.Must(template.New("base").Funcs(funcMap).ParseFS(webFS, "templates/base.html"))
+    files, err := fs.Glob(webFS, "templates/*.html")
+    if err != nil { panic(err) }
+    pages := map[string]*template.Template{}
+    for _, f := range files {
+        name := path.Base(f)
+        if name == "base.html" { continue }
+        pages[name] = template.Must(template.Must(base.Clone()).ParseFS(webFS, f))
+    }
+    return pages
+}
+
+func (s *Server) render(w http.ResponseWriter, r *http.Request, name string, data Page) {
+    tpl, ok := s.pages[name]
+    if !ok {
+        http.Error(w, "unknown template "+name, http.StatusInternalServerError)
+        return
+    }
+    if data.Error != "" {
+        slog.WarnContext(r.Context(), "upstream error", "page", name, "query", data.Query, "err", data.Error)
+        if id := requestid.FromContext(r.Context()); !strings.Contains(data.Error, id) { data.RequestID = id }
+    }
+    data.ImgSize = imageSize(r.URL.Query().Get("img"))
     w.Header().Set("Content-Type", "text/html; charset=utf-8")
-    if err := s.tpl.ExecuteTemplate(w, name, data); err != nil {
+    if err := tpl.ExecuteTemplate(w, name, data); err != nil {
+        slog.ErrorContext(r.Context(), "template error", "page", name, "err", err)
         http.Error(w, err.Error(), http.StatusInternalServerError)
     }
 }
 
-func (s *Server) listCards(ctx context.Context, offset, limit int) ([]Card, error) {
-    res, err := s.cli.ListCards(ctx, offset, limit)
-    if err != nil { return nil, err }
-    out := make([]Card, 0, len(res))
-    for _, c := range res {
-        out = append(out, Card{ID: c.ID, ScryfallID: c.ScryfallID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, OracleText: c.OracleText, ImageNormal: c.ImageNormal})
-    }
-    return out, nil
+func (s *Server) listCards(ctx context.Context, offset, limit int, set, rarity string) ([]Card, int, error) {
+    res, total, err := s.cli.ListCardsFilteredPage(ctx, offset, limit, set, rarity)
+    if err != nil { return nil, 0, err }
+    return toWebCards(res), total, nil
 }
 
 func (s *Server) listPrintingsByName(ctx context.Context, name string, limit int) ([]Card, error) {
     res, err := s.cli.ListPrintingsByName(ctx, name, limit)
     if err != nil { return nil, err }
+    return toPrints(res), nil
+}
+
+func (s *Server) listPrintingsByOracleID(ctx context.Context, oracleID string, limit int) ([]Card, error) {
+    res, err := s.cli.ListPrintingsByOracleID(ctx, oracleID, limit)
+    if err != nil { return nil, err }
+    return toPrints(res), nil
+}
+
+func toPrints(res []client.Card) []Card {
     out := make([]Card, 0, len(res))
     for _, c := range res {
-        out = append(out, Card{ID: c.ID, ScryfallID: c.ScryfallID, Set: c.Set, Collector: c.CollectorNum, Rarity: c.Rarity, ImageNormal: c.ImageNormal})
+        out = append(out, Card{ID: c.ID, ScryfallID: c.ScryfallID, Set: c.Set, Collector: c.CollectorNum, Rarity: c.Rarity, ImageSmall: c.ImageSmall, ImageNormal: c.ImageNormal, ImageLarge: c.ImageLarge})
     }
-    // simple lexicographic sort by set then collector number (numeric if possible)
     sortPrints(out)
-    return out, nil
+    return out
 }
 
+// sortPrints orders printings by set, then collector number (numerically when both are numbers).
 func sortPrints(cs []Card) {
-    // attempt numeric collector ordering
-    parseNum := func(s string) (int, bool) {
-        n, err := strconv.Atoi(s)
-        if err != nil { return 0, false }
-        return n, true
-    }
-    // stable sort: set asc, collector numeric asc if possible, else lex
-    for i := 0; i < len(cs)-1; i++ {
-        for j := i + 1; j < len(cs); j++ {
-            a, b := cs[i], cs[j]
-            if a.Set == b.Set {
-                an, okA := parseNum(a.Collector)
-                bn, okB := parseNum(b.Collector)
-                swap := false
-                if okA && okB {
-                    swap = an > bn
-                } else {
-                    swap = a.Collector > b.Collector
-                }
-                if swap { cs[i], cs[j] = cs[j], cs[i] }
-            } else if a.Set > b.Set {
-                cs[i], cs[j] = cs[j], cs[i]
-            }
-        }
-    }
+    sort.SliceStable(cs, func(i, j int) bool {
+        a, b := cs[i], cs[j]
+        if a.Set != b.Set { return a.Set < b.Set }
+        an, errA := strconv.Atoi(a.Collector)
+        bn, errB := strconv.Atoi(b.Collector)
+        if errA == nil && errB == nil { return an < bn }
+        return a.Collector < b.Collector
+    })
 }
 
-func (s *Server) findByNameLike(ctx context.Context, name string, limit int) ([]Card, error) {
-    res, err := s.cli.FindByNameLike(ctx, name, limit)
-    if err != nil { return nil, err }
+// serverSorts maps the web sort keys Weaviate can order by to their Card property. Other keys
+// (similarity, and price, which lives in a JSON string) are sorted in Go by cardfilter.
+var serverSorts = map[string]string{"name": "name", "cmc": "cmc", "edhrec": "edhrec_rank"}
+
+// withServerSort asks Weaviate for q's sort and order (descending by default) when q names a
+// key it can sort by, and reports whether it did, so the caller filters without re-sorting.
+// Without a sort param it leaves ctx alone, keeping the caller's default order.
+func withServerSort(ctx context.Context, q url.Values) (context.Context, bool) {
+    path, ok := serverSorts[q.Get("sort")]
+    if !ok { return ctx, false }
+    return client.WithSort(ctx, path, q.Get("order") != "asc"), true
+}
+
+// toWebCards maps list-style client cards onto the template Card.
+func toWebCards(res []client.Card) []Card {
     out := make([]Card, 0, len(res))
     for _, c := range res {
-        out = append(out, Card{ID: c.ID, ScryfallID: c.ScryfallID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, CMC: c.CMC, Colors: c.Colors, OracleText: c.OracleText, ImageNormal: c.ImageNormal})
+        out = append(out, Card{ID: c.ID, ScryfallID: c.ScryfallID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, CMC: c.CMC, Colors: c.Colors, Set: c.Set, Collector: c.CollectorNum, Rarity: c.Rarity, OracleText: c.OracleText, ImageSmall: c.ImageSmall, ImageNormal: c.ImageNormal, ImageLarge: c.ImageLarge, PriceUSD: c.PriceUSD, PriceEUR: c.PriceEUR, PriceTix: c.PriceTix})
     }
-    return out, nil
+    return out
 }
 
-// Filters and sorters
-func applyFiltersSort(cards []Card, q map[string][]string, isSimilar bool) []Card {
-    wantLegendary := qValue(q, "legendary") == "1"
-    typeFilter := strings.TrimSpace(qValue(q, "type"))
-    colorsStr := strings.ReplaceAll(strings.TrimSpace(qValue(q, "colors")), " ", "")
-    var colors []string
-    if colorsStr != "" { colors = strings.Split(colorsStr, ",") }
-    cmcMin := atoiDefault(qValue(q, "cmc_min"), -1)
-    cmcMax := atoiDefault(qValue(q, "cmc_max"), -1)
-
-    out := make([]Card, 0, len(cards))
-    for _, c := range cards {
-        if wantLegendary && !strings.Contains(c.TypeLine, "Legendary") { continue }
-        if typeFilter != "" && !strings.Contains(strings.ToLower(c.TypeLine), strings.ToLower(typeFilter)) { continue }
-        if len(colors) > 0 {
-            if !containsAllColors(c.Colors, colors) { continue }
-        }
-        if cmcMin >= 0 && int(c.CMC) < cmcMin { continue }
-        if cmcMax >= 0 && int(c.CMC) > cmcMax { continue }
-        out = append(out, c)
-    }
-    sortKey := qValue(q, "sort")
-    order := qValue(q, "order")
-    if sortKey == "" {
-        if isSimilar { sortKey = "similarity" } else { sortKey = "name" }
-    }
-    desc := (order == "desc" || order == "")
-    sortCards(out, sortKey, desc)
-    return out
+// searchCards matches names and rules text; exact-name hits come first.
+func (s *Server) searchCards(ctx context.Context, query string, limit int) ([]Card, error) {
+    res, err := s.cli.SearchCards(ctx, query, limit)
+    if err != nil { return nil, err }
+    return toWebCards(res), nil
 }
 
-func qValue(q map[string][]string, k string) string { if v, ok := q[k]; ok && len(v) > 0 { return v[0] }; return "" }
+func (s *Server) findByNameLike(ctx context.Context, name string, limit int) ([]Card, error) {
+    res, err := s.cli.FindByNameLike(ctx, name, limit)
+    if err != nil { return nil, err }
+    return toWebCards(res), nil
+}
 
-func containsAllColors(have []string, want []string) bool {
-    set := map[string]struct{}{}
-    for _, c := range have { set[strings.ToUpper(strings.TrimSpace(c))] = struct{}{} }
-    for _, c := range want {
-        c = strings.ToUpper(strings.TrimSpace(c))
-        if c == "" { continue }
-        if _, ok := set[c]; !ok { return false }
+// imageSize reads an image size param such as ?img=; only Scryfall's small and large sizes are
+// kept, anything else is normal.
+func imageSize(v string) string {
+    switch v = strings.ToLower(v); v {
+    case "small", "large":
+        return v
     }
-    return true
+    return ""
 }
 
-func sortCards(cs []Card, key string, desc bool) {
-    less := func(i, j int) bool { return false }
-    switch key {
-    case "cmc":
-        less = func(i, j int) bool { if cs[i].CMC == cs[j].CMC { return cs[i].Name < cs[j].Name }; return cs[i].CMC < cs[j].CMC }
-    case "name":
-        less = func(i, j int) bool { return cs[i].Name < cs[j].Name }
-    case "similarity":
-        less = func(i, j int) bool { if cs[i].Similarity == cs[j].Similarity { return cs[i].Name < cs[j].Name }; return cs[i].Similarity < cs[j].Similarity }
-    default:
-        less = func(i, j int) bool { return cs[i].Name < cs[j].Name }
-    }
-    for i := 1; i < len(cs); i++ {
-        j := i
-        for j > 0 {
-            a, b := j-1, j
-            cmp := less(a, b)
-            if desc { cmp = !cmp }
-            if cmp { break }
-            cs[a], cs[b] = cs[b], cs[a]
-            j--
-        }
+// Image returns the card's image URL in size (see imageSize), falling back to the normal image
+// when that size is empty, e.g. for collections ingested before image_large.
+func (c Card) Image(size string) string {
+    switch {
+    case size == "small" && c.ImageSmall != "":
+        return c.ImageSmall
+    case size == "large" && c.ImageLarge != "":
+        return c.ImageLarge
     }
+    return c.ImageNormal
+}
+
+// FilterFields exposes the card to pkg/cardfilter.
+func (c Card) FilterFields() cardfilter.Fields {
+    return cardfilter.Fields{Name: c.Name, TypeLine: c.TypeLine, Colors: c.Colors, CMC: c.CMC, Similarity: c.Similarity, PriceUSD: c.PriceUSD}
 }
 
 
 func (s *Server) getCardByScryfallID(ctx context.Context, scryfallID string) (Card, error) {
     c, err := s.cli.GetCardByScryfallID(ctx, scryfallID)
     if err != nil { return Card{}, err }
+    return detailCard(c), nil
+}
+
+func (s *Server) getCardBySetAndCollector(ctx context.Context, set, collector string) (Card, error) {
+    c, err := s.cli.GetCardBySetAndCollector(ctx, set, collector)
+    if err != nil { return Card{}, err }
+    return detailCard(c), nil
+}
+
+// detailCard maps a detail-query client card onto the template Card.
+func detailCard(c client.Card) Card {
     return Card{
-        ID: c.ID, ScryfallID: c.ScryfallID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, CMC: c.CMC,
+        ID: c.ID, ScryfallID: c.ScryfallID, OracleID: c.OracleID, Name: c.Name, TypeLine: c.TypeLine, ManaCost: c.ManaCost, CMC: c.CMC,
         OracleText: c.OracleText, Power: c.Power, Toughness: c.Toughness, Colors: c.Colors, ColorID: c.ColorID,
         Keywords: c.Keywords, Set: c.Set, Collector: c.CollectorNum, Rarity: c.Rarity, Layout: c.Layout,
-        ImageNormal: c.ImageNormal, Legalities: c.Legalities,
-    }, nil
+        ImageSmall: c.ImageSmall, ImageNormal: c.ImageNormal, ImageLarge: c.ImageLarge, Legalities: c.Legalities, Faces: c.Faces,
+        PriceUSD: c.PriceUSD, PriceEUR: c.PriceEUR, PriceTix: c.PriceTix,
+    }
 }
 
 // Helpers
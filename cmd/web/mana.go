@@ -0,0 +1,67 @@
+package main
+
+import (
+    "html/template"
+    "strings"
+)
+
+// manaColors maps single-letter symbols to their --mana-* CSS variable suffix.
+var manaColors = map[string]string{"W": "w", "U": "u", "B": "b", "R": "r", "G": "g", "C": "c"}
+
+// manaSymbols renders a cost like "{2}{U}{W/U}{G/P}" as pip spans (`<span class="ms ms-u">U</span>`)
+// styled in assets/style.css. Hybrid and Phyrexian pips get ms-hybrid / ms-phyrexian; numbers and
+// X/Y/Z are ms-generic. Text between tokens (e.g. " // " on split cards) and unrecognized tokens
+// are emitted escaped, as-is.
+func manaSymbols(cost string) template.HTML {
+    var b strings.Builder
+    for cost != "" {
+        open := strings.IndexByte(cost, '{')
+        if open < 0 { b.WriteString(template.HTMLEscapeString(cost)); break }
+        close := strings.IndexByte(cost[open:], '}')
+        if close < 0 { b.WriteString(template.HTMLEscapeString(cost)); break }
+        close += open
+        b.WriteString(template.HTMLEscapeString(cost[:open]))
+        b.WriteString(manaPip(cost[open : close+1]))
+        cost = cost[close+1:]
+    }
+    return template.HTML(`<span class="mana">` + b.String() + `</span>`)
+}
+
+// manaPip renders one {…} token, or returns it escaped when it isn't a known symbol.
+func manaPip(token string) string {
+    sym := strings.ToUpper(strings.Trim(token, "{}"))
+    esc := template.HTMLEscapeString(sym)
+    pip := func(class, style string) string {
+        if style != "" { style = ` style="` + style + `"` }
+        return `<span class="ms ` + class + `" title="` + esc + `"` + style + `>` + esc + `</span>`
+    }
+    if c, ok := manaColors[sym]; ok { return pip("ms-"+c, "") }
+    if isDigits(sym) || sym == "X" || sym == "Y" || sym == "Z" { return pip("ms-generic", "") }
+    switch sym {
+    case "S":
+        return pip("ms-snow", "")
+    case "T", "Q":
+        return pip("ms-tap", "")
+    }
+    base, phyrexian := strings.CutSuffix(sym, "/P")
+    extra := ""
+    if phyrexian { extra = " ms-phyrexian" }
+    if c, ok := manaColors[base]; ok && phyrexian { return pip("ms-"+c+extra, "") }
+    if a, bb, ok := strings.Cut(base, "/"); ok {
+        ca, okA := manaColors[a]
+        cb, okB := manaColors[bb]
+        if a == "2" { ca, okA = "generic", true }
+        if okA && okB {
+            return pip("ms-hybrid"+extra, "--a:var(--mana-"+ca+");--b:var(--mana-"+cb+")")
+        }
+    }
+    return template.HTMLEscapeString(token)
+}
+
+func isDigits(s string) bool {
+    if s == "" { return false }
+    for _, r := range s {
+        if r < '0' || r > '9' { return false }
+    }
+    return true
+}
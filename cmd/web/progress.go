@@ -0,0 +1,138 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+
+    prg "github.com/domano/decktech/pkg/progress"
+)
+
+// progressInterval is how often /progress re-reads the checkpoint; an unchanged state is
+// not re-sent, but a keepalive comment goes out every progressKeepalive ticks.
+const (
+    progressInterval  = time.Second
+    progressKeepalive = 15
+)
+
+// ImportProgress is the embedding checkpoint as shown on /progress and sent as SSE data.
+type ImportProgress struct {
+    Active     bool    `json:"-"`
+    NextOffset int     `json:"next_offset"`
+    Total      int     `json:"total"`
+    Percent    float64 `json:"percent"`
+}
+
+// checkpointPathFromEnv returns CHECKPOINT, defaulting to the decktech TUI's checkpoint path.
+func checkpointPathFromEnv() string {
+    if p := os.Getenv("CHECKPOINT"); p != "" { return p }
+    return "data/embedding_progress.json"
+}
+
+// readProgress loads the checkpoint; a missing or unreadable file means no active import.
+func (s *Server) readProgress() ImportProgress {
+    cp, err := prg.ReadCheckpoint(s.checkpoint)
+    if err != nil || cp.Total <= 0 { return ImportProgress{} }
+    return ImportProgress{Active: true, NextOffset: cp.NextOffset, Total: cp.Total, Percent: 100 * float64(cp.NextOffset) / float64(cp.Total)}
+}
+
+// handleProgress streams checkpoint updates as server-sent events to EventSource clients
+// (Accept: text/event-stream) and renders the progress page for everyone else.
+func (s *Server) handleProgress(w http.ResponseWriter, r *http.Request) {
+    if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+        p := s.readProgress()
+        s.render(w, r, "progress.html", Page{Title: "Import Progress", Progress: &p})
+        return
+    }
+    rc := http.NewResponseController(w)
+    w.Header().Set("Content-Type", "text/event-stream")
+    w.Header().Set("Cache-Control", "no-cache")
+    w.Header().Set("X-Accel-Buffering", "no") // keep reverse proxies from buffering the stream
+    w.WriteHeader(http.StatusOK)
+
+    tick := time.NewTicker(progressInterval)
+    defer tick.Stop()
+    last, quiet := "", 0
+    for {
+        event, data := "idle", `{"message":"no active import"}`
+        if p := s.readProgress(); p.Active {
+            b, _ := json.Marshal(p)
+            event, data = "progress", string(b)
+        }
+        if msg := event + data; msg != last {
+            fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+            last, quiet = msg, 0
+        } else if quiet++; quiet >= progressKeepalive {
+            fmt.Fprint(w, ": keepalive\n\n")
+            quiet = 0
+        }
+        if err := rc.Flush(); err != nil { return }
+        select {
+        case <-r.Context().Done():
+            return // client went away
+        case <-tick.C:
+        }
+    }
+}
+
+// waitMaxFromEnv returns WAIT_IMPORT_MAX (a duration such as "2m"), defaulting to a minute.
+func waitMaxFromEnv() time.Duration {
+    if v := os.Getenv("WAIT_IMPORT_MAX"); v != "" {
+        if d, err := time.ParseDuration(v); err == nil && d > 0 { return d }
+    }
+    return time.Minute
+}
+
+// importWait is the /wait-import response: the checkpoint when the wait ended and whether
+// it reached the target (false means the wait timed out; poll again).
+type importWait struct {
+    Done   bool `json:"done"`
+    Active bool `json:"active"`
+    ImportProgress
+}
+
+// handleWaitImport long-polls the checkpoint: /wait-import?target_offset=N[&timeout=30s] returns
+// once NextOffset >= N, or when the timeout (capped at waitMax) passes, with the status as JSON.
+// A client that disconnects ends the wait at the next poll without a response.
+func (s *Server) handleWaitImport(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    target, err := strconv.Atoi(q.Get("target_offset"))
+    if err != nil || target < 0 {
+        http.Error(w, "target_offset must be a non-negative integer", http.StatusBadRequest)
+        return
+    }
+    wait := s.waitMax
+    if v := q.Get("timeout"); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d <= 0 {
+            http.Error(w, "timeout must be a positive duration such as 30s", http.StatusBadRequest)
+            return
+        }
+        wait = min(d, s.waitMax)
+    }
+    timeout := time.NewTimer(wait)
+    defer timeout.Stop()
+    tick := time.NewTicker(progressInterval)
+    defer tick.Stop()
+    for {
+        p := s.readProgress()
+        done := p.Active && p.NextOffset >= target
+        if !done {
+            select {
+            case <-r.Context().Done():
+                return // client went away
+            case <-tick.C:
+                continue
+            case <-timeout.C:
+            }
+        }
+        w.Header().Set("Content-Type", "application/json")
+        w.Header().Set("Cache-Control", "no-store")
+        _ = json.NewEncoder(w).Encode(importWait{Done: done, Active: p.Active, ImportProgress: p})
+        return
+    }
+}
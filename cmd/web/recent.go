@@ -0,0 +1,90 @@
+package main
+
+import (
+    "context"
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/base64"
+    "log/slog"
+    "net/http"
+    "os"
+    "strings"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// recentCookie holds the visitor's recently viewed Scryfall IDs, newest first, signed with
+// the server's session key so clients can't plant IDs; recentMax caps the list.
+const (
+    recentCookie = "recent"
+    recentMax    = 12
+    recentMaxAge = 30 * 24 * 60 * 60 // seconds
+)
+
+// sessionKeyFromEnv returns SESSION_KEY, or a random key when unset; with a random key the
+// recently viewed lists start over whenever the server restarts.
+func sessionKeyFromEnv() []byte {
+    if k := os.Getenv("SESSION_KEY"); k != "" { return []byte(k) }
+    key := make([]byte, 32)
+    if _, err := rand.Read(key); err != nil { panic(err) }
+    slog.Info("SESSION_KEY not set; using a random key, recently viewed cards reset on restart")
+    return key
+}
+
+func (s *Server) sign(payload string) string {
+    mac := hmac.New(sha256.New, s.sessionKey)
+    mac.Write([]byte(payload))
+    return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// recentIDs returns the IDs from a validly signed cookie; a missing or tampered cookie is empty.
+func (s *Server) recentIDs(r *http.Request) []string {
+    ck, err := r.Cookie(recentCookie)
+    if err != nil { return nil }
+    payload, sig, ok := strings.Cut(ck.Value, ".")
+    if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(payload))) { return nil }
+    raw, err := base64.RawURLEncoding.DecodeString(payload)
+    if err != nil || len(raw) == 0 { return nil }
+    ids := strings.Split(string(raw), ",")
+    if len(ids) > recentMax { ids = ids[:recentMax] }
+    return ids
+}
+
+func (s *Server) setRecent(w http.ResponseWriter, r *http.Request, ids []string) {
+    payload := base64.RawURLEncoding.EncodeToString([]byte(strings.Join(ids, ",")))
+    ck := &http.Cookie{Name: recentCookie, Value: payload + "." + s.sign(payload), Path: "/", MaxAge: recentMaxAge, HttpOnly: true, Secure: r.TLS != nil, SameSite: http.SameSiteLaxMode}
+    if len(ids) == 0 { ck.Value, ck.MaxAge = "", -1 }
+    http.SetCookie(w, ck)
+}
+
+// pushRecent moves id to the front of ids, dropping an older occurrence and anything past recentMax.
+func pushRecent(ids []string, id string) []string {
+    out := []string{id}
+    for _, v := range ids {
+        if v != id && len(out) < recentMax { out = append(out, v) }
+    }
+    return out
+}
+
+// recentCards batch-fetches the visitor's recently viewed cards in cookie order. IDs that no
+// longer resolve are dropped, and the cookie is rewritten without them.
+func (s *Server) recentCards(ctx context.Context, w http.ResponseWriter, r *http.Request) []Card {
+    ids := s.recentIDs(r)
+    if len(ids) == 0 { return nil }
+    found, err := s.cli.GetCardsByScryfallIDs(ctx, ids)
+    if err != nil {
+        slog.WarnContext(ctx, "recently viewed lookup failed", "err", err)
+        return nil
+    }
+    var cards []client.Card
+    var kept []string
+    for _, id := range ids {
+        if c, ok := found[id]; ok {
+            cards = append(cards, c)
+            kept = append(kept, id)
+        }
+    }
+    if len(kept) != len(ids) { s.setRecent(w, r, kept) }
+    return toWebCards(cards)
+}
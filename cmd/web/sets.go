@@ -0,0 +1,49 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "sort"
+    "sync"
+    "time"
+)
+
+// setsTTL bounds how often /sets re-runs its groupBy; the set list only changes on import.
+const setsTTL = 10 * time.Minute
+
+type setsCache struct {
+    mu   sync.Mutex
+    at   time.Time
+    sets []Bar
+}
+
+// handleSets lists every imported set with its card count, each linking to /cards?set=….
+func (s *Server) handleSets(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+    defer cancel()
+    sets, err := s.loadSets(ctx)
+    if err != nil {
+        s.render(w, r, "sets.html", Page{Title: "Sets", Error: err.Error()})
+        return
+    }
+    s.render(w, r, "sets.html", Page{Title: "Sets", SetList: sets})
+}
+
+// loadSets returns the cached set list when fresh, otherwise groups cards by set code.
+// Release dates aren't ingested, so sets are sorted alphabetically by code.
+func (s *Server) loadSets(ctx context.Context) ([]Bar, error) {
+    s.sets.mu.Lock()
+    defer s.sets.mu.Unlock()
+    if s.sets.sets != nil && time.Since(s.sets.at) < setsTTL {
+        return s.sets.sets, nil
+    }
+    counts, err := s.cli.AggregateGroupBy(ctx, "set")
+    if err != nil { return nil, err }
+    sets := make([]Bar, 0, len(counts))
+    for code, n := range counts {
+        if code != "" { sets = append(sets, Bar{Label: code, Count: n}) }
+    }
+    sort.Slice(sets, func(i, j int) bool { return sets[i].Label < sets[j].Label })
+    s.sets.sets, s.sets.at = sets, time.Now()
+    return sets, nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "sort"
+    "strconv"
+    "sync"
+    "time"
+
+    client "github.com/domano/decktech/pkg/weaviateclient"
+)
+
+// statsTTL bounds how often the (expensive) Aggregate queries behind /stats run.
+const statsTTL = time.Minute
+
+// topSets is how many sets the /stats page lists.
+const topSets = 10
+
+// Buckets always shown, so an empty collection renders zeros rather than empty tables.
+var (
+    rarityLabels = []string{"common", "uncommon", "rare", "mythic"}
+    colorLabels  = []string{"W", "U", "B", "R", "G"}
+)
+
+// Bar is one row of a CSS bar chart; Pct is relative to the largest bar in its chart.
+type Bar struct {
+    Label string
+    Count int
+    Pct   float64
+}
+
+// Stats is the collection overview rendered on /stats.
+type Stats struct {
+    Total  int
+    Rarity []Bar
+    Colors []Bar
+    CMC    []Bar
+    Sets   []Bar // top sets by card count
+}
+
+type statsCache struct {
+    mu    sync.Mutex
+    at    time.Time
+    stats *Stats
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+    defer cancel()
+    st, err := s.loadStats(ctx)
+    if err != nil {
+        s.render(w, r, "stats.html", Page{Title: "Stats", Error: err.Error()})
+        return
+    }
+    s.render(w, r, "stats.html", Page{Title: "Stats", Stats: st})
+}
+
+// loadStats returns cached stats when fresh, otherwise runs the groupBy aggregates.
+func (s *Server) loadStats(ctx context.Context) (*Stats, error) {
+    s.stats.mu.Lock()
+    defer s.stats.mu.Unlock()
+    if s.stats.stats != nil && time.Since(s.stats.at) < statsTTL {
+        return s.stats.stats, nil
+    }
+    total, err := s.cli.CountCards(ctx)
+    if err != nil { return nil, err }
+    rarity, err := s.cli.AggregateByField(ctx, "rarity")
+    if err != nil { return nil, err }
+    colors, err := s.cli.AggregateByField(ctx, "colors")
+    if err != nil { return nil, err }
+    cmc, err := s.cli.AggregateByField(ctx, "cmc")
+    if err != nil { return nil, err }
+    sets, err := s.cli.AggregateGroupBy(ctx, "set")
+    if err != nil { return nil, err }
+    st := &Stats{
+        Total:  total,
+        Rarity: toBars(rarity, rarityLabels...),
+        Colors: toBars(colors, colorLabels...),
+        CMC:    cmcHistogram(cmc),
+        Sets:   topBars(sets, topSets),
+    }
+    s.stats.stats, s.stats.at = st, time.Now()
+    return st, nil
+}
+
+// toBars keeps the aggregate order and appends any missing seed labels with a zero count.
+func toBars(groups []client.GroupCount, seed ...string) []Bar {
+    out := make([]Bar, 0, len(groups)+len(seed))
+    seen := map[string]bool{}
+    for _, g := range groups {
+        out = append(out, Bar{Label: g.Value, Count: g.Count})
+        seen[g.Value] = true
+    }
+    for _, l := range seed {
+        if !seen[l] { out = append(out, Bar{Label: l}) }
+    }
+    scaleBars(out)
+    return out
+}
+
+// topBars returns the n largest buckets of counts, largest first (ties by label).
+func topBars(counts map[string]int, n int) []Bar {
+    out := make([]Bar, 0, len(counts))
+    for l, c := range counts { out = append(out, Bar{Label: l, Count: c}) }
+    sort.Slice(out, func(i, j int) bool {
+        if out[i].Count == out[j].Count { return out[i].Label < out[j].Label }
+        return out[i].Count > out[j].Count
+    })
+    if len(out) > n { out = out[:n] }
+    scaleBars(out)
+    return out
+}
+
+// cmcHistogram folds per-value CMC buckets into 0..6 and 7+.
+func cmcHistogram(groups []client.GroupCount) []Bar {
+    out := make([]Bar, 8)
+    for i := range out { out[i].Label = strconv.Itoa(i) }
+    out[7].Label = "7+"
+    for _, g := range groups {
+        f, err := strconv.ParseFloat(g.Value, 64)
+        if err != nil { continue }
+        i := int(f)
+        if i > 7 { i = 7 }
+        if i < 0 { i = 0 }
+        out[i].Count += g.Count
+    }
+    scaleBars(out)
+    return out
+}
+
+func scaleBars(bars []Bar) {
+    top := 0
+    for _, b := range bars { if b.Count > top { top = b.Count } }
+    if top == 0 { return }
+    for i := range bars { bars[i].Pct = 100 * float64(bars[i].Count) / float64(top) }
+}
@@ -0,0 +1,62 @@
+package main
+
+import "strings"
+
+// typeOrder ranks card types for grouping: a card goes under the first of its types listed
+// here (so an Artifact Creature is a Creature), and groups are shown in this order.
+var typeOrder = []string{"Creature", "Planeswalker", "Battle", "Instant", "Sorcery", "Artifact", "Enchantment", "Land"}
+
+// CardGroup is one section of a grouped result list.
+type CardGroup struct {
+    Type  string
+    Cards []Card
+}
+
+// primaryType extracts the main card type from a type line: the front face's types before
+// the em-dash, ranked by typeOrder, e.g. "Legendary Artifact Creature — Golem" is "Creature".
+// Type lines without a known type give their last type word, or "Other" when empty.
+func primaryType(typeLine string) string {
+    front, _, _ := strings.Cut(typeLine, "//")
+    types, _, _ := strings.Cut(front, "—")
+    words := strings.Fields(types)
+    for _, t := range typeOrder {
+        for _, w := range words {
+            if strings.EqualFold(w, t) { return t }
+        }
+    }
+    if len(words) == 0 { return "Other" }
+    return words[len(words)-1]
+}
+
+// groupByType buckets cards by primaryType, keeping their order within each group.
+// Groups follow typeOrder, then any other types in order of first appearance.
+func groupByType(cards []Card) []CardGroup {
+    idx := map[string]int{}
+    var groups []CardGroup
+    for _, t := range typeOrder {
+        idx[t] = len(groups)
+        groups = append(groups, CardGroup{Type: t})
+    }
+    for _, c := range cards {
+        t := primaryType(c.TypeLine)
+        i, ok := idx[t]
+        if !ok {
+            i = len(groups)
+            idx[t] = i
+            groups = append(groups, CardGroup{Type: t})
+        }
+        groups[i].Cards = append(groups[i].Cards, c)
+    }
+    out := groups[:0]
+    for _, g := range groups {
+        if len(g.Cards) > 0 { out = append(out, g) }
+    }
+    return out
+}
+
+// Sections is what results.html renders: the type groups when grouping, otherwise all cards
+// as one untitled section.
+func (p Page) Sections() []CardGroup {
+    if p.Groups != nil { return p.Groups }
+    return []CardGroup{{Cards: p.Cards}}
+}
@@ -0,0 +1,175 @@
+// Package cardfilter filters and sorts card lists by the query parameters the web UI uses
+// (legendary, type, colors, cmc_min, cmc_max, sort, order).
+package cardfilter
+
+import (
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// Fields is the part of a card the filters and sorts read.
+type Fields struct {
+    Name       string
+    TypeLine   string
+    Colors     []string
+    CMC        float64
+    Similarity float64
+    PriceUSD   string // Scryfall price string; empty when unknown
+}
+
+// Card is any card type that can describe itself as Fields, e.g. weaviateclient.Card.
+type Card interface {
+    FilterFields() Fields
+}
+
+// Apply keeps the cards matching the filters in q (see Filter) and sorts them by q's sort and
+// order. Without a sort key, similar results sort by similarity and everything else by name;
+// without an order, the sort is descending.
+func Apply[C Card](cards []C, q map[string][]string, similar bool) []C {
+    out := Filter(cards, q)
+    sortKey := QueryValue(q, "sort")
+    order := QueryValue(q, "order")
+    if sortKey == "" {
+        if similar { sortKey = "similarity" } else { sortKey = "name" }
+    }
+    Sort(out, sortKey, order == "desc" || order == "")
+    return out
+}
+
+// Filter keeps the cards matching the filters in q, in their original order, for results that
+// already come back sorted (e.g. by Weaviate):
+//
+//   - legendary=1: the type line contains "Legendary"
+//   - type=Creature: the type line contains the value, case-insensitively
+//   - colors=W,U: the card has every listed color (AND, not OR); see ParseColors for the
+//     accepted spellings
+//   - cmc_min=2, cmc_max=4: inclusive bounds on the mana value, rounded down
+func Filter[C Card](cards []C, q map[string][]string) []C {
+    wantLegendary := QueryValue(q, "legendary") == "1"
+    typeFilter := strings.TrimSpace(QueryValue(q, "type"))
+    colors := ParseColors(QueryValue(q, "colors"))
+    cmcMin := atoiDefault(QueryValue(q, "cmc_min"), -1)
+    cmcMax := atoiDefault(QueryValue(q, "cmc_max"), -1)
+
+    out := make([]C, 0, len(cards))
+    for _, c := range cards {
+        f := c.FilterFields()
+        if wantLegendary && !strings.Contains(f.TypeLine, "Legendary") { continue }
+        if typeFilter != "" && !strings.Contains(strings.ToLower(f.TypeLine), strings.ToLower(typeFilter)) { continue }
+        if len(colors) > 0 && !ContainsAllColors(f.Colors, colors) { continue }
+        if cmcMin >= 0 && int(f.CMC) < cmcMin { continue }
+        if cmcMax >= 0 && int(f.CMC) > cmcMax { continue }
+        out = append(out, c)
+    }
+    return out
+}
+
+// QueryValue returns the first value for k in q, or "".
+func QueryValue(q map[string][]string, k string) string {
+    if v, ok := q[k]; ok && len(v) > 0 { return v[0] }
+    return ""
+}
+
+// colorOrder is WUBRG, the order ParseColors returns colors in.
+const colorOrder = "WUBRG"
+
+// colorWords maps color, guild, shard and wedge names to their colors.
+var colorWords = map[string]string{
+    "white": "W", "blue": "U", "black": "B", "red": "R", "green": "G",
+    "azorius": "WU", "dimir": "UB", "rakdos": "BR", "gruul": "RG", "selesnya": "GW",
+    "orzhov": "WB", "izzet": "UR", "golgari": "BG", "boros": "RW", "simic": "GU",
+    "bant": "GWU", "esper": "WUB", "grixis": "UBR", "jund": "BRG", "naya": "RGW",
+    "abzan": "WBG", "jeskai": "URW", "sultai": "BGU", "mardu": "RWB", "temur": "GUR",
+}
+
+// ParseColors turns user color input into color codes in WUBRG order, without duplicates.
+// The input is a comma, space, slash or plus separated list whose items are color names
+// ("blue"), guild, shard or wedge names ("azorius", "esper", "temur") or runs of color letters
+// ("U", "WU", "bug"), all case-insensitive. Items that are none of these are ignored.
+func ParseColors(s string) []string {
+    seen := map[rune]bool{}
+    tokens := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+        return r == ',' || r == '/' || r == '+' || r == ';' || r == ' ' || r == '\t'
+    })
+    for _, t := range tokens {
+        letters, ok := colorWords[t]
+        if !ok {
+            letters = strings.ToUpper(t)
+            if strings.Trim(letters, colorOrder) != "" { continue }
+        }
+        for _, r := range letters { seen[r] = true }
+    }
+    out := []string{}
+    for _, r := range colorOrder {
+        if seen[r] { out = append(out, string(r)) }
+    }
+    return out
+}
+
+// ContainsAllColors reports whether have includes every color in want, ignoring case,
+// surrounding space and empty entries.
+func ContainsAllColors(have []string, want []string) bool {
+    set := map[string]struct{}{}
+    for _, c := range have { set[strings.ToUpper(strings.TrimSpace(c))] = struct{}{} }
+    for _, c := range want {
+        c = strings.ToUpper(strings.TrimSpace(c))
+        if c == "" { continue }
+        if _, ok := set[c]; !ok { return false }
+    }
+    return true
+}
+
+// Sort orders cs in place by key: "cmc", "similarity" or "price" (ties broken by name), or
+// "name" (the default for unknown keys). The sort is stable, so cards that compare equal keep
+// their order in either direction. Cards without a USD price go last when sorting by price.
+func Sort[C Card](cs []C, key string, desc bool) {
+    fs := make([]Fields, len(cs))
+    for i, c := range cs { fs[i] = c.FilterFields() }
+    var less func(a, b Fields) bool
+    switch key {
+    case "cmc":
+        less = func(a, b Fields) bool { if a.CMC == b.CMC { return a.Name < b.Name }; return a.CMC < b.CMC }
+    case "similarity":
+        less = func(a, b Fields) bool { if a.Similarity == b.Similarity { return a.Name < b.Name }; return a.Similarity < b.Similarity }
+    case "price":
+        less = func(a, b Fields) bool {
+            pa, _ := PriceUSD(a)
+            pb, _ := PriceUSD(b)
+            if pa == pb { return a.Name < b.Name }
+            return pa < pb
+        }
+    default:
+        less = func(a, b Fields) bool { return a.Name < b.Name }
+    }
+    idx := make([]int, len(cs))
+    for i := range idx { idx[i] = i }
+    sort.SliceStable(idx, func(i, j int) bool {
+        a, b := fs[idx[i]], fs[idx[j]]
+        if key == "price" {
+            // cards without a USD price go last in either direction
+            _, pa := PriceUSD(a)
+            _, pb := PriceUSD(b)
+            if pa != pb { return pa }
+        }
+        if desc { return less(b, a) }
+        return less(a, b)
+    })
+    sorted := make([]C, len(cs))
+    for i, j := range idx { sorted[i] = cs[j] }
+    copy(cs, sorted)
+}
+
+// PriceUSD parses the USD price string; ok is false when it is missing or malformed.
+func PriceUSD(f Fields) (float64, bool) {
+    if f.PriceUSD == "" { return 0, false }
+    p, err := strconv.ParseFloat(f.PriceUSD, 64)
+    return p, err == nil
+}
+
+func atoiDefault(s string, def int) int {
+    if s == "" { return def }
+    i, err := strconv.Atoi(s)
+    if err != nil { return def }
+    return i
+}
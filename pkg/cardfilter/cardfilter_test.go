@@ -0,0 +1,183 @@
+package cardfilter
+
+import (
+    "net/url"
+    "reflect"
+    "testing"
+)
+
+// card is a minimal Card for tests; ID tells equal-sorting cards apart.
+type card struct {
+    ID string
+    Fields
+}
+
+func (c card) FilterFields() Fields { return c.Fields }
+
+func ids(cs []card) []string {
+    out := make([]string, len(cs))
+    for i, c := range cs { out[i] = c.ID }
+    return out
+}
+
+func TestContainsAllColors(t *testing.T) {
+    tests := []struct {
+        name       string
+        have, want []string
+        ok         bool
+    }{
+        {name: "exact", have: []string{"W", "U"}, want: []string{"W", "U"}, ok: true},
+        {name: "subset", have: []string{"W", "U", "B"}, want: []string{"U"}, ok: true},
+        {name: "all required, not any", have: []string{"W"}, want: []string{"W", "U"}, ok: false},
+        {name: "case and space", have: []string{" g "}, want: []string{"G"}, ok: true},
+        {name: "empty entries ignored", have: []string{"R"}, want: []string{"", "R", " "}, ok: true},
+        {name: "colorless card", have: nil, want: []string{"B"}, ok: false},
+        {name: "no colors wanted", have: nil, want: nil, ok: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := ContainsAllColors(tt.have, tt.want); got != tt.ok {
+                t.Fatalf("ContainsAllColors(%q, %q) = %v, want %v", tt.have, tt.want, got, tt.ok)
+            }
+        })
+    }
+}
+
+func TestParseColors(t *testing.T) {
+    tests := []struct {
+        in   string
+        want []string
+    }{
+        // raw letters
+        {in: "W,U", want: []string{"W", "U"}},
+        {in: "r, u", want: []string{"U", "R"}},
+        {in: "WU", want: []string{"W", "U"}},
+        {in: "gw ub", want: []string{"W", "U", "B", "G"}},
+        {in: "bug", want: []string{"U", "B", "G"}},
+        {in: "R,R,r", want: []string{"R"}},
+        // color names
+        {in: "blue,red", want: []string{"U", "R"}},
+        {in: "Green White", want: []string{"W", "G"}},
+        {in: "black/red", want: []string{"B", "R"}},
+        // guilds
+        {in: "azorius", want: []string{"W", "U"}},
+        {in: "Dimir", want: []string{"U", "B"}},
+        {in: "selesnya", want: []string{"W", "G"}},
+        {in: "boros", want: []string{"W", "R"}},
+        {in: "izzet+golgari", want: []string{"U", "B", "R", "G"}},
+        // shards and wedges
+        {in: "esper", want: []string{"W", "U", "B"}},
+        {in: "naya", want: []string{"W", "R", "G"}},
+        {in: "grixis", want: []string{"U", "B", "R"}},
+        {in: "abzan", want: []string{"W", "B", "G"}},
+        {in: "TEMUR", want: []string{"U", "R", "G"}},
+        {in: "mardu", want: []string{"W", "B", "R"}},
+        // mixed and invalid
+        {in: "simic, red", want: []string{"U", "R", "G"}},
+        {in: "purple, x, U", want: []string{"U"}},
+        {in: "wux", want: []string{}},
+        {in: "", want: []string{}},
+        {in: " , ", want: []string{}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.in, func(t *testing.T) {
+            if got := ParseColors(tt.in); !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("ParseColors(%q) = %q, want %q", tt.in, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestApplyFilters(t *testing.T) {
+    cards := []card{
+        {ID: "bolt", Fields: Fields{Name: "Lightning Bolt", TypeLine: "Instant", Colors: []string{"R"}, CMC: 1}},
+        {ID: "helix", Fields: Fields{Name: "Lightning Helix", TypeLine: "Instant", Colors: []string{"R", "W"}, CMC: 2}},
+        {ID: "sol", Fields: Fields{Name: "Sol Ring", TypeLine: "Artifact", CMC: 1}},
+        {ID: "niv", Fields: Fields{Name: "Niv-Mizzet, Parun", TypeLine: "Legendary Creature — Dragon Wizard", Colors: []string{"U", "R"}, CMC: 6}},
+        {ID: "x", Fields: Fields{Name: "Fireball", TypeLine: "Sorcery", Colors: []string{"R"}, CMC: 1.5}}, // fractional MV rounds down
+        {ID: "land", Fields: Fields{Name: "Mountain", TypeLine: "Basic Land — Mountain", CMC: 0}},
+    }
+    tests := []struct {
+        name  string
+        query string
+        want  []string
+    }{
+        {name: "no filters sorts by name descending", query: "", want: []string{"sol", "niv", "land", "helix", "bolt", "x"}},
+        {name: "colors are ANDed", query: "colors=R,W&order=asc", want: []string{"helix"}},
+        {name: "colors with spaces and case", query: "colors=r, u&order=asc", want: []string{"niv"}},
+        {name: "guild name", query: "colors=boros", want: []string{"helix"}},
+        {name: "color names", query: "colors=blue red", want: []string{"niv"}},
+        {name: "unknown colors ignored", query: "colors=purple&type=Artifact", want: []string{"sol"}},
+        {name: "single color", query: "colors=R&order=asc", want: []string{"x", "bolt", "helix", "niv"}},
+        {name: "cmc_min is inclusive", query: "cmc_min=2&order=asc", want: []string{"helix", "niv"}},
+        {name: "cmc_max is inclusive", query: "cmc_max=1&order=asc", want: []string{"x", "bolt", "land", "sol"}},
+        {name: "cmc range of one value", query: "cmc_min=1&cmc_max=1&order=asc", want: []string{"x", "bolt", "sol"}},
+        {name: "cmc zero bounds", query: "cmc_max=0", want: []string{"land"}},
+        {name: "empty cmc range", query: "cmc_min=3&cmc_max=2", want: []string{}},
+        {name: "invalid bound ignored", query: "cmc_min=abc&colors=W&order=asc", want: []string{"helix"}},
+        {name: "legendary", query: "legendary=1", want: []string{"niv"}},
+        {name: "type is case-insensitive substring", query: "type=instant&order=asc", want: []string{"bolt", "helix"}},
+        {name: "cmc sort ascending ties by name", query: "sort=cmc&order=asc", want: []string{"land", "bolt", "sol", "x", "helix", "niv"}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            q, err := url.ParseQuery(tt.query)
+            if err != nil { t.Fatal(err) }
+            if got := ids(Apply(cards, q, false)); !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("Apply(%q) = %v, want %v", tt.query, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestApplyDefaultSortForSimilar(t *testing.T) {
+    cards := []card{
+        {ID: "a", Fields: Fields{Name: "A", Similarity: 0.5}},
+        {ID: "b", Fields: Fields{Name: "B", Similarity: 0.9}},
+        {ID: "c", Fields: Fields{Name: "C", Similarity: 0.7}},
+    }
+    if got := ids(Apply(cards, nil, true)); !reflect.DeepEqual(got, []string{"b", "c", "a"}) {
+        t.Fatalf("similar results = %v, want most similar first", got)
+    }
+}
+
+func TestSortStable(t *testing.T) {
+    // Same name and similarity: only the input order tells them apart.
+    twins := []card{
+        {ID: "1", Fields: Fields{Name: "Opt", Similarity: 0.8, CMC: 1}},
+        {ID: "2", Fields: Fields{Name: "Opt", Similarity: 0.8, CMC: 1}},
+        {ID: "3", Fields: Fields{Name: "Opt", Similarity: 0.8, CMC: 1}},
+    }
+    for _, key := range []string{"name", "cmc", "similarity", "price", "unknown"} {
+        for _, desc := range []bool{false, true} {
+            cs := append([]card(nil), twins...)
+            Sort(cs, key, desc)
+            if got := ids(cs); !reflect.DeepEqual(got, []string{"1", "2", "3"}) {
+                t.Errorf("Sort(%q, desc=%v) reordered equal cards: %v", key, desc, got)
+            }
+        }
+    }
+}
+
+func TestSortPriceUnpricedLast(t *testing.T) {
+    cards := []card{
+        {ID: "none-b", Fields: Fields{Name: "B"}},
+        {ID: "cheap", Fields: Fields{Name: "C", PriceUSD: "0.25"}},
+        {ID: "bad", Fields: Fields{Name: "A", PriceUSD: "n/a"}},
+        {ID: "dear", Fields: Fields{Name: "D", PriceUSD: "12.00"}},
+    }
+    tests := []struct {
+        desc bool
+        want []string
+    }{
+        {desc: false, want: []string{"cheap", "dear", "bad", "none-b"}},
+        {desc: true, want: []string{"dear", "cheap", "none-b", "bad"}},
+    }
+    for _, tt := range tests {
+        cs := append([]card(nil), cards...)
+        Sort(cs, "price", tt.desc)
+        if got := ids(cs); !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("Sort(price, desc=%v) = %v, want %v", tt.desc, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,78 @@
+// Package decklist parses plain-text decklists ("4 Lightning Bolt", "1x Sol Ring (CMM) 410").
+package decklist
+
+import (
+    "bufio"
+    "io"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+// Entry is one decklist line.
+type Entry struct {
+    Count     int    `json:"count"`
+    Name      string `json:"name"`
+    Set       string `json:"set,omitempty"`
+    Collector string `json:"collector_number,omitempty"`
+}
+
+// sectionHeaders are lines that group a list (Arena/MTGO exports) rather than name a card.
+var sectionHeaders = map[string]bool{
+    "deck": true, "main": true, "mainboard": true, "maindeck": true, "sideboard": true,
+    "commander": true, "companion": true, "maybeboard": true, "about": true,
+}
+
+var (
+    reCount = regexp.MustCompile(`^(\d+)\s*[xX]?\s+(.+)$`)
+    rePrint = regexp.MustCompile(`^(.+?)\s+\(([A-Za-z0-9]+)\)(?:\s+(\S+))?$`)
+)
+
+// Parse reads a decklist. Blank lines, comments (# or //), section headers, and
+// "SB:" prefixes are handled; a line without a count means one copy.
+func Parse(r io.Reader) ([]Entry, error) {
+    var out []Entry
+    sc := bufio.NewScanner(r)
+    for sc.Scan() {
+        if e, ok := ParseLine(sc.Text()); ok { out = append(out, e) }
+    }
+    return out, sc.Err()
+}
+
+// ParseString is Parse for an in-memory decklist.
+func ParseString(s string) []Entry {
+    out, _ := Parse(strings.NewReader(s))
+    return out
+}
+
+// ParseLine parses a single decklist line, reporting false for non-card lines.
+func ParseLine(line string) (Entry, bool) {
+    line = strings.TrimSpace(line)
+    if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") { return Entry{}, false }
+    line = strings.TrimSpace(strings.TrimPrefix(line, "SB:"))
+    if sectionHeaders[strings.ToLower(strings.TrimSuffix(line, ":"))] { return Entry{}, false }
+    e := Entry{Count: 1, Name: line}
+    if m := reCount.FindStringSubmatch(line); m != nil {
+        n, err := strconv.Atoi(m[1])
+        if err == nil && n > 0 { e.Count, e.Name = n, m[2] }
+    }
+    if m := rePrint.FindStringSubmatch(e.Name); m != nil {
+        e.Name, e.Set, e.Collector = m[1], strings.ToLower(m[2]), m[3]
+    }
+    e.Name = strings.TrimSpace(e.Name)
+    if e.Name == "" { return Entry{}, false }
+    return e, true
+}
+
+// Names returns the distinct card names in list order.
+func Names(entries []Entry) []string {
+    seen := map[string]bool{}
+    var out []string
+    for _, e := range entries {
+        k := strings.ToLower(e.Name)
+        if seen[k] { continue }
+        seen[k] = true
+        out = append(out, e.Name)
+    }
+    return out
+}
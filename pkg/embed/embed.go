@@ -0,0 +1,132 @@
+package embed
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "math"
+    "net/http"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/domano/decktech/pkg/requestid"
+)
+
+// Embedder turns texts into vectors, one per input and in input order.
+type Embedder interface {
+    Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// PartialError reports that only the first Done texts were embedded before Err.
+// The vectors returned alongside it cover exactly those texts.
+type PartialError struct {
+    Done int
+    Err  error
+}
+
+func (e *PartialError) Error() string { return fmt.Sprintf("embedded %d texts before failure: %v", e.Done, e.Err) }
+func (e *PartialError) Unwrap() error { return e.Err }
+
+// HTTPEmbedder posts texts to an embedding server. URLs ending in /embed are treated as a
+// text-embeddings-inference endpoint ({"inputs": [...]} -> [[...]]); anything else is sent an
+// OpenAI-style body ({"model", "input"} -> {"data": [{"index", "embedding"}]}).
+type HTTPEmbedder struct {
+    URL       string
+    Model     string
+    ChunkSize int // texts per request; 0 sends everything at once
+    Retries   int // extra attempts per chunk before giving up
+    http      *http.Client
+}
+
+// NewHTTPEmbedder returns an embedder for url using model, sending 64 texts per request with one retry.
+func NewHTTPEmbedder(url, model string) *HTTPEmbedder {
+    return &HTTPEmbedder{
+        URL:       url,
+        Model:     model,
+        ChunkSize: 64,
+        Retries:   1,
+        http:      &http.Client{Timeout: 2 * time.Minute, Transport: requestid.Transport(http.DefaultTransport)},
+    }
+}
+
+// Embed embeds texts chunk by chunk. If a chunk still fails after retries, the vectors for the
+// chunks that succeeded are returned together with a *PartialError so callers can keep them.
+func (e *HTTPEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+    size := e.ChunkSize
+    if size <= 0 { size = len(texts) }
+    out := make([][]float64, 0, len(texts))
+    for start := 0; start < len(texts); start += size {
+        end := min(start+size, len(texts))
+        var vecs [][]float64
+        var err error
+        for attempt := 0; attempt <= e.Retries; attempt++ {
+            if attempt > 0 {
+                select {
+                case <-ctx.Done(): err = ctx.Err()
+                case <-time.After(time.Duration(attempt) * time.Second):
+                }
+                if ctx.Err() != nil { break }
+            }
+            if vecs, err = e.post(ctx, texts[start:end]); err == nil { break }
+        }
+        if err != nil {
+            if len(out) == 0 { return nil, err }
+            return out, &PartialError{Done: len(out), Err: err}
+        }
+        out = append(out, vecs...)
+    }
+    return out, nil
+}
+
+// post sends one request and checks that a vector came back for every text.
+func (e *HTTPEmbedder) post(ctx context.Context, texts []string) ([][]float64, error) {
+    tei := strings.HasSuffix(strings.TrimRight(e.URL, "/"), "/embed")
+    var body any = map[string]any{"model": e.Model, "input": texts}
+    if tei { body = map[string]any{"inputs": texts} }
+    b, _ := json.Marshal(body)
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(b))
+    if err != nil { return nil, err }
+    req.Header.Set("Content-Type", "application/json")
+    resp, err := e.http.Do(req)
+    if err != nil { return nil, err }
+    defer resp.Body.Close()
+    raw, err := io.ReadAll(resp.Body)
+    if err != nil { return nil, err }
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("embed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(raw[:min(len(raw), 500)])))
+    }
+    vecs, err := decodeVectors(raw)
+    if err != nil { return nil, fmt.Errorf("embed: %w", err) }
+    if len(vecs) != len(texts) { return nil, fmt.Errorf("embed: got %d vectors for %d texts", len(vecs), len(texts)) }
+    return vecs, nil
+}
+
+// decodeVectors accepts either a bare array of vectors or an OpenAI-style data list.
+func decodeVectors(raw []byte) ([][]float64, error) {
+    var bare [][]float64
+    if err := json.Unmarshal(raw, &bare); err == nil { return bare, nil }
+    var oa struct {
+        Data []struct {
+            Index     int       `json:"index"`
+            Embedding []float64 `json:"embedding"`
+        } `json:"data"`
+    }
+    if err := json.Unmarshal(raw, &oa); err != nil { return nil, err }
+    sort.SliceStable(oa.Data, func(i, j int) bool { return oa.Data[i].Index < oa.Data[j].Index })
+    out := make([][]float64, len(oa.Data))
+    for i, d := range oa.Data { out[i] = d.Embedding }
+    return out, nil
+}
+
+// Normalize scales v to unit length in place (cosine distance expects L2-normalized vectors).
+func Normalize(v []float64) []float64 {
+    var s float64
+    for _, x := range v { s += x * x }
+    if s <= 0 { return v }
+    n := math.Sqrt(s)
+    for i := range v { v[i] /= n }
+    return v
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+    "context"
+    "log/slog"
+    "net/http"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/domano/decktech/pkg/requestid"
+)
+
+// Setup installs the default slog logger configured from the environment:
+//   LOG_LEVEL  debug|info|warn|error (default info)
+//   LOG_FORMAT text|json (default text)
+func Setup() *slog.Logger {
+    opts := &slog.HandlerOptions{Level: ParseLevel(os.Getenv("LOG_LEVEL"))}
+    var h slog.Handler
+    if strings.EqualFold(os.Getenv("LOG_FORMAT"), "json") {
+        h = slog.NewJSONHandler(os.Stderr, opts)
+    } else {
+        h = slog.NewTextHandler(os.Stderr, opts)
+    }
+    l := slog.New(contextHandler{h})
+    slog.SetDefault(l)
+    return l
+}
+
+// ParseLevel maps a LOG_LEVEL string to a slog level, defaulting to info.
+func ParseLevel(s string) slog.Level {
+    switch strings.ToLower(strings.TrimSpace(s)) {
+    case "debug":
+        return slog.LevelDebug
+    case "warn", "warning":
+        return slog.LevelWarn
+    case "error":
+        return slog.LevelError
+    }
+    return slog.LevelInfo
+}
+
+// contextHandler adds the request ID from the context to every record logged via the *Context funcs.
+type contextHandler struct{ slog.Handler }
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+    if id := requestid.FromContext(ctx); id != "" {
+        r.AddAttrs(slog.String("request_id", id))
+    }
+    return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler { return contextHandler{h.Handler.WithAttrs(attrs)} }
+func (h contextHandler) WithGroup(name string) slog.Handler       { return contextHandler{h.Handler.WithGroup(name)} }
+
+// Requests logs method, path, status, and duration for every request.
+// Wrap it with requestid.Middleware so each line carries the request ID.
+func Requests(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        start := time.Now()
+        rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+        next.ServeHTTP(rec, r)
+        level := slog.LevelInfo
+        if rec.status >= 500 { level = slog.LevelError } else if rec.status >= 400 { level = slog.LevelWarn }
+        slog.Log(r.Context(), level, "request",
+            "method", r.Method, "path", r.URL.Path, "status", rec.status, "duration", time.Since(start))
+    })
+}
+
+// statusRecorder captures the response status for logging.
+type statusRecorder struct {
+    http.ResponseWriter
+    status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+    s.status = code
+    s.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (e.g. for Flush).
+func (s *statusRecorder) Unwrap() http.ResponseWriter { return s.ResponseWriter }
@@ -2,7 +2,9 @@ package progress
 
 import (
     "encoding/json"
+    "fmt"
     "os"
+    "path/filepath"
 )
 
 // Checkpoint represents embedding progress persisted to disk by the embedder.
@@ -12,6 +14,37 @@ type Checkpoint struct {
     Total        int    `json:"total"`
     LastBatchOut string `json:"last_batch_out"`
     Model        string `json:"model,omitempty"`
+    IncludeName  bool   `json:"include_name,omitempty"`
+    Kind         string `json:"kind,omitempty"`
+    // BatchCounts maps each verified batch's start offset to the objects it should have put in
+    // Weaviate; Mismatches lists the batches where verification found fewer.
+    BatchCounts map[int]int     `json:"batch_counts,omitempty"`
+    Mismatches  []BatchMismatch `json:"batch_mismatches,omitempty"`
+}
+
+// BatchMismatch is a batch whose objects were not all found in Weaviate after ingest.
+type BatchMismatch struct {
+    Offset   int `json:"offset"`
+    Expected int `json:"expected"`
+    Found    int `json:"found"`
+}
+
+func (b BatchMismatch) String() string {
+    return fmt.Sprintf("batch offset %d: expected %d, found %d", b.Offset, b.Expected, b.Found)
+}
+
+// RecordBatch stores the verification result for the batch at offset, replacing any earlier
+// result for it, so a batch that verifies on a later run drops out of Mismatches.
+func (cp *Checkpoint) RecordBatch(offset, expected, found int) {
+    if cp.BatchCounts == nil { cp.BatchCounts = map[int]int{} }
+    cp.BatchCounts[offset] = expected
+    kept := cp.Mismatches[:0]
+    for _, m := range cp.Mismatches {
+        if m.Offset != offset { kept = append(kept, m) }
+    }
+    cp.Mismatches = kept
+    if found != expected { cp.Mismatches = append(cp.Mismatches, BatchMismatch{Offset: offset, Expected: expected, Found: found}) }
+    if len(cp.Mismatches) == 0 { cp.Mismatches = nil }
 }
 
 // ReadCheckpoint loads the checkpoint JSON file if present.
@@ -27,3 +60,13 @@ func ReadCheckpoint(path string) (Checkpoint, error) {
     return cp, err
 }
 
+
+// WriteCheckpoint atomically replaces the checkpoint file at path.
+func WriteCheckpoint(path string, cp Checkpoint) error {
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { return err }
+    tmp := path + ".tmp"
+    b, err := json.Marshal(cp)
+    if err != nil { return err }
+    if err := os.WriteFile(tmp, b, 0o644); err != nil { return err }
+    return os.Rename(tmp, path)
+}
@@ -0,0 +1,71 @@
+package requestid
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "net/http"
+)
+
+// Header is the HTTP header used to carry request IDs in and out of the services.
+const Header = "X-Request-ID"
+
+// maxLen bounds accepted incoming IDs so clients can't bloat every log line.
+const maxLen = 128
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+    return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" when none is set.
+func FromContext(ctx context.Context) string {
+    if ctx == nil { return "" }
+    id, _ := ctx.Value(ctxKey{}).(string)
+    return id
+}
+
+// New generates a random 16-byte hex request ID.
+func New() string {
+    var b [16]byte
+    _, _ = rand.Read(b[:])
+    return hex.EncodeToString(b[:])
+}
+
+// Middleware reads X-Request-ID from the incoming request (or generates one),
+// stores it in the request context, and echoes it on the response.
+func Middleware(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        id := r.Header.Get(Header)
+        if !valid(id) { id = New() }
+        w.Header().Set(Header, id)
+        next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+    })
+}
+
+func valid(id string) bool {
+    if id == "" || len(id) > maxLen { return false }
+    for i := 0; i < len(id); i++ {
+        if id[i] < 0x21 || id[i] > 0x7e { return false }
+    }
+    return true
+}
+
+// Transport wraps base (http.DefaultTransport when nil) so outgoing requests carry the
+// request ID from their context, propagating it to downstream services.
+func Transport(base http.RoundTripper) http.RoundTripper {
+    if base == nil { base = http.DefaultTransport }
+    return roundTripper{base}
+}
+
+type roundTripper struct{ base http.RoundTripper }
+
+func (t roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+    id := FromContext(r.Context())
+    if id == "" || r.Header.Get(Header) != "" { return t.base.RoundTrip(r) }
+    r = r.Clone(r.Context()) // RoundTrippers must not modify the caller's request
+    r.Header.Set(Header, id)
+    return t.base.RoundTrip(r)
+}
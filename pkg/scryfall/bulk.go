@@ -0,0 +1,132 @@
+// Package scryfall downloads Scryfall bulk data files.
+package scryfall
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+)
+
+// IndexURL is Scryfall's bulk-data listing; overridable for tests.
+var IndexURL = "https://api.scryfall.com/bulk-data"
+
+// userAgent identifies the client, as Scryfall's API guidelines ask.
+const userAgent = "decktech/1.0"
+
+// BulkItem is one entry of the /bulk-data listing.
+type BulkItem struct {
+    Type        string `json:"type"`
+    DownloadURI string `json:"download_uri"`
+    Size        int64  `json:"size"`
+    UpdatedAt   string `json:"updated_at"`
+}
+
+// FindBulk looks up the bulk item of the given kind ("oracle_cards", "default_cards", ...).
+func FindBulk(ctx context.Context, kind string) (BulkItem, error) {
+    req, err := newRequest(ctx, IndexURL)
+    if err != nil { return BulkItem{}, err }
+    req.Header.Set("Accept", "application/json")
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil { return BulkItem{}, err }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return BulkItem{}, fmt.Errorf("bulk-data index: status %d", resp.StatusCode)
+    }
+    var idx struct { Data []BulkItem `json:"data"` }
+    if err := json.NewDecoder(resp.Body).Decode(&idx); err != nil { return BulkItem{}, fmt.Errorf("bulk-data index: %w", err) }
+    for _, it := range idx.Data {
+        if it.Type == kind && it.DownloadURI != "" { return it, nil }
+    }
+    return BulkItem{}, fmt.Errorf("no bulk download for kind %q", kind)
+}
+
+// DownloadBulk resolves the download URI for kind via the bulk-data API and streams it to outPath.
+// Data is written to outPath+".part" and renamed on success. An interrupted download of the same
+// URI is resumed with an HTTP Range request when the server honors it (206); otherwise it restarts.
+// progress, if non-nil, is called after each chunk with bytes done and the total (0 if unknown).
+func DownloadBulk(ctx context.Context, kind, outPath string, progress func(done, total int64)) error {
+    item, err := FindBulk(ctx, kind)
+    if err != nil { return err }
+    if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil { return err }
+
+    part, src := outPath+".part", outPath+".part.src"
+    var offset int64
+    if prev, err := os.ReadFile(src); err == nil && string(prev) == item.DownloadURI {
+        if fi, err := os.Stat(part); err == nil { offset = fi.Size() }
+    }
+    if err := os.WriteFile(src, []byte(item.DownloadURI), 0o644); err != nil { return err }
+
+    req, err := newRequest(ctx, item.DownloadURI)
+    if err != nil { return err }
+    if offset > 0 { req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset)) }
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil { return err }
+    defer resp.Body.Close()
+
+    flags := os.O_CREATE | os.O_WRONLY
+    switch resp.StatusCode {
+    case http.StatusPartialContent:
+        flags |= os.O_APPEND
+    case http.StatusOK:
+        offset, flags = 0, flags|os.O_TRUNC // no range support (or fresh start)
+    case http.StatusRequestedRangeNotSatisfiable:
+        _ = os.Remove(part) // stale partial; the next attempt starts over
+        return fmt.Errorf("download %s: partial file is out of range, removed; retry", kind)
+    default:
+        return fmt.Errorf("download %s: status %d", kind, resp.StatusCode)
+    }
+    total := totalSize(resp, offset)
+    if total == 0 { total = item.Size }
+
+    f, err := os.OpenFile(part, flags, 0o644)
+    if err != nil { return err }
+    w := &progressWriter{w: f, done: offset, total: total, fn: progress}
+    if _, err := io.Copy(w, resp.Body); err != nil {
+        _ = f.Close()
+        return fmt.Errorf("download %s: %w (partial kept for resume)", kind, err)
+    }
+    if err := f.Close(); err != nil { return err }
+    if total > 0 && w.done != total {
+        return fmt.Errorf("download %s: got %d of %d bytes (partial kept for resume)", kind, w.done, total)
+    }
+    _ = os.Remove(src)
+    return os.Rename(part, outPath)
+}
+
+func newRequest(ctx context.Context, url string) (*http.Request, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+    if err != nil { return nil, err }
+    req.Header.Set("User-Agent", userAgent)
+    return req, nil
+}
+
+// totalSize derives the full file size from Content-Range ("bytes 100-199/200") on a 206,
+// or Content-Length plus the resume offset otherwise. 0 means unknown.
+func totalSize(resp *http.Response, offset int64) int64 {
+    if cr := resp.Header.Get("Content-Range"); cr != "" {
+        if i := strings.LastIndexByte(cr, '/'); i >= 0 {
+            if n, err := strconv.ParseInt(cr[i+1:], 10, 64); err == nil { return n }
+        }
+    }
+    if resp.ContentLength > 0 { return offset + resp.ContentLength }
+    return 0
+}
+
+type progressWriter struct {
+    w           io.Writer
+    done, total int64
+    fn          func(done, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+    n, err := p.w.Write(b)
+    p.done += int64(n)
+    if p.fn != nil { p.fn(p.done, p.total) }
+    return n, err
+}
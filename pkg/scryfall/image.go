@@ -0,0 +1,30 @@
+package scryfall
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+)
+
+// MaxImageBytes bounds one image download; Scryfall's largest JPEGs are well under 1 MB.
+const MaxImageBytes = 4 << 20
+
+// FetchImage downloads a card image URI (one of a card's image_uris) and returns its bytes and
+// content type. Anything but a 200 with an image content type is an error.
+func FetchImage(ctx context.Context, uri string) ([]byte, string, error) {
+    req, err := newRequest(ctx, uri)
+    if err != nil { return nil, "", err }
+    req.Header.Set("Accept", "image/*")
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil { return nil, "", err }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK { return nil, "", fmt.Errorf("image %s: status %d", uri, resp.StatusCode) }
+    ct := resp.Header.Get("Content-Type")
+    if !strings.HasPrefix(ct, "image/") { return nil, "", fmt.Errorf("image %s: content type %q", uri, ct) }
+    data, err := io.ReadAll(io.LimitReader(resp.Body, MaxImageBytes+1))
+    if err != nil { return nil, "", fmt.Errorf("image %s: %w", uri, err) }
+    if len(data) > MaxImageBytes { return nil, "", fmt.Errorf("image %s: larger than %d bytes", uri, MaxImageBytes) }
+    return data, ct, nil
+}
@@ -0,0 +1,38 @@
+package scryfall
+
+import (
+    "bufio"
+    "compress/gzip"
+    "fmt"
+    "io"
+    "os"
+)
+
+// OpenBulk opens a bulk data file for reading, transparently decompressing it when it is
+// gzipped. Compression is detected from the gzip magic bytes rather than the name, so a
+// .json.gz file works, and so does a plain file that happens to end in .gz.
+func OpenBulk(path string) (io.ReadCloser, error) {
+    f, err := os.Open(path)
+    if err != nil { return nil, err }
+    br := bufio.NewReaderSize(f, 1<<16)
+    if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+        zr, err := gzip.NewReader(br)
+        if err != nil { f.Close(); return nil, fmt.Errorf("%s: %w", path, err) }
+        return &bulkReader{Reader: zr, closers: []io.Closer{zr, f}}, nil
+    }
+    return &bulkReader{Reader: br, closers: []io.Closer{f}}, nil
+}
+
+// bulkReader closes the gzip stream (if any) and then the file.
+type bulkReader struct {
+    io.Reader
+    closers []io.Closer
+}
+
+func (r *bulkReader) Close() error {
+    var first error
+    for _, c := range r.closers {
+        if err := c.Close(); err != nil && first == nil { first = err }
+    }
+    return first
+}
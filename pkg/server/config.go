@@ -0,0 +1,106 @@
+package server
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// Config is the optional JSON file (-config) shared by similarityd and the web server, so a
+// tuned instance can be reproduced from one file. Each field stands for the env var named in
+// its comment; a set env var wins over the file, and fields a server has no use for are
+// ignored. Omitted fields keep the server's defaults.
+type Config struct {
+    WeaviateURL   string            `json:"weaviate_url,omitempty"`      // WEAVIATE_URL
+    Addr          string            `json:"addr,omitempty"`              // ADDR, e.g. ":8088"
+    Timeouts      map[string]string `json:"weaviate_timeouts,omitempty"` // WEAVIATE_TIMEOUT_<OP>, e.g. {"search": "30s"}
+    WeaviateRPS   float64           `json:"weaviate_rps,omitempty"`      // WEAVIATE_RPS
+    WeaviateBurst int               `json:"weaviate_burst,omitempty"`    // WEAVIATE_BURST
+    CORSOrigins   []string          `json:"cors_origins,omitempty"`      // CORS_ORIGINS, comma-separated
+
+    // similarityd
+    DefaultK        int    `json:"default_k,omitempty"`        // DEFAULT_K
+    MaxK            int    `json:"max_k,omitempty"`            // MAX_K
+    DefaultEf       int    `json:"default_ef,omitempty"`       // DEFAULT_EF
+    MaxEf           int    `json:"max_ef,omitempty"`           // MAX_EF
+    OverfetchFactor int    `json:"overfetch_factor,omitempty"` // OVERFETCH_FACTOR
+    Metric          string `json:"metric,omitempty"`           // METRIC
+    EmbedURL        string `json:"embed_url,omitempty"`        // EMBED_URL
+    EmbedModel      string `json:"embed_model,omitempty"`      // EMBED_MODEL
+
+    // web
+    CacheTTL    string  `json:"cache_ttl,omitempty"`     // WEB_CACHE_TTL
+    CacheSize   int     `json:"cache_size,omitempty"`    // WEB_CACHE_SIZE
+    ImgCacheDir string  `json:"img_cache_dir,omitempty"` // WEB_IMG_CACHE_DIR
+    ImgCacheMB  *int    `json:"img_cache_mb,omitempty"`  // WEB_IMG_CACHE_MB; 0 disables the image proxy
+    ImgTTL      string  `json:"img_ttl,omitempty"`       // WEB_IMG_TTL
+    ScryfallRPS float64 `json:"scryfall_rps,omitempty"`  // SCRYFALL_RPS
+}
+
+// LoadConfig reads a config file. Unknown fields are an error, so a misspelt setting isn't
+// silently ignored.
+func LoadConfig(path string) (Config, error) {
+    var c Config
+    f, err := os.Open(path)
+    if err != nil { return c, err }
+    defer f.Close()
+    dec := json.NewDecoder(f)
+    dec.DisallowUnknownFields()
+    if err := dec.Decode(&c); err != nil { return c, fmt.Errorf("%s: %w", path, err) }
+    return c, nil
+}
+
+// Env lists the file's settings as env vars, leaving out unset fields.
+func (c Config) Env() map[string]string {
+    env := map[string]string{}
+    set := func(key, v string) { if v != "" { env[key] = v } }
+    num := func(key string, n int) { if n != 0 { env[key] = strconv.Itoa(n) } }
+    float := func(key string, f float64) { if f != 0 { env[key] = strconv.FormatFloat(f, 'g', -1, 64) } }
+    set("WEAVIATE_URL", c.WeaviateURL)
+    set("ADDR", c.Addr)
+    for op, d := range c.Timeouts { set("WEAVIATE_TIMEOUT_"+strings.ToUpper(op), d) }
+    float("WEAVIATE_RPS", c.WeaviateRPS)
+    num("WEAVIATE_BURST", c.WeaviateBurst)
+    set("CORS_ORIGINS", strings.Join(c.CORSOrigins, ","))
+    num("DEFAULT_K", c.DefaultK)
+    num("MAX_K", c.MaxK)
+    num("DEFAULT_EF", c.DefaultEf)
+    num("MAX_EF", c.MaxEf)
+    num("OVERFETCH_FACTOR", c.OverfetchFactor)
+    set("METRIC", c.Metric)
+    set("EMBED_URL", c.EmbedURL)
+    set("EMBED_MODEL", c.EmbedModel)
+    set("WEB_CACHE_TTL", c.CacheTTL)
+    num("WEB_CACHE_SIZE", c.CacheSize)
+    set("WEB_IMG_CACHE_DIR", c.ImgCacheDir)
+    if c.ImgCacheMB != nil { env["WEB_IMG_CACHE_MB"] = strconv.Itoa(*c.ImgCacheMB) }
+    set("WEB_IMG_TTL", c.ImgTTL)
+    float("SCRYFALL_RPS", c.ScryfallRPS)
+    return env
+}
+
+// ApplyConfig loads the config file at path, if any, and sets each of its settings whose env
+// var is unset, so the servers' env readers see file values unless the environment overrides
+// them. It returns the keys taken from the file.
+func ApplyConfig(path string) ([]string, error) {
+    if path == "" { return nil, nil }
+    c, err := LoadConfig(path)
+    if err != nil { return nil, err }
+    var applied []string
+    for key, v := range c.Env() {
+        if _, ok := os.LookupEnv(key); ok { continue }
+        if err := os.Setenv(key, v); err != nil { return applied, err }
+        applied = append(applied, key)
+    }
+    sort.Strings(applied)
+    return applied, nil
+}
+
+// ListenAddr is ADDR, or def when it is unset.
+func ListenAddr(def string) string {
+    if a := strings.TrimSpace(os.Getenv("ADDR")); a != "" { return a }
+    return def
+}
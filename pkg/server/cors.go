@@ -0,0 +1,45 @@
+package server
+
+import (
+    "net/http"
+    "os"
+    "strings"
+)
+
+// CORSOriginsFromEnv reads CORS_ORIGINS, a comma-separated list of origins such as
+// https://cube.example.org, or * for any.
+func CORSOriginsFromEnv() []string {
+    var out []string
+    for _, o := range strings.Split(os.Getenv("CORS_ORIGINS"), ",") {
+        if o = strings.TrimRight(strings.TrimSpace(o), "/"); o != "" { out = append(out, o) }
+    }
+    return out
+}
+
+// CORS lets pages on the listed origins call the server from the browser: their requests get
+// Access-Control-Allow-Origin, and their preflight OPTIONS requests are answered here. Other
+// origins get no CORS headers, so browsers keep blocking them. No origins returns next as is.
+func CORS(origins []string, next http.Handler) http.Handler {
+    if len(origins) == 0 { return next }
+    allowed := map[string]bool{}
+    for _, o := range origins { allowed[o] = true }
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        origin := r.Header.Get("Origin")
+        if origin == "" || !(allowed["*"] || allowed[origin]) {
+            next.ServeHTTP(w, r)
+            return
+        }
+        h := w.Header()
+        h.Add("Vary", "Origin")
+        if allowed["*"] { h.Set("Access-Control-Allow-Origin", "*") } else { h.Set("Access-Control-Allow-Origin", origin) }
+        h.Set("Access-Control-Expose-Headers", "X-Request-ID, X-Result-Count, X-Has-More, X-Skipped-Card, X-Unresolved-Card")
+        if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+            h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+            h.Set("Access-Control-Allow-Headers", "Content-Type, X-Request-ID")
+            h.Set("Access-Control-Max-Age", "600")
+            w.WriteHeader(http.StatusNoContent)
+            return
+        }
+        next.ServeHTTP(w, r)
+    })
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+    "compress/gzip"
+    "net/http"
+    "strings"
+    "sync"
+)
+
+// gzipMinSize is the smallest body worth compressing; below it the gzip header and
+// CPU cost outweigh the savings.
+const gzipMinSize = 1024
+
+var gzipPool = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+
+// Gzip compresses responses for clients that accept it. The body is buffered until
+// gzipMinSize bytes so tiny responses go out as-is; already-compressed content (images,
+// archives, or a handler-set Content-Encoding), partial content and server-sent event
+// streams are never compressed.
+func Gzip(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Add("Vary", "Accept-Encoding")
+        if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+            next.ServeHTTP(w, r)
+            return
+        }
+        gw := &gzipWriter{ResponseWriter: w, status: http.StatusOK}
+        defer gw.finish()
+        next.ServeHTTP(gw, r)
+    })
+}
+
+// acceptsGzip reports whether an Accept-Encoding header lists gzip without q=0.
+func acceptsGzip(header string) bool {
+    for _, part := range strings.Split(header, ",") {
+        coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+        if !strings.EqualFold(strings.TrimSpace(coding), "gzip") { continue }
+        q := strings.ReplaceAll(params, " ", "")
+        return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+    }
+    return false
+}
+
+// gzipWriter holds back the header and the first bytes until it knows whether to compress.
+type gzipWriter struct {
+    http.ResponseWriter
+    status  int
+    buf     []byte
+    decided bool
+    gz      *gzip.Writer // nil when passing through
+}
+
+func (g *gzipWriter) WriteHeader(code int) {
+    if g.decided { return }
+    g.status = code
+}
+
+func (g *gzipWriter) Write(p []byte) (int, error) {
+    if !g.decided {
+        g.buf = append(g.buf, p...)
+        if len(g.buf) < gzipMinSize { return len(p), nil }
+        if err := g.decide(); err != nil { return 0, err }
+        return len(p), nil
+    }
+    if g.gz != nil { return g.gz.Write(p) }
+    return g.ResponseWriter.Write(p)
+}
+
+// decide picks compression once the buffer is large enough (or the handler flushes or
+// returns), sends the header, and writes out the buffered bytes.
+func (g *gzipWriter) decide() error {
+    g.decided = true
+    h := g.ResponseWriter.Header()
+    if h.Get("Content-Type") == "" && len(g.buf) > 0 {
+        h.Set("Content-Type", http.DetectContentType(g.buf)) // sniff before the bytes are compressed
+    }
+    if len(g.buf) >= gzipMinSize && compressible(g.status, h) {
+        h.Set("Content-Encoding", "gzip")
+        h.Del("Content-Length")
+        g.gz = gzipPool.Get().(*gzip.Writer)
+        g.gz.Reset(g.ResponseWriter)
+    }
+    g.ResponseWriter.WriteHeader(g.status)
+    buf := g.buf
+    g.buf = nil
+    if len(buf) == 0 { return nil }
+    var err error
+    if g.gz != nil { _, err = g.gz.Write(buf) } else { _, err = g.ResponseWriter.Write(buf) }
+    return err
+}
+
+func compressible(status int, h http.Header) bool {
+    if status < 200 || status == http.StatusNoContent || status == http.StatusPartialContent || status == http.StatusNotModified {
+        return false
+    }
+    if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" { return false }
+    ct := strings.ToLower(h.Get("Content-Type"))
+    if strings.HasPrefix(ct, "image/svg") { return true } // the one text image format
+    for _, skip := range []string{"image/", "video/", "audio/", "font/woff", "application/zip", "application/gzip", "application/x-gzip", "application/octet-stream", "text/event-stream"} {
+        if strings.HasPrefix(ct, skip) { return false }
+    }
+    return true
+}
+
+// FlushError sends what is buffered so far; a flush before gzipMinSize bytes (e.g. an
+// event stream) commits to an uncompressed response. http.ResponseController prefers it
+// over Flush, and it walks the wrapped writers (which may not implement http.Flusher).
+func (g *gzipWriter) FlushError() error {
+    if !g.decided {
+        if err := g.decide(); err != nil { return err }
+    }
+    if g.gz != nil {
+        if err := g.gz.Flush(); err != nil { return err }
+    }
+    return http.NewResponseController(g.ResponseWriter).Flush()
+}
+
+func (g *gzipWriter) Flush() { _ = g.FlushError() }
+
+// Unwrap lets http.ResponseController reach the underlying writer for deadlines.
+func (g *gzipWriter) Unwrap() http.ResponseWriter { return g.ResponseWriter }
+
+func (g *gzipWriter) finish() {
+    if !g.decided { _ = g.decide() }
+    if g.gz != nil {
+        _ = g.gz.Close()
+        g.gz.Reset(nil)
+        gzipPool.Put(g.gz)
+        g.gz = nil
+    }
+}
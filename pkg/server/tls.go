@@ -0,0 +1,34 @@
+// Package server holds HTTP plumbing shared by similarityd and the web app.
+package server
+
+import (
+    "crypto/tls"
+    "errors"
+    "fmt"
+    "net/http"
+    "os"
+)
+
+// LoadTLS validates and loads a certificate/key pair. It returns (nil, nil) when
+// neither file is configured, and an error when only one is set or loading fails,
+// so callers can fail fast at startup.
+func LoadTLS(certFile, keyFile string) (*tls.Config, error) {
+    if certFile == "" && keyFile == "" { return nil, nil }
+    if certFile == "" || keyFile == "" {
+        return nil, errors.New("both -tls-cert and -tls-key must be set to enable TLS")
+    }
+    for _, f := range []string{certFile, keyFile} {
+        if _, err := os.Stat(f); err != nil { return nil, fmt.Errorf("tls: %w", err) }
+    }
+    cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+    if err != nil { return nil, fmt.Errorf("tls: load key pair: %w", err) }
+    return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// ListenAndServe serves HTTPS when srv.TLSConfig carries certificates, plain HTTP otherwise.
+func ListenAndServe(srv *http.Server) error {
+    if srv.TLSConfig != nil && len(srv.TLSConfig.Certificates) > 0 {
+        return srv.ListenAndServeTLS("", "")
+    }
+    return srv.ListenAndServe()
+}
@@ -0,0 +1,101 @@
+package weaviateclient
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sort"
+)
+
+// GroupCount is one bucket of an Aggregate groupBy query.
+type GroupCount struct {
+    Value string `json:"value"`
+    Count int    `json:"count"`
+}
+
+// AggregateByField counts Card objects grouped by a property (e.g. "rarity", "colors", "set", "cmc").
+// Buckets are sorted by count descending, then value. An empty class yields an empty slice.
+func (c *Client) AggregateByField(ctx context.Context, groupBy string) ([]GroupCount, error) {
+    return c.AggregateByFieldWhere(ctx, groupBy, nil)
+}
+
+// AggregateByFieldWhere is AggregateByField over the Card objects matching where (all of them
+// for nil). No matching cards yields an empty slice.
+func (c *Client) AggregateByFieldWhere(ctx context.Context, groupBy string, where *WhereFilter) ([]GroupCount, error) {
+    args := ""
+    if where != nil { args = fmt.Sprintf(", where:%s", where) }
+    q := fmt.Sprintf(`{ Aggregate { Card(groupBy:[%q]%s){ groupedBy { value } meta { count } } } }`, groupBy, args)
+    data, err := c.do(ctx, OpAggregate, q)
+    if err != nil { return nil, err }
+    var o struct { Aggregate struct { Card []struct {
+        GroupedBy struct { Value json.RawMessage `json:"value"` } `json:"groupedBy"`
+        Meta      struct { Count int `json:"count"` } `json:"meta"`
+    } `json:"Card"` } `json:"Aggregate"` }
+    if err := json.Unmarshal(data, &o); err != nil { return nil, err }
+    out := make([]GroupCount, 0, len(o.Aggregate.Card))
+    for _, g := range o.Aggregate.Card {
+        out = append(out, GroupCount{Value: rawValue(g.GroupedBy.Value), Count: g.Meta.Count})
+    }
+    sort.Slice(out, func(i, j int) bool {
+        if out[i].Count == out[j].Count { return out[i].Value < out[j].Value }
+        return out[i].Count > out[j].Count
+    })
+    return out, nil
+}
+
+// AggregateGroupBy counts Card objects per value of property, e.g. {"common": 120, "rare": 40}.
+// Multi-valued properties (colors) count an object once per value. An empty class yields an empty map.
+func (c *Client) AggregateGroupBy(ctx context.Context, property string) (map[string]int, error) {
+    groups, err := c.AggregateByField(ctx, property)
+    if err != nil { return nil, err }
+    out := make(map[string]int, len(groups))
+    for _, g := range groups { out[g.Value] += g.Count }
+    return out, nil
+}
+
+// CountCards returns the total number of Card objects.
+func (c *Client) CountCards(ctx context.Context) (int, error) { return c.CountWhere(ctx, nil) }
+
+// CountWhere returns the number of Card objects matching where (all of them for nil).
+func (c *Client) CountWhere(ctx context.Context, where *WhereFilter) (int, error) {
+    q := `{ Aggregate { Card { meta { count } } } }`
+    if where != nil { q = fmt.Sprintf(`{ Aggregate { Card(where:%s){ meta { count } } } }`, where) }
+    data, err := c.do(ctx, OpAggregate, q)
+    if err != nil { return 0, err }
+    var o struct { Aggregate struct { Card []struct {
+        Meta struct { Count int `json:"count"` } `json:"meta"`
+    } `json:"Card"` } `json:"Aggregate"` }
+    if err := json.Unmarshal(data, &o); err != nil { return 0, err }
+    if len(o.Aggregate.Card) == 0 { return 0, nil }
+    return o.Aggregate.Card[0].Meta.Count, nil
+}
+
+// AggregateCMC returns the mean, minimum and maximum cmc of the Card objects matching where
+// (all of them for nil), computed by Weaviate. No matching cards yields zeros and no error.
+func (c *Client) AggregateCMC(ctx context.Context, where *WhereFilter) (mean, min, max float64, err error) {
+    args := ""
+    if where != nil { args = fmt.Sprintf("(where:%s)", where) }
+    data, err := c.do(ctx, OpAggregate, fmt.Sprintf(`{ Aggregate { Card%s{ cmc { mean minimum maximum } } } }`, args))
+    if err != nil { return 0, 0, 0, err }
+    var o struct { Aggregate struct { Card []struct {
+        CMC struct {
+            Mean    *float64 `json:"mean"`
+            Minimum *float64 `json:"minimum"`
+            Maximum *float64 `json:"maximum"`
+        } `json:"cmc"`
+    } `json:"Card"` } `json:"Aggregate"` }
+    if err := json.Unmarshal(data, &o); err != nil { return 0, 0, 0, err }
+    if len(o.Aggregate.Card) == 0 { return 0, 0, 0, nil }
+    val := func(f *float64) float64 { if f == nil { return 0 }; return *f }
+    a := o.Aggregate.Card[0].CMC
+    return val(a.Mean), val(a.Minimum), val(a.Maximum), nil
+}
+
+// rawValue renders a groupedBy value (string or number) as plain text.
+func rawValue(v json.RawMessage) string {
+    var s string
+    if err := json.Unmarshal(v, &s); err == nil { return s }
+    var f float64
+    if err := json.Unmarshal(v, &f); err == nil { return fmt.Sprintf("%g", f) }
+    return string(v)
+}
@@ -7,30 +7,66 @@ import (
     "errors"
     "fmt"
     "io"
+    "log/slog"
     "net/http"
+    "sort"
+    "strconv"
     "strings"
+    "sync"
     "time"
+
+    "github.com/domano/decktech/pkg/cardfilter"
+    "github.com/domano/decktech/pkg/requestid"
 )
 
 // Client is a minimal GraphQL helper for Weaviate focused on the Card class.
 // It provides typed helpers used by the REST server, TUIs, and the web app.
 type Client struct {
-    baseURL string
-    http    *http.Client
+    baseURL     string
+    http        *http.Client
+    consistency string   // default consistency level for Get queries; "" = Weaviate default
+    missing     sync.Map // optional properties (see doOptional) this collection lacks
+    targets     sync.Map // named vectors confirmed by checkTargetVector
+    limiter     *limiter // nil = unlimited; see WithRateLimit
+    timeouts    map[Operation]time.Duration // per request; see WithTimeout
+    log         *slog.Logger                // failed and partial requests; see WithLogger
 }
 
+// transport is shared by every Client so keep-alive connections are pooled per process,
+// even where callers construct a Client per request.
+var transport = func() *http.Transport {
+    t := http.DefaultTransport.(*http.Transport).Clone()
+    t.MaxIdleConns = 100
+    t.MaxIdleConnsPerHost = 32 // default of 2 thrashes connections under concurrent requests to one Weaviate
+    t.IdleConnTimeout = 90 * time.Second
+    return t
+}()
+
 // NewClient creates a new client. baseURL should be like "http://localhost:8080".
-func NewClient(baseURL string) *Client {
-    return &Client{
-        baseURL: strings.TrimRight(baseURL, "/"),
-        http:    &http.Client{Timeout: 15 * time.Second},
+// Clients are safe for concurrent use; prefer one per process.
+func NewClient(baseURL string, opts ...Option) *Client {
+    c := &Client{
+        baseURL:  strings.TrimRight(baseURL, "/"),
+        http:     &http.Client{Transport: requestid.Transport(transport)},
+        timeouts: map[Operation]time.Duration{},
+        log:      slog.New(slog.DiscardHandler),
     }
+    for op, d := range defaultTimeouts { c.timeouts[op] = d }
+    for _, o := range opts { o(c) }
+    return c
+}
+
+// WithLogger logs failed and partial Weaviate requests to l. Without it the client logs
+// nothing and only returns errors, so terminal UIs drawing on stdout/stderr stay intact.
+func WithLogger(l *slog.Logger) Option {
+    return func(c *Client) { if l != nil { c.log = l } }
 }
 
 // Card is a union of commonly used card fields. Not all fields will be set in all queries.
 type Card struct {
     ID           string            `json:"id"`
     ScryfallID   string            `json:"scryfall_id"`
+    OracleID     string            `json:"oracle_id,omitempty"` // shared by all printings; empty before oracle_id was ingested
     Name         string            `json:"name"`
     TypeLine     string            `json:"type_line"`
     ManaCost     string            `json:"mana_cost"`
@@ -41,14 +77,40 @@ type Card struct {
     Colors       []string          `json:"colors"`
     ColorID      []string          `json:"color_identity"`
     Keywords     []string          `json:"keywords"`
+    EDHRECRank   int               `json:"edhrec_rank,omitempty"` // detail queries only; 0 when unranked
     Set          string            `json:"set"`
     CollectorNum string            `json:"collector_number"`
     Rarity       string            `json:"rarity"`
     Layout       string            `json:"layout"`
+    ImageSmall   string            `json:"image_small,omitempty"`
     ImageNormal  string            `json:"image_normal"`
+    ImageLarge   string            `json:"image_large,omitempty"` // empty for collections ingested before image_large
     Distance     float64           `json:"distance"`
     Similarity   float64           `json:"similarity"`
+    Score        float64           `json:"score,omitempty"` // bm25 relevance, SearchBM25 only (higher is better)
     Legalities   map[string]string `json:"legalities"`
+    Printings    int               `json:"printings,omitempty"` // extra printings collapsed by DedupeByName
+    Faces        []CardFace        `json:"card_faces,omitempty"` // multi-face cards only (transform, modal_dfc, split, ...)
+    PriceUSD     string            `json:"price_usd,omitempty"` // Scryfall price strings; empty when not ingested
+    PriceEUR     string            `json:"price_eur,omitempty"`
+    PriceTix     string            `json:"price_tix,omitempty"`
+    Vector       []float64         `json:"vector,omitempty"` // only set by SearchNearVectorWithVectors
+}
+
+// FilterFields lets cards be filtered and sorted with pkg/cardfilter.
+func (c Card) FilterFields() cardfilter.Fields {
+    return cardfilter.Fields{Name: c.Name, TypeLine: c.TypeLine, Colors: c.Colors, CMC: c.CMC, Similarity: c.Similarity, PriceUSD: c.PriceUSD}
+}
+
+// CardFace is one face of a multi-faced card, decoded from the card_faces JSON string property.
+type CardFace struct {
+    Name        string `json:"name"`
+    ManaCost    string `json:"mana_cost"`
+    TypeLine    string `json:"type_line"`
+    OracleText  string `json:"oracle_text"`
+    Power       string `json:"power"`
+    Toughness   string `json:"toughness"`
+    ImageNormal string `json:"image_normal"`
 }
 
 type gqlResp struct {
@@ -58,8 +120,15 @@ type gqlResp struct {
     } `json:"errors"`
 }
 
-// do runs a GraphQL query and returns the raw data payload.
-func (c *Client) do(ctx context.Context, query string) (json.RawMessage, error) {
+// do runs a GraphQL query and returns the raw data payload. op picks the request's timeout.
+// A response with both data and errors returns the data with a *PartialError.
+func (c *Client) do(ctx context.Context, op Operation, query string) (json.RawMessage, error) {
+    if c.limiter != nil {
+        if err := c.limiter.wait(ctx); err != nil { return nil, err }
+    }
+    parent := ctx
+    ctx, cancel := c.withTimeout(ctx, op)
+    defer cancel()
     endpoint := c.baseURL + "/v1/graphql"
     body := map[string]string{"query": query}
     b, _ := json.Marshal(body)
@@ -70,38 +139,98 @@ func (c *Client) do(ctx context.Context, query string) (json.RawMessage, error)
     req.Header.Set("Content-Type", "application/json")
     resp, err := c.http.Do(req)
     if err != nil {
+        err = timeoutErr(parent, op, c.timeouts[op], err)
+        c.log.ErrorContext(ctx, "weaviate request failed", "endpoint", endpoint, "op", op, "err", err)
         return nil, err
     }
     defer resp.Body.Close()
     if resp.StatusCode != http.StatusOK {
         data, _ := io.ReadAll(resp.Body)
+        c.log.ErrorContext(ctx, "weaviate graphql status", "endpoint", endpoint, "status", resp.StatusCode)
         return nil, fmt.Errorf("graphql status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
     }
     var wr gqlResp
     if err := json.NewDecoder(resp.Body).Decode(&wr); err != nil {
-        return nil, err
+        return nil, timeoutErr(parent, op, c.timeouts[op], err)
+    }
+    if len(wr.Errors) > 0 && resolvedAny(wr.Data) {
+        // Partial success: hand back what resolved, failing the call unless ctx allows it.
+        pe := &PartialError{RequestID: requestid.FromContext(ctx)}
+        for _, e := range wr.Errors { pe.Messages = append(pe.Messages, e.Message) }
+        c.log.WarnContext(ctx, "weaviate graphql partial response", "err", wr.Errors[0].Message, "errors", len(wr.Errors), "allowed", partialAllowed(ctx))
+        if partialAllowed(ctx) { return wr.Data, nil }
+        return wr.Data, pe
     }
     if len(wr.Errors) > 0 {
+        c.log.WarnContext(ctx, "weaviate graphql error", "err", wr.Errors[0].Message, "errors", len(wr.Errors))
+        if id := requestid.FromContext(ctx); id != "" {
+            return nil, fmt.Errorf("%s (request_id=%s)", wr.Errors[0].Message, id)
+        }
         return nil, errors.New(wr.Errors[0].Message)
     }
     return wr.Data, nil
 }
 
-// FetchVectorForName returns (vector, objectID) for an exact name, with LIKE fallback.
+// resolvedAny reports whether data holds a non-null result for some class, e.g. Get.Card. An
+// errored query still comes back as {"Get":{"Card":null}}, which is a failure, not a partial.
+func resolvedAny(data json.RawMessage) bool {
+    var top map[string]map[string]json.RawMessage
+    if err := json.Unmarshal(data, &top); err != nil { return false }
+    for _, classes := range top {
+        for _, v := range classes {
+            if len(v) > 0 && string(v) != "null" { return true }
+        }
+    }
+    return false
+}
+
+// ErrNoVector is returned (wrapped with the card name) when a card exists but has no
+// embedding, e.g. an object ingested without vectors. Test with errors.Is.
+var ErrNoVector = errors.New("card has no vector")
+
+func noVector(name string) error { return fmt.Errorf("%w: %s", ErrNoVector, name) }
+
+// ErrCardNotFound is returned (wrapped with the name or ID) when no card matches a lookup.
+var ErrCardNotFound = errors.New("card not found")
+
+func notFound(key string) error { return fmt.Errorf("%w: %s", ErrCardNotFound, key) }
+
+// vectorByNameQuery selects the target vector ("" for the unnamed one) of the card with
+// exactly this name.
+func vectorByNameQuery(name, target string) string {
+    return fmt.Sprintf(`{ Get { Card(where:{path:["name"], operator: Equal, valueString:%s}, limit:1){ name _additional{ id %s } } } }`, gqlString(name), vectorSelection(target))
+}
+
+// vectorByNameLikeQuery selects the target vector of the first card whose name contains name,
+// with more candidates when name has Like wildcards (see literalMatch).
+func vectorByNameLikeQuery(name, target string) string {
+    return fmt.Sprintf(`{ Get { Card(where:{path:["name"], operator: Like, valueText:%s}, limit:%d){ name _additional{ id %s } } } }`, gqlString(likeContains(name)), likeLimit(name, 1), vectorSelection(target))
+}
+
+// vectorByScryfallIDQuery selects the target vector of the card with this scryfall_id.
+func vectorByScryfallIDQuery(scryID, target string) string {
+    return fmt.Sprintf(`{ Get { Card(where:{path:["scryfall_id"], operator: Equal, valueString:%s}, limit:1){ scryfall_id _additional{ id %s } } } }`, gqlString(scryID), vectorSelection(target))
+}
+
+// cardByScryfallIDQuery selects detailFields for the card with this scryfall_id.
+func cardByScryfallIDQuery(scryfallID string) string {
+    return fmt.Sprintf(`{ Get { Card(where:{path:["scryfall_id"], operator: Equal, valueString:%s}, limit:1){ %s } } }`, gqlString(scryfallID), detailFields)
+}
+
+// FetchVectorForName returns (vector, objectID) for an exact name, with LIKE fallback. See
+// WithTargetVector to read a named vector.
 func (c *Client) FetchVectorForName(ctx context.Context, name string) ([]float64, string, error) {
-    q := fmt.Sprintf(`{ Get { Card(where:{path:["name"], operator: Equal, valueString:%q}, limit:1){ name _additional{ id vector } } } }`, name)
-    data, err := c.do(ctx, q)
+    target := targetVectorOf(ctx)
+    if err := c.checkTargetVector(ctx, target); err != nil { return nil, "", err }
+    data, err := c.do(ctx, OpList, vectorByNameQuery(name, target))
     if err != nil {
         return nil, "", err
     }
     var o struct{
         Get struct{
             Card []struct{
-                Name string `json:"name"`
-                Add  struct{
-                    ID     string    `json:"id"`
-                    Vector []float64 `json:"vector"`
-                } `json:"_additional"`
+                Name string           `json:"name"`
+                Add  additionalVector `json:"_additional"`
             } `json:"Card"`
         } `json:"Get"`
     }
@@ -109,35 +238,62 @@ func (c *Client) FetchVectorForName(ctx context.Context, name string) ([]float64
         return nil, "", err
     }
     if len(o.Get.Card) == 0 {
-        like := fmt.Sprintf("*%s*", name)
-        q2 := fmt.Sprintf(`{ Get { Card(where:{path:["name"], operator: Like, valueText:%q}, limit:1){ name _additional{ id vector } } } }`, like)
-        d2, err2 := c.do(ctx, q2)
+        d2, err2 := c.do(ctx, OpList, vectorByNameLikeQuery(name, target))
         if err2 != nil {
-            return nil, "", fmt.Errorf("card not found: %s", name)
+            return nil, "", fmt.Errorf("like lookup for %s: %w", name, err2)
         }
-        var o2 struct{
-            Get struct{
-                Card []struct{
-                    Name string `json:"name"`
-                    Add  struct{ ID string `json:"id"`; Vector []float64 `json:"vector"` } `json:"_additional"`
-                } `json:"Card"`
-            } `json:"Get"`
+        if err := json.Unmarshal(d2, &o); err != nil { return nil, "", err }
+        hits := o.Get.Card[:0]
+        for _, c0 := range o.Get.Card {
+            if literalMatch(name, c0.Name) { hits = append(hits, c0) }
         }
-        if err := json.Unmarshal(d2, &o2); err != nil || len(o2.Get.Card) == 0 {
-            return nil, "", fmt.Errorf("card not found: %s", name)
-        }
-        c0 := o2.Get.Card[0]
-        return c0.Add.Vector, c0.Add.ID, nil
+        if len(hits) == 0 { return nil, "", notFound(name) }
+        o.Get.Card = hits
     }
     c0 := o.Get.Card[0]
-    return c0.Add.Vector, c0.Add.ID, nil
+    vec := c0.Add.of(target)
+    if len(vec) == 0 { return nil, c0.Add.ID, noVector(c0.Name) }
+    return vec, c0.Add.ID, nil
 }
 
-// SearchNearVector returns the top-k similar cards to a query vector.
+// SearchNearVector returns the top-k similar cards to a query vector. See WithEf to raise
+// recall and WithAutocut to trim weak tail results for a call.
 func (c *Client) SearchNearVector(ctx context.Context, vector []float64, k int) ([]Card, error) {
-    vb, _ := json.Marshal(vector)
-    q := fmt.Sprintf(`{ Get { Card(nearVector:{ vector:%s }, limit:%d){ scryfall_id name type_line mana_cost cmc colors set rarity oracle_text image_normal _additional{ id distance } } } }`, string(vb), k)
-    data, err := c.do(ctx, q)
+    return c.SearchNearVectorFiltered(ctx, vector, k, nil)
+}
+
+// SearchNearVectorFiltered is SearchNearVector restricted by a where filter (nil means unfiltered),
+// so the top-k is drawn only from matching cards rather than post-filtered in Go.
+func (c *Client) SearchNearVectorFiltered(ctx context.Context, vector []float64, k int, where *WhereFilter) ([]Card, error) {
+    return c.searchNearVector(ctx, vector, k, where, false)
+}
+
+// SearchNearVectorWithVectors is SearchNearVectorFiltered that also fills Card.Vector, for callers
+// re-ranking or clustering results themselves. Each vector adds a few KB per result (384 floats
+// for MiniLM), so only use it when the vectors are actually needed.
+func (c *Client) SearchNearVectorWithVectors(ctx context.Context, vector []float64, k int, where *WhereFilter) ([]Card, error) {
+    return c.searchNearVector(ctx, vector, k, where, true)
+}
+
+func (c *Client) searchNearVector(ctx context.Context, vector []float64, k int, where *WhereFilter, withVectors bool) ([]Card, error) {
+    if len(vector) == 0 { return nil, fmt.Errorf("%w: empty query vector", ErrNoVector) }
+    target := targetVectorOf(ctx)
+    if err := c.checkTargetVector(ctx, target); err != nil { return nil, err }
+    add := "id distance"
+    if withVectors { add += " " + vectorSelection(target) }
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: where, nearVector: vector, targetVector: target, autocut: autocutOf(ctx), limit: searchLimit(ctx, k)}
+    return c.runSearch(ctx, q, add, target, k)
+}
+
+// searchFields are the properties a search result selects, before its _additional block.
+const searchFields = "scryfall_id name type_line mana_cost cmc colors set collector_number rarity oracle_text image_small image_normal image_large legalities prices"
+
+// runSearch runs a ranked search query with _additional{ add } and decodes up to k results.
+// Results selecting a bm25 score get Score and no similarity; the rest get Distance and
+// Similarity.
+func (c *Client) runSearch(ctx context.Context, b queryBuilder, add, target string, k int) ([]Card, error) {
+    b.fields = searchFields + " _additional{ " + add + " }"
+    data, err := c.doOptional(ctx, OpSearch, b.String(), listOptional...)
     if err != nil {
         return nil, err
     }
@@ -151,10 +307,15 @@ func (c *Client) SearchNearVector(ctx context.Context, vector []float64, k int)
                 CMC    float64 `json:"cmc"`
                 Colors []string `json:"colors"`
                 Set    string   `json:"set"`
+                Coll   string   `json:"collector_number"`
                 Rarity string   `json:"rarity"`
                 Oracle string `json:"oracle_text"`
+                ImgS   string `json:"image_small"`
                 Img    string `json:"image_normal"`
-                Add    struct{ ID string `json:"id"`; Distance float64 `json:"distance"` } `json:"_additional"`
+                ImgL   string `json:"image_large"`
+                Legal  string `json:"legalities"`
+                Prices string `json:"prices"`
+                Add    additionalVector `json:"_additional"`
             } `json:"Card"`
         } `json:"Get"`
     }
@@ -163,32 +324,81 @@ func (c *Client) SearchNearVector(ctx context.Context, vector []float64, k int)
     }
     out := make([]Card, 0, len(o.Get.Card))
     for _, c0 := range o.Get.Card {
-        sim := 1.0 - c0.Add.Distance
+        var sim, score float64
+        if c0.Add.Score != "" {
+            score, _ = strconv.ParseFloat(c0.Add.Score, 64)
+        } else {
+            c.checkDistance(ctx, c0.Add.Distance)
+            sim = SimilarityFromDistance(c0.Add.Distance)
+        }
+        var leg map[string]string
+        if c0.Legal != "" { _ = json.Unmarshal([]byte(c0.Legal), &leg) }
+        usd, eur, tix := parsePrices(c0.Prices)
         out = append(out, Card{
             ID: c0.Add.ID, ScryfallID: c0.ScryID, Name: c0.Name, TypeLine: c0.Type, ManaCost: c0.Mana,
-            CMC: c0.CMC, Colors: c0.Colors, Rarity: c0.Rarity, Set: c0.Set,
-            OracleText: c0.Oracle, ImageNormal: c0.Img, Distance: c0.Add.Distance, Similarity: sim,
+            CMC: c0.CMC, Colors: c0.Colors, Rarity: c0.Rarity, Set: c0.Set, CollectorNum: c0.Coll, Legalities: leg,
+            OracleText: c0.Oracle, ImageSmall: c0.ImgS, ImageNormal: c0.Img, ImageLarge: c0.ImgL, Distance: c0.Add.Distance, Similarity: sim, Score: score,
+            PriceUSD: usd, PriceEUR: eur, PriceTix: tix, Vector: c0.Add.of(target),
         })
     }
+    if len(out) > k { out = out[:k] } // WithEf fetched extra candidates
     return out, nil
 }
 
-// FetchVectorByScryfallID returns (vector, objectID) for a given scryfall_id.
+// SimilarityFromDistance converts a cosine distance into a similarity clamped to [0,1].
+// Distances above 1 (possible with un-normalized vectors) map to 0 rather than going negative.
+func SimilarityFromDistance(d float64) float64 {
+    return min(1, max(0, 1.0-d))
+}
+
+var distanceWarning sync.Once
+
+// checkDistance logs once per process when a cosine distance falls outside [0,2],
+// which signals that the stored vectors aren't normalized.
+func (c *Client) checkDistance(ctx context.Context, d float64) {
+    if d >= 0 && d <= 2 { return }
+    distanceWarning.Do(func() {
+        c.log.WarnContext(ctx, "cosine distance outside [0,2]; vectors may not be normalized", "distance", d)
+    })
+}
+
+// DedupeByName collapses cards sharing a name, keeping the first (i.e. best-ranked)
+// occurrence and counting the dropped printings in its Printings field.
+// Call it on similarity-ordered results so the best match per name survives.
+func DedupeByName(cards []Card) []Card {
+    pos := map[string]int{}
+    out := make([]Card, 0, len(cards))
+    for _, c := range cards {
+        if i, ok := pos[c.Name]; ok {
+            out[i].Printings++
+            continue
+        }
+        pos[c.Name] = len(out)
+        out = append(out, c)
+    }
+    return out
+}
+
+// FetchVectorByScryfallID returns (vector, objectID) for a given scryfall_id. See
+// WithTargetVector to read a named vector.
 func (c *Client) FetchVectorByScryfallID(ctx context.Context, scryID string) ([]float64, string, error) {
-    q := fmt.Sprintf(`{ Get { Card(where:{path:["scryfall_id"], operator: Equal, valueString:%q}, limit:1){ scryfall_id _additional{ id vector } } } }`, scryID)
-    data, err := c.do(ctx, q)
+    target := targetVectorOf(ctx)
+    if err := c.checkTargetVector(ctx, target); err != nil { return nil, "", err }
+    data, err := c.do(ctx, OpList, vectorByScryfallIDQuery(scryID, target))
     if err != nil { return nil, "", err }
-    var o struct{ Get struct{ Card []struct{ Scry string `json:"scryfall_id"`; Add struct{ ID string `json:"id"`; Vector []float64 `json:"vector"` } `json:"_additional"` } `json:"Card"` } `json:"Get"` }
+    var o struct{ Get struct{ Card []struct{ Scry string `json:"scryfall_id"`; Add additionalVector `json:"_additional"` } `json:"Card"` } `json:"Get"` }
     if err := json.Unmarshal(data, &o); err != nil { return nil, "", err }
-    if len(o.Get.Card) == 0 { return nil, "", fmt.Errorf("card not found: %s", scryID) }
+    if len(o.Get.Card) == 0 { return nil, "", notFound(scryID) }
     c0 := o.Get.Card[0]
-    return c0.Add.Vector, c0.Add.ID, nil
+    vec := c0.Add.of(target)
+    if len(vec) == 0 { return nil, c0.Add.ID, noVector(scryID) }
+    return vec, c0.Add.ID, nil
 }
 
 // ListCards returns a simple list view for browsing.
 func (c *Client) ListCards(ctx context.Context, offset, limit int) ([]Card, error) {
-    q := fmt.Sprintf(`{ Get { Card(limit:%d, offset:%d){ scryfall_id name type_line mana_cost cmc colors set rarity oracle_text image_normal _additional{ id } } } }`, limit, offset)
-    data, err := c.do(ctx, q)
+    q := queryBuilder{consistency: c.consistencyFor(ctx), sort: sortOf(ctx), limit: limit, offset: offset, fields: browseFields}.String()
+    data, err := c.doOptional(ctx, OpList, q, propImageLarge)
     if err != nil { return nil, err }
     var outer struct { Get struct { Card []struct {
         Scry string `json:"scryfall_id"`
@@ -200,22 +410,125 @@ func (c *Client) ListCards(ctx context.Context, offset, limit int) ([]Card, erro
         Set   string `json:"set"`
         Rarity string `json:"rarity"`
         Oracle string `json:"oracle_text"`
+        ImgS string `json:"image_small"`
         Img string `json:"image_normal"`
+        ImgL string `json:"image_large"`
         Add struct { ID string `json:"id"` } `json:"_additional"`
     } `json:"Card"` } `json:"Get"` }
     if err := json.Unmarshal(data, &outer); err != nil { return nil, err }
     out := make([]Card, 0, len(outer.Get.Card))
     for _, c0 := range outer.Get.Card {
-        out = append(out, Card{ID: c0.Add.ID, ScryfallID: c0.Scry, Name: c0.Name, TypeLine: c0.Type, ManaCost: c0.Mana, CMC: c0.CMC, Colors: c0.Colors, Set: c0.Set, Rarity: c0.Rarity, OracleText: c0.Oracle, ImageNormal: c0.Img})
+        out = append(out, Card{ID: c0.Add.ID, ScryfallID: c0.Scry, Name: c0.Name, TypeLine: c0.Type, ManaCost: c0.Mana, CMC: c0.CMC, Colors: c0.Colors, Set: c0.Set, Rarity: c0.Rarity, OracleText: c0.Oracle, ImageSmall: c0.ImgS, ImageNormal: c0.Img, ImageLarge: c0.ImgL})
+    }
+    return out, nil
+}
+
+// ListCardsFiltered is ListCards narrowed by optional set code and rarity (e.g. "mh3", "mythic").
+// Empty arguments are ignored; with neither set it behaves exactly like ListCards.
+func (c *Client) ListCardsFiltered(ctx context.Context, offset, limit int, set, rarity string) ([]Card, error) {
+    where := browseFilter(set, rarity)
+    if where == nil { return c.ListCards(ctx, offset, limit) }
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: where, sort: sortOf(ctx), limit: limit, offset: offset, fields: listFields}.String()
+    data, err := c.doOptional(ctx, OpList, q, listOptional...)
+    if err != nil { return nil, err }
+    return decodeCardList(data)
+}
+
+// browseFilter narrows a browse listing to a set and rarity (either may be empty); nil when
+// neither is given.
+func browseFilter(set, rarity string) *WhereFilter {
+    var ops []*WhereFilter
+    if set = strings.ToLower(strings.TrimSpace(set)); set != "" {
+        ops = append(ops, TextFilter("Equal", "set", set))
+    }
+    if rarity = strings.ToLower(strings.TrimSpace(rarity)); rarity != "" {
+        ops = append(ops, TextFilter("Equal", "rarity", rarity))
+    }
+    return And(ops...)
+}
+
+// ListCardsPage is ListCards plus the number of cards in the collection, so callers can show
+// and bound real pagination instead of fetching an extra row to detect a next page. It costs a
+// Get and an Aggregate.
+func (c *Client) ListCardsPage(ctx context.Context, offset, limit int) (cards []Card, total int, err error) {
+    return c.ListCardsFilteredPage(ctx, offset, limit, "", "")
+}
+
+// ListCardsFilteredPage is ListCardsFiltered plus the number of cards matching set and rarity.
+func (c *Client) ListCardsFilteredPage(ctx context.Context, offset, limit int, set, rarity string) (cards []Card, total int, err error) {
+    if cards, err = c.ListCardsFiltered(ctx, offset, limit, set, rarity); err != nil { return nil, 0, err }
+    if total, err = c.CountWhere(ctx, browseFilter(set, rarity)); err != nil { return nil, 0, err }
+    return cards, total, nil
+}
+
+// SearchCards matches query against both name and oracle_text (LIKE) and ranks exact-name
+// matches first, then name substrings, then oracle-only hits, unless ctx asks for a sort
+// (WithSort). A * or ? in query matches literally. Weaviate applies the limit in its own
+// order, so for the ranked view the cards are collected in rank order, each query only
+// filling what the previous ones left: the card named query, then name matches, then
+// rules-text matches.
+func (c *Client) SearchCards(ctx context.Context, query string, limit int) ([]Card, error) {
+    query = strings.TrimSpace(query)
+    if query == "" { return nil, nil }
+    like := likeContains(query)
+    anywhere := Or(TextFilter("Like", "name", like), TextFilter("Like", "oracle_text", like))
+    var out []Card
+    seen := map[string]struct{}{}
+    add := func(cards []Card, keep func(Card) bool) {
+        for _, c0 := range cards {
+            if len(out) >= limit { return }
+            if _, ok := seen[c0.ID]; ok || (keep != nil && !keep(c0)) || !literalMatch(query, c0.Name, c0.OracleText) { continue }
+            seen[c0.ID] = struct{}{}
+            out = append(out, c0)
+        }
+    }
+    if sortArg(ctx) != "" { // one query, already in the requested order
+        cards, err := c.searchWhere(ctx, anywhere, likeLimit(query, limit))
+        if err != nil { return nil, err }
+        add(cards, nil)
+        return out, nil
     }
+    steps := []struct {
+        where *WhereFilter
+        keep  func(Card) bool
+    }{
+        {TextFilter("Equal", "name", query), func(c0 Card) bool { return strings.EqualFold(c0.Name, query) }},
+        {TextFilter("Like", "name", like), nil},
+        {anywhere, nil},
+    }
+    for _, st := range steps {
+        if len(out) >= limit { break }
+        cards, err := c.searchWhere(ctx, st.where, likeLimit(query, limit))
+        if err != nil { return nil, err }
+        add(cards, st.keep)
+    }
+    lq := strings.ToLower(query)
+    rank := func(c0 Card) int {
+        name := strings.ToLower(c0.Name)
+        switch {
+        case name == lq:
+            return 0
+        case strings.Contains(name, lq):
+            return 1
+        }
+        return 2
+    }
+    sort.SliceStable(out, func(i, j int) bool { return rank(out[i]) < rank(out[j]) })
     return out, nil
 }
 
-// FindByNameLike returns name-matching cards using LIKE.
+// searchWhere lists up to limit cards matching where with listFields, in ctx's sort order.
+func (c *Client) searchWhere(ctx context.Context, where *WhereFilter, limit int) ([]Card, error) {
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: where, sort: sortOf(ctx), limit: limit, fields: listFields}.String()
+    data, err := c.doOptional(ctx, OpList, q, listOptional...)
+    if err != nil { return nil, err }
+    return decodeCardList(data)
+}
+
+// FindByNameLike returns name-matching cards using LIKE; a * or ? in name matches literally.
 func (c *Client) FindByNameLike(ctx context.Context, name string, limit int) ([]Card, error) {
-    like := fmt.Sprintf("*%s*", name)
-    q := fmt.Sprintf(`{ Get { Card(where:{path:["name"], operator: Like, valueText:%q}, limit:%d){ scryfall_id name type_line mana_cost cmc colors set rarity oracle_text image_normal _additional{ id } } } }`, like, limit)
-    data, err := c.do(ctx, q)
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: TextFilter("Like", "name", likeContains(name)), sort: sortOf(ctx), limit: likeLimit(name, limit), fields: browseFields}.String()
+    data, err := c.doOptional(ctx, OpList, q, propImageLarge)
     if err != nil { return nil, err }
     var outer struct { Get struct { Card []struct {
         Scry string `json:"scryfall_id"`
@@ -227,25 +540,122 @@ func (c *Client) FindByNameLike(ctx context.Context, name string, limit int) ([]
         Set   string `json:"set"`
         Rarity string `json:"rarity"`
         Oracle string `json:"oracle_text"`
+        ImgS string `json:"image_small"`
         Img string `json:"image_normal"`
+        ImgL string `json:"image_large"`
         Add struct { ID string `json:"id"` } `json:"_additional"`
     } `json:"Card"` } `json:"Get"` }
     if err := json.Unmarshal(data, &outer); err != nil { return nil, err }
     out := make([]Card, 0, len(outer.Get.Card))
     for _, c0 := range outer.Get.Card {
-        out = append(out, Card{ID: c0.Add.ID, ScryfallID: c0.Scry, Name: c0.Name, TypeLine: c0.Type, ManaCost: c0.Mana, CMC: c0.CMC, Colors: c0.Colors, Set: c0.Set, Rarity: c0.Rarity, OracleText: c0.Oracle, ImageNormal: c0.Img})
+        if len(out) >= limit || !literalMatch(name, c0.Name) { continue }
+        out = append(out, Card{ID: c0.Add.ID, ScryfallID: c0.Scry, Name: c0.Name, TypeLine: c0.Type, ManaCost: c0.Mana, CMC: c0.CMC, Colors: c0.Colors, Set: c0.Set, Rarity: c0.Rarity, OracleText: c0.Oracle, ImageSmall: c0.ImgS, ImageNormal: c0.Img, ImageLarge: c0.ImgL})
+    }
+    return out, nil
+}
+
+// SuggestNames returns up to limit distinct card names containing prefix (a * or ? matching
+// literally), those starting with it first, then alphabetically. It selects only the name
+// so it stays cheap for typeahead.
+func (c *Client) SuggestNames(ctx context.Context, prefix string, limit int) ([]string, error) {
+    prefix = strings.TrimSpace(prefix)
+    if prefix == "" || limit <= 0 { return []string{}, nil }
+    // reprints share a name, so overfetch before deduplicating
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: TextFilter("Like", "name", likeContains(prefix)), limit: likeLimit(prefix, limit*4), fields: "name"}.String()
+    data, err := c.do(ctx, OpList, q)
+    if err != nil { return nil, err }
+    var outer struct { Get struct { Card []struct {
+        Name string `json:"name"`
+    } `json:"Card"` } `json:"Get"` }
+    if err := json.Unmarshal(data, &outer); err != nil { return nil, err }
+    seen := map[string]bool{}
+    out := []string{}
+    for _, c0 := range outer.Get.Card {
+        key := strings.ToLower(c0.Name)
+        if c0.Name == "" || seen[key] || !literalMatch(prefix, c0.Name) { continue }
+        seen[key] = true
+        out = append(out, c0.Name)
     }
+    lp := strings.ToLower(prefix)
+    sort.SliceStable(out, func(i, j int) bool {
+        pi, pj := strings.HasPrefix(strings.ToLower(out[i]), lp), strings.HasPrefix(strings.ToLower(out[j]), lp)
+        if pi != pj { return pi }
+        return out[i] < out[j]
+    })
+    if len(out) > limit { out = out[:limit] }
     return out, nil
 }
 
+// detailFields is the full selection for the card detail view. card_faces, prices, oracle_id
+// and image_large are optional: run it through doOptional(ctx, q, detailOptional...).
+const detailFields = `scryfall_id name type_line mana_cost cmc oracle_text power toughness colors color_identity keywords edhrec_rank set collector_number rarity layout legalities image_small image_normal image_large card_faces prices oracle_id _additional{ id }`
+
+var detailOptional = []string{propCardFaces, propPrices, propOracleID, propImageLarge}
+
 // GetCardByScryfallID returns a richly populated card for the detail view.
 func (c *Client) GetCardByScryfallID(ctx context.Context, scryfallID string) (Card, error) {
-    q := fmt.Sprintf(`{ Get { Card(where:{path:["scryfall_id"], operator: Equal, valueString:%q}, limit:1){
-      scryfall_id name type_line mana_cost cmc oracle_text power toughness colors color_identity keywords edhrec_rank set collector_number rarity layout legalities image_normal
-      _additional{ id }
-    } } }`, scryfallID)
-    data, err := c.do(ctx, q)
+    data, err := c.doOptional(ctx, OpList, cardByScryfallIDQuery(scryfallID), detailOptional...)
+    if err != nil { return Card{}, err }
+    cards, err := decodeCardDetails(data)
     if err != nil { return Card{}, err }
+    if len(cards) == 0 { return Card{}, notFound(scryfallID) }
+    return cards[0], nil
+}
+
+// GetCardBySetAndCollector returns the printing with this set code and collector number (e.g.
+// "neo", "100"), with the same fields as GetCardByScryfallID. This is how most MTG tools
+// reference a specific printing. The set is matched case-insensitively, the number exactly:
+// collector_number is word-tokenized, so Equal "100" also matches "100★" and "100†", and the
+// exact printing is picked from the first few hits.
+func (c *Client) GetCardBySetAndCollector(ctx context.Context, set, collector string) (Card, error) {
+    set, collector = strings.ToLower(strings.TrimSpace(set)), strings.TrimSpace(collector)
+    if set == "" || collector == "" { return Card{}, notFound(set + " #" + collector) }
+    where := And(TextFilter("Equal", "set", set), TextFilter("Equal", "collector_number", collector))
+    q := fmt.Sprintf(`{ Get { Card(where:%s, limit:%d){ %s } } }`, where, collectorVariants, detailFields)
+    data, err := c.doOptional(ctx, OpList, q, detailOptional...)
+    if err != nil { return Card{}, err }
+    cards, err := decodeCardDetails(data)
+    if err != nil { return Card{}, err }
+    for _, card := range cards {
+        if card.CollectorNum == collector { return card, nil }
+    }
+    return Card{}, notFound(set + " #" + collector)
+}
+
+// collectorVariants bounds how many printings sharing a collector number token are fetched.
+const collectorVariants = 5
+
+// GetCardsByScryfallIDs fetches several cards in one query, with the same fields as
+// GetCardByScryfallID, keyed by Scryfall ID. IDs that match no card are left out of the map.
+// scryfall_id is word-tokenized, so ContainsAny would also hit UUIDs sharing a segment; it asks
+// for an Or of Equal filters instead and keeps only cards whose ID is one of ids.
+func (c *Client) GetCardsByScryfallIDs(ctx context.Context, ids []string) (map[string]Card, error) {
+    seen := map[string]bool{}
+    var want []string
+    for _, id := range ids {
+        if id = strings.TrimSpace(id); id != "" && !seen[id] {
+            seen[id] = true
+            want = append(want, id)
+        }
+    }
+    out := make(map[string]Card, len(want))
+    if len(want) == 0 { return out, nil }
+    eq := make([]*WhereFilter, 0, len(want))
+    for _, id := range want { eq = append(eq, TextFilter("Equal", "scryfall_id", id)) }
+    // Room for an ID ingested twice without pushing another wanted card past the limit.
+    q := fmt.Sprintf(`{ Get { Card(where:%s, limit:%d){ %s } } }`, Or(eq...), 2*len(want), detailFields)
+    data, err := c.doOptional(ctx, OpList, q, detailOptional...)
+    if err != nil { return nil, err }
+    cards, err := decodeCardDetails(data)
+    if err != nil { return nil, err }
+    for _, card := range cards {
+        if _, dup := out[card.ScryfallID]; !dup && seen[card.ScryfallID] { out[card.ScryfallID] = card }
+    }
+    return out, nil
+}
+
+// decodeCardDetails maps a `Get { Card [...] }` payload selected with detailFields into Cards.
+func decodeCardDetails(data json.RawMessage) ([]Card, error) {
     var o struct { Get struct { Card []struct {
         Scry   string   `json:"scryfall_id"`
         Name   string   `json:"name"`
@@ -258,36 +668,95 @@ func (c *Client) GetCardByScryfallID(ctx context.Context, scryfallID string) (Ca
         Colors []string `json:"colors"`
         ColorI []string `json:"color_identity"`
         Keys   []string `json:"keywords"`
+        EDHREC int      `json:"edhrec_rank"`
         Set    string   `json:"set"`
         Coll   string   `json:"collector_number"`
         Rarity string   `json:"rarity"`
         Layout string   `json:"layout"`
         Legal  string   `json:"legalities"`
+        ImgS   string   `json:"image_small"`
         Img    string   `json:"image_normal"`
+        ImgL   string   `json:"image_large"`
+        Faces  string   `json:"card_faces"`
+        Prices string   `json:"prices"`
+        OID    string   `json:"oracle_id"`
         Add    struct { ID string `json:"id"` } `json:"_additional"`
     } `json:"Card"` } `json:"Get"` }
-    if err := json.Unmarshal(data, &o); err != nil { return Card{}, err }
-    if len(o.Get.Card) == 0 { return Card{}, fmt.Errorf("card not found: %s", scryfallID) }
-    c0 := o.Get.Card[0]
-    leg := map[string]string{}
-    if c0.Legal != "" {
-        _ = json.Unmarshal([]byte(c0.Legal), &leg)
+    if err := json.Unmarshal(data, &o); err != nil { return nil, err }
+    out := make([]Card, 0, len(o.Get.Card))
+    for _, c0 := range o.Get.Card {
+        leg := map[string]string{}
+        if c0.Legal != "" {
+            _ = json.Unmarshal([]byte(c0.Legal), &leg)
+        }
+        var faces []CardFace
+        if c0.Faces != "" {
+            _ = json.Unmarshal([]byte(c0.Faces), &faces)
+        }
+        if len(faces) < 2 { faces = nil }
+        usd, eur, tix := parsePrices(c0.Prices)
+        out = append(out, Card{
+            ID: c0.Add.ID, ScryfallID: c0.Scry, OracleID: c0.OID, Name: c0.Name, TypeLine: c0.Type, ManaCost: c0.Mana, CMC: c0.CMC,
+            OracleText: c0.Oracle, Power: c0.Power, Toughness: c0.Tough, Colors: c0.Colors, ColorID: c0.ColorI,
+            Keywords: c0.Keys, EDHRECRank: c0.EDHREC, Set: c0.Set, CollectorNum: c0.Coll, Rarity: c0.Rarity, Layout: c0.Layout,
+            ImageSmall: c0.ImgS, ImageNormal: c0.Img, ImageLarge: c0.ImgL, Legalities: leg, Faces: faces, PriceUSD: usd, PriceEUR: eur, PriceTix: tix,
+        })
     }
-    return Card{
-        ID: c0.Add.ID, ScryfallID: c0.Scry, Name: c0.Name, TypeLine: c0.Type, ManaCost: c0.Mana, CMC: c0.CMC,
-        OracleText: c0.Oracle, Power: c0.Power, Toughness: c0.Tough, Colors: c0.Colors, ColorID: c0.ColorI,
-        Keywords: c0.Keys, Set: c0.Set, CollectorNum: c0.Coll, Rarity: c0.Rarity, Layout: c0.Layout,
-        ImageNormal: c0.Img, Legalities: leg,
-    }, nil
+    return out, nil
 }
 
-// ListPrintingsByName returns different printings (same name) with set/collector info.
+// printFields is the selection for printing lists; oracle_id is optional (see doOptional).
+const printFields = `scryfall_id name oracle_id set collector_number rarity image_normal _additional{ id }`
+
+// ListPrintingsByName returns up to limit printings of the card with this exact name
+// (case-insensitive, see nameMatches). Equal on name matches words, so the query pages
+// through every card containing them and keeps the exact names. When the printings carry
+// oracle IDs, only the most common one is kept, so unrelated cards that happen to share a
+// name aren't listed as printings; printings without one are kept.
 func (c *Client) ListPrintingsByName(ctx context.Context, name string, limit int) ([]Card, error) {
-    q := fmt.Sprintf(`{ Get { Card(where:{path:["name"], operator: Equal, valueString:%q}, limit:%d){ scryfall_id set collector_number rarity image_normal _additional{ id } } } }`, name, limit)
-    data, err := c.do(ctx, q)
+    if limit <= 0 { return []Card{}, nil }
+    prints := []Card{}
+    for offset := 0; len(prints) < limit; offset += limit {
+        q := queryBuilder{consistency: c.consistencyFor(ctx), where: TextFilter("Equal", "name", name), limit: limit, offset: offset, fields: printFields}.String()
+        data, err := c.doOptional(ctx, OpList, q, propOracleID)
+        if err != nil { return nil, err }
+        page, err := decodePrintings(data)
+        if err != nil { return nil, err }
+        for _, p := range page {
+            if len(prints) < limit && nameMatches(p.Name, name) { prints = append(prints, p) }
+        }
+        if len(page) < limit { break }
+    }
+    counts := map[string]int{}
+    best := ""
+    for _, p := range prints {
+        if p.OracleID == "" { continue }
+        counts[p.OracleID]++
+        if counts[p.OracleID] > counts[best] { best = p.OracleID }
+    }
+    if best == "" { return prints, nil }
+    out := prints[:0]
+    for _, p := range prints {
+        if p.OracleID == "" || p.OracleID == best { out = append(out, p) }
+    }
+    return out, nil
+}
+
+// ListPrintingsByOracleID returns the printings sharing an oracle ID. Unlike name matching this
+// covers split, adventure and double-faced cards whatever name each printing was ingested under.
+func (c *Client) ListPrintingsByOracleID(ctx context.Context, oracleID string, limit int) ([]Card, error) {
+    q := fmt.Sprintf(`{ Get { Card(where:{path:["oracle_id"], operator: Equal, valueString:%s}, limit:%d){ %s } } }`, gqlString(oracleID), limit, printFields)
+    data, err := c.do(ctx, OpList, q)
     if err != nil { return nil, err }
+    return decodePrintings(data)
+}
+
+// decodePrintings maps a `Get { Card [...] }` payload selected with printFields into Cards.
+func decodePrintings(data json.RawMessage) ([]Card, error) {
     var outer struct { Get struct { Card []struct {
         Scry string `json:"scryfall_id"`
+        Name string `json:"name"`
+        OID  string `json:"oracle_id"`
         Set  string `json:"set"`
         Coll string `json:"collector_number"`
         Rar  string `json:"rarity"`
@@ -297,7 +766,128 @@ func (c *Client) ListPrintingsByName(ctx context.Context, name string, limit int
     if err := json.Unmarshal(data, &outer); err != nil { return nil, err }
     out := make([]Card, 0, len(outer.Get.Card))
     for _, c0 := range outer.Get.Card {
-        out = append(out, Card{ID: c0.Add.ID, ScryfallID: c0.Scry, Set: c0.Set, CollectorNum: c0.Coll, Rarity: c0.Rar, ImageNormal: c0.Img})
+        out = append(out, Card{ID: c0.Add.ID, ScryfallID: c0.Scry, Name: c0.Name, OracleID: c0.OID, Set: c0.Set, CollectorNum: c0.Coll, Rarity: c0.Rar, ImageNormal: c0.Img})
     }
     return out, nil
 }
+
+// listFields is the selection shared by the list-style card queries.
+// prices and image_large are optional: run these queries through doOptional(ctx, q, listOptional...).
+const listFields = `scryfall_id name type_line mana_cost cmc colors set rarity oracle_text image_small image_normal image_large prices _additional{ id }`
+
+var listOptional = []string{propPrices, propImageLarge}
+
+// browseFields is listFields without prices, for the plain ListCards and FindByNameLike queries.
+const browseFields = `scryfall_id name type_line mana_cost cmc colors set rarity oracle_text image_small image_normal image_large _additional{ id }`
+
+// decodeCardList maps a `Get { Card [...] }` payload selected with listFields into Cards.
+func decodeCardList(data json.RawMessage) ([]Card, error) {
+    var outer struct { Get struct { Card []struct {
+        Scry string `json:"scryfall_id"`
+        Name string `json:"name"`
+        Type string `json:"type_line"`
+        Mana string `json:"mana_cost"`
+        CMC  float64 `json:"cmc"`
+        Colors []string `json:"colors"`
+        Set   string `json:"set"`
+        Rarity string `json:"rarity"`
+        Oracle string `json:"oracle_text"`
+        ImgS string `json:"image_small"`
+        Img string `json:"image_normal"`
+        ImgL string `json:"image_large"`
+        Prices string `json:"prices"`
+        EDHREC int    `json:"edhrec_rank"` // only when selected, as by Staples
+        Add struct { ID string `json:"id"` } `json:"_additional"`
+    } `json:"Card"` } `json:"Get"` }
+    if err := json.Unmarshal(data, &outer); err != nil { return nil, err }
+    out := make([]Card, 0, len(outer.Get.Card))
+    for _, c0 := range outer.Get.Card {
+        usd, eur, tix := parsePrices(c0.Prices)
+        out = append(out, Card{ID: c0.Add.ID, ScryfallID: c0.Scry, Name: c0.Name, TypeLine: c0.Type, ManaCost: c0.Mana, CMC: c0.CMC, Colors: c0.Colors, Set: c0.Set, Rarity: c0.Rarity, OracleText: c0.Oracle, ImageSmall: c0.ImgS, ImageNormal: c0.Img, ImageLarge: c0.ImgL, PriceUSD: usd, PriceEUR: eur, PriceTix: tix, EDHRECRank: c0.EDHREC})
+    }
+    return out, nil
+}
+
+// FindByKeyword returns cards whose keywords array contains the given keyword (e.g. "Flashback").
+func (c *Client) FindByKeyword(ctx context.Context, keyword string, limit int) ([]Card, error) {
+    return c.FindByKeywords(ctx, []string{keyword}, false, limit)
+}
+
+// FindByKeywords returns cards matching any (or, with matchAll, all) of the given keywords.
+// Matching uses ContainsAny/ContainsAll on the `keywords` text[] property, independent of vectors.
+func (c *Client) FindByKeywords(ctx context.Context, keywords []string, matchAll bool, limit int) ([]Card, error) {
+    vals := make([]string, 0, len(keywords))
+    for _, k := range keywords {
+        if k = strings.TrimSpace(k); k != "" { vals = append(vals, k) }
+    }
+    if len(vals) == 0 { return nil, errors.New("keyword required") }
+    op := "ContainsAny"
+    if matchAll { op = "ContainsAll" }
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: TextsFilter(op, "keywords", vals), sort: sortOf(ctx), limit: limit, fields: listFields}.String()
+    data, err := c.doOptional(ctx, OpList, q, listOptional...)
+    if err != nil { return nil, err }
+    return decodeCardList(data)
+}
+
+// GetCardsByNames resolves exact card names, returning a map keyed by lower-cased name. Names
+// that don't resolve are omitted. name is word-tokenized, so Equal "Forest" also matches
+// "Snow-Covered Forest": each name gets its own paged lookup that keeps only cards named
+// exactly that (ignoring case), or whose front face is, for double-faced cards.
+// Selected fields cover the analysis endpoints: colors, color identity, type line, cmc, keywords.
+func (c *Client) GetCardsByNames(ctx context.Context, names []string) (map[string]Card, error) {
+    out := map[string]Card{}
+    seen := map[string]bool{}
+    for _, n := range names {
+        n = strings.TrimSpace(n)
+        key := strings.ToLower(n)
+        if n == "" || seen[key] { continue }
+        seen[key] = true
+        card, ok, err := c.cardByExactName(ctx, n)
+        if err != nil { return nil, err }
+        if ok { out[key] = card }
+    }
+    return out, nil
+}
+
+// namePage is the page size of cardByExactName's lookups.
+const namePage = 20
+
+// nameMatches reports whether a card called full is the card the user named: the whole name
+// or, for double-faced cards, the front face, ignoring case.
+func nameMatches(full, name string) bool {
+    front, _, _ := strings.Cut(full, " // ")
+    return strings.EqualFold(full, name) || strings.EqualFold(front, name)
+}
+
+// cardByExactName pages through the cards whose name contains every word of name until one
+// is named exactly that (see nameMatches).
+func (c *Client) cardByExactName(ctx context.Context, name string) (Card, bool, error) {
+    for offset := 0; ; offset += namePage {
+        q := queryBuilder{consistency: c.consistencyFor(ctx), where: TextFilter("Equal", "name", name), limit: namePage, offset: offset,
+            fields: "scryfall_id name type_line mana_cost cmc colors color_identity keywords set collector_number rarity image_normal _additional{ id }"}.String()
+        data, err := c.do(ctx, OpList, q)
+        if err != nil { return Card{}, false, err }
+        var o struct { Get struct { Card []struct {
+            Scry   string   `json:"scryfall_id"`
+            Name   string   `json:"name"`
+            Type   string   `json:"type_line"`
+            Mana   string   `json:"mana_cost"`
+            CMC    float64  `json:"cmc"`
+            Colors []string `json:"colors"`
+            ColorI []string `json:"color_identity"`
+            Keys   []string `json:"keywords"`
+            Set    string   `json:"set"`
+            Coll   string   `json:"collector_number"`
+            Rarity string   `json:"rarity"`
+            Img    string   `json:"image_normal"`
+            Add    struct { ID string `json:"id"` } `json:"_additional"`
+        } `json:"Card"` } `json:"Get"` }
+        if err := json.Unmarshal(data, &o); err != nil { return Card{}, false, err }
+        for _, c0 := range o.Get.Card {
+            if !nameMatches(c0.Name, name) { continue }
+            return Card{ID: c0.Add.ID, ScryfallID: c0.Scry, Name: c0.Name, TypeLine: c0.Type, ManaCost: c0.Mana, CMC: c0.CMC,
+                Colors: c0.Colors, ColorID: c0.ColorI, Keywords: c0.Keys, Set: c0.Set, CollectorNum: c0.Coll, Rarity: c0.Rarity, ImageNormal: c0.Img}, true, nil
+        }
+        if len(o.Get.Card) < namePage { return Card{}, false, nil }
+    }
+}
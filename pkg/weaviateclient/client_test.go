@@ -0,0 +1,307 @@
+package weaviateclient
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+    "unicode/utf8"
+)
+
+// fakeRoute answers the GraphQL queries containing match with response. A match starting with
+// "/" answers requests to that REST path instead (e.g. "/v1/schema/Card"); an empty match
+// answers any query.
+type fakeRoute struct {
+    match    string
+    response string
+}
+
+// fakeWeaviate answers each request with the first route that matches it and fails the test on
+// a request no route expects. It records the GraphQL queries in arrival order.
+func fakeWeaviate(t testing.TB, routes ...fakeRoute) (*Client, func() []string) {
+    t.Helper()
+    var mu sync.Mutex
+    var queries []string
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        if r.URL.Path != "/v1/graphql" {
+            for _, rt := range routes {
+                if rt.match == r.URL.Path {
+                    _, _ = w.Write([]byte(rt.response))
+                    return
+                }
+            }
+            t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+            http.NotFound(w, r)
+            return
+        }
+        var body struct{ Query string `json:"query"` }
+        if err := json.NewDecoder(r.Body).Decode(&body); err != nil { t.Errorf("decode request: %v", err) }
+        mu.Lock()
+        queries = append(queries, body.Query)
+        mu.Unlock()
+        for _, rt := range routes {
+            if !strings.HasPrefix(rt.match, "/") && strings.Contains(body.Query, rt.match) {
+                _, _ = w.Write([]byte(rt.response))
+                return
+            }
+        }
+        t.Errorf("unexpected query: %s", body.Query)
+        _, _ = w.Write([]byte(`{"errors":[{"message":"unexpected query"}]}`))
+    }))
+    t.Cleanup(srv.Close)
+    return NewClient(srv.URL), func() []string {
+        mu.Lock()
+        defer mu.Unlock()
+        return append([]string(nil), queries...)
+    }
+}
+
+const emptyGet = `{"data":{"Get":{"Card":[]}}}`
+
+func TestListCardsPage(t *testing.T) {
+    const page = `{"data":{"Get":{"Card":[{"scryfall_id":"a","name":"Shock","rarity":"common","_additional":{"id":"o1"}}]}}}`
+    const count = `{"data":{"Aggregate":{"Card":[{"meta":{"count":42}}]}}}`
+    cli, queries := fakeWeaviate(t, fakeRoute{"Aggregate", count}, fakeRoute{"Get", page})
+    ctx := context.Background()
+    cards, total, err := cli.ListCardsPage(ctx, 20, 1)
+    if err != nil { t.Fatalf("ListCardsPage: %v", err) }
+    want, err := cli.CountCards(ctx)
+    if err != nil { t.Fatalf("CountCards: %v", err) }
+    if total != want || len(cards) != 1 || cards[0].Name != "Shock" { t.Errorf("ListCardsPage = %v, %d; want [Shock], %d", cards, total, want) }
+
+    // The filtered count uses the listing's where filter.
+    if _, _, err := cli.ListCardsFilteredPage(ctx, 0, 1, "M21", "common"); err != nil { t.Fatalf("ListCardsFilteredPage: %v", err) }
+    qs := queries()
+    where := browseFilter("m21", "common").String()
+    if last := qs[len(qs)-1]; !strings.Contains(last, "Aggregate") || !strings.Contains(last, where) { t.Errorf("count query = %s, want the where filter %s", last, where) }
+}
+
+func TestAggregateWhere(t *testing.T) {
+    // Weaviate answers a numeric aggregate over no cards with nulls, or with no group at all.
+    cli, queries := fakeWeaviate(t,
+        fakeRoute{`groupBy:["set"]`, `{"data":{"Aggregate":{"Card":[]}}}`},
+        fakeRoute{"groupBy", `{"data":{"Aggregate":{"Card":[{"groupedBy":{"value":"common"},"meta":{"count":3}}]}}}`},
+        fakeRoute{"mh3", `{"data":{"Aggregate":{"Card":[{"cmc":{"mean":null,"minimum":null,"maximum":null}}]}}}`},
+        fakeRoute{"", `{"data":{"Aggregate":{"Card":[{"cmc":{"mean":2.5,"minimum":0,"maximum":16}}]}}}`},
+    )
+    ctx := context.Background()
+    where := TextFilter("Equal", "set", "mh3")
+    mean, lo, hi, err := cli.AggregateCMC(ctx, where)
+    if err != nil || mean != 0 || lo != 0 || hi != 0 { t.Errorf("AggregateCMC over no cards = %v, %v, %v, %v; want zeros", mean, lo, hi, err) }
+    mean, lo, hi, err = cli.AggregateCMC(ctx, nil)
+    if err != nil || mean != 2.5 || lo != 0 || hi != 16 { t.Errorf("AggregateCMC = %v, %v, %v, %v", mean, lo, hi, err) }
+    groups, err := cli.AggregateByFieldWhere(ctx, "rarity", where)
+    if err != nil || !reflect.DeepEqual(groups, []GroupCount{{"common", 3}}) { t.Errorf("AggregateByFieldWhere = %v, %v", groups, err) }
+    groups, err = cli.AggregateByFieldWhere(ctx, "set", where)
+    if err != nil || len(groups) != 0 { t.Errorf("AggregateByFieldWhere over no cards = %v, %v; want empty", groups, err) }
+
+    qs := queries()
+    if len(qs) != 4 { t.Fatalf("got %d queries, want 4", len(qs)) }
+    if !strings.Contains(qs[0], "Card(where:"+where.String()+")") || strings.Contains(qs[1], "where") { t.Errorf("cmc queries = %q", qs[:2]) }
+    if want := `Card(groupBy:["rarity"], where:` + where.String() + `)`; !strings.Contains(qs[2], want) { t.Errorf("groupBy query = %s, want %s", qs[2], want) }
+}
+
+func TestWithLogger(t *testing.T) {
+    var buf bytes.Buffer
+    cli, _ := fakeWeaviate(t, fakeRoute{"", `{"errors":[{"message":"boom"}]}`})
+    if _, err := cli.ListCards(context.Background(), 0, 1); err == nil { t.Fatal("ListCards succeeded, want the GraphQL error") }
+    WithLogger(slog.New(slog.NewTextHandler(&buf, nil)))(cli)
+    if _, err := cli.ListCards(context.Background(), 0, 1); err == nil { t.Fatal("ListCards succeeded, want the GraphQL error") }
+    if out := buf.String(); strings.Count(out, "weaviate graphql error") != 1 || !strings.Contains(out, "err=boom") { t.Errorf("log = %q, want one record for the second call only", out) }
+}
+
+func TestListCardsFilteredWhere(t *testing.T) {
+    tests := []struct {
+        name        string
+        set, rarity string
+        want        []string
+        notWant     []string
+    }{
+        {
+            name: "set and rarity", set: "MH3", rarity: "mythic",
+            want: []string{`where:{operator: And, operands:[`, `{path:["set"], operator: Equal, valueText:"mh3"}`, `{path:["rarity"], operator: Equal, valueText:"mythic"}`, `limit:20`, `offset:40`},
+        },
+        {
+            name: "set only", set: "neo",
+            want:    []string{`where:{path:["set"], operator: Equal, valueText:"neo"}`},
+            notWant: []string{`And`, `path:["rarity"]`},
+        },
+        {
+            name:    "no filters",
+            want:    []string{`Card(limit:20, offset:40)`},
+            notWant: []string{`where`},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            cli, queries := fakeWeaviate(t, fakeRoute{"", emptyGet})
+            if _, err := cli.ListCardsFiltered(context.Background(), 40, 20, tt.set, tt.rarity); err != nil {
+                t.Fatalf("ListCardsFiltered: %v", err)
+            }
+            qs := queries()
+            if len(qs) != 1 {
+                t.Fatalf("got %d queries, want 1", len(qs))
+            }
+            q := qs[0]
+            for _, w := range tt.want {
+                if !strings.Contains(q, w) { t.Errorf("query missing %q:\n%s", w, q) }
+            }
+            for _, w := range tt.notWant {
+                if strings.Contains(q, w) { t.Errorf("query unexpectedly contains %q:\n%s", w, q) }
+            }
+        })
+    }
+}
+
+func TestSearchNearVectorClampsSimilarity(t *testing.T) {
+    resp := `{"data":{"Get":{"Card":[
+        {"name":"Far","_additional":{"id":"a","distance":1.3}},
+        {"name":"Near","_additional":{"id":"b","distance":0.25}}
+    ]}}}`
+    cli, _ := fakeWeaviate(t, fakeRoute{"", resp})
+    cards, err := cli.SearchNearVector(context.Background(), []float64{1, 0}, 2)
+    if err != nil {
+        t.Fatalf("SearchNearVector: %v", err)
+    }
+    if len(cards) != 2 {
+        t.Fatalf("got %d cards, want 2", len(cards))
+    }
+    if cards[0].Similarity != 0 {
+        t.Errorf("distance 1.3: similarity = %v, want 0", cards[0].Similarity)
+    }
+    if cards[1].Similarity != 0.75 {
+        t.Errorf("distance 0.25: similarity = %v, want 0.75", cards[1].Similarity)
+    }
+}
+
+func TestSimilarityFromDistance(t *testing.T) {
+    for _, tt := range []struct{ d, want float64 }{
+        {0, 1}, {0.4, 0.6}, {1, 0}, {1.3, 0}, {2.5, 0}, {-0.2, 1},
+    } {
+        if got := SimilarityFromDistance(tt.d); got != tt.want {
+            t.Errorf("SimilarityFromDistance(%v) = %v, want %v", tt.d, got, tt.want)
+        }
+    }
+}
+
+// FuzzLikeQueries feeds adversarial names/search terms through the LIKE-based lookups and
+// checks that every query is well-formed GraphQL and that the Like pattern is the input
+// between wildcards, however the input is quoted.
+func FuzzLikeQueries(f *testing.F) {
+    for _, s := range []string{"bolt", "*", "?", "what?", `\`, `"`, `\"`, `*"}) { x`, "a\x00b", "\xff\xfe", "tab\there", "Lim-Dûl's", `\u0022`, "\u2028", "😀"} {
+        f.Add(s)
+    }
+    // Whatever is what a wildcard in the input matches; only literal hits may come back.
+    cards := `{"data":{"Get":{"Card":[{"name":"What?","_additional":{"id":"w1"}},{"name":"Whatever","oracle_text":"Draw a card.","_additional":{"id":"w2"}},{"name":"Shoal","oracle_text":"Costs *.","_additional":{"id":"w3"}}]}}}`
+    cli, queries := fakeWeaviate(f, fakeRoute{"", cards})
+    f.Fuzz(func(t *testing.T, s string) {
+        sent := len(queries())
+        byName, err := cli.FindByNameLike(context.Background(), s, 5)
+        if err != nil { t.Fatalf("FindByNameLike: %v", err) }
+        found, err := cli.SearchCards(context.Background(), s, 5)
+        if err != nil { t.Fatalf("SearchCards: %v", err) }
+        if hasWildcard(s) {
+            lit := strings.ToLower(strings.TrimSpace(s))
+            for _, c := range byName {
+                if !strings.Contains(strings.ToLower(c.Name), strings.ToLower(s)) { t.Errorf("FindByNameLike(%q) kept %q", s, c.Name) }
+            }
+            for _, c := range found {
+                if !strings.Contains(strings.ToLower(c.Name), lit) && !strings.Contains(strings.ToLower(c.OracleText), lit) { t.Errorf("SearchCards(%q) kept %q", s, c.Name) }
+            }
+        }
+        for _, q := range queries()[sent:] {
+            lits, err := graphQLStrings(q)
+            if err != nil { t.Fatalf("malformed GraphQL for input %q: %v\n%s", s, err, q) }
+            for _, lit := range lits {
+                // the exact-name lookup sends s itself, which may start and end with *
+                if !strings.HasPrefix(lit, "*") || !strings.HasSuffix(lit, "*") || len(lit) < 2 || lit == strings.TrimSpace(s) { continue }
+                got := lit[1 : len(lit)-1]
+                if want := strings.TrimSpace(s); utf8.ValidString(s) && got != want && got != s {
+                    t.Fatalf("pattern %q matches %q, want %q", lit, got, s)
+                }
+            }
+        }
+    })
+}
+
+// graphQLStrings lexes q, checking bracket balance and that every string literal only uses
+// GraphQL escapes and no raw control characters. It returns the decoded literals.
+func graphQLStrings(q string) ([]string, error) {
+    var lits []string
+    var stack []byte
+    pairs := map[byte]byte{'}': '{', ')': '(', ']': '['}
+    for i := 0; i < len(q); i++ {
+        switch ch := q[i]; ch {
+        case '{', '(', '[':
+            stack = append(stack, ch)
+        case '}', ')', ']':
+            if len(stack) == 0 || stack[len(stack)-1] != pairs[ch] { return nil, fmt.Errorf("unbalanced %q at %d", ch, i) }
+            stack = stack[:len(stack)-1]
+        case '"':
+            j := i + 1
+            for ; j < len(q) && q[j] != '"'; j++ {
+                switch {
+                case q[j] < 0x20:
+                    return nil, fmt.Errorf("raw control character in string at %d", j)
+                case q[j] == '\\':
+                    j++
+                    if j >= len(q) { return nil, fmt.Errorf("unterminated escape") }
+                    switch q[j] {
+                    case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+                    case 'u':
+                        if j+4 >= len(q) { return nil, fmt.Errorf("short \\u escape") }
+                        for _, h := range q[j+1 : j+5] {
+                            if !strings.ContainsRune("0123456789abcdefABCDEF", h) { return nil, fmt.Errorf("bad \\u escape at %d", j) }
+                        }
+                        j += 4
+                    default:
+                        return nil, fmt.Errorf("invalid escape \\%c at %d", q[j], j)
+                    }
+                }
+            }
+            if j >= len(q) { return nil, fmt.Errorf("unterminated string at %d", i) }
+            if !utf8.ValidString(q[i : j+1]) { return nil, fmt.Errorf("invalid UTF-8 in string at %d", i) }
+            var lit string
+            if err := json.Unmarshal([]byte(q[i:j+1]), &lit); err != nil { return nil, err }
+            lits = append(lits, lit)
+            i = j
+        }
+    }
+    if len(stack) != 0 { return nil, fmt.Errorf("unclosed %q", stack) }
+    return lits, nil
+}
+
+func TestTimeoutPerOperation(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        time.Sleep(100 * time.Millisecond)
+        w.Header().Set("Content-Type", "application/json")
+        _, _ = w.Write([]byte(emptyGet))
+    }))
+    t.Cleanup(srv.Close)
+    cli := NewClient(srv.URL, WithTimeout(OpList, 20*time.Millisecond), WithTimeout(OpSearch, time.Second))
+
+    _, err := cli.ListCards(context.Background(), 0, 1)
+    if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "list request timed out after 20ms") {
+        t.Fatalf("ListCards err = %v, want the list timeout", err)
+    }
+    if _, err := cli.SearchNearVector(context.Background(), []float64{1}, 1); err != nil { t.Fatalf("SearchNearVector: %v", err) }
+
+    // A sooner context deadline wins and is reported as the caller's own.
+    ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+    defer cancel()
+    _, err = cli.SearchNearVector(ctx, []float64{1}, 1)
+    if !errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timed out after") {
+        t.Fatalf("SearchNearVector err = %v, want the context's deadline", err)
+    }
+    if d := NewClient(srv.URL).Timeout(OpDelete); d != 5*time.Minute { t.Errorf("default delete timeout = %s, want 5m", d) }
+}
@@ -0,0 +1,50 @@
+package weaviateclient
+
+import (
+    "context"
+    "log/slog"
+    "strings"
+)
+
+// Consistency levels understood by replicated Weaviate classes.
+const (
+    ConsistencyOne    = "ONE"
+    ConsistencyQuorum = "QUORUM"
+    ConsistencyAll    = "ALL"
+)
+
+// Option configures a Client in NewClient.
+type Option func(*Client)
+
+// WithConsistencyLevel sets the default consistency level for Get queries (ONE, QUORUM, ALL).
+// Unset, queries carry no level and Weaviate uses its own default (the previous behavior).
+// Unknown values are ignored with a warning.
+func WithConsistencyLevel(level string) Option {
+    return func(c *Client) { c.consistency = normalizeConsistency(level) }
+}
+
+type consistencyKey struct{}
+
+// WithConsistency overrides the client's consistency level for calls made with the returned
+// context, e.g. ConsistencyAll for a read-after-write check right after ingestion.
+func WithConsistency(ctx context.Context, level string) context.Context {
+    return context.WithValue(ctx, consistencyKey{}, normalizeConsistency(level))
+}
+
+func normalizeConsistency(level string) string {
+    level = strings.ToUpper(strings.TrimSpace(level))
+    switch level {
+    case "", ConsistencyOne, ConsistencyQuorum, ConsistencyAll:
+        return level
+    }
+    slog.Warn("ignoring unknown weaviate consistency level", "level", level)
+    return ""
+}
+
+// consistencyFor is the level for a Get query made with ctx: the WithConsistency override,
+// else the client's default. Queries carry it in queryBuilder.consistency; Aggregate queries
+// take no level.
+func (c *Client) consistencyFor(ctx context.Context) string {
+    if v, ok := ctx.Value(consistencyKey{}).(string); ok && v != "" { return v }
+    return c.consistency
+}
@@ -0,0 +1,56 @@
+package weaviateclient
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+)
+
+// DeleteAllCards removes every Card object in chunks of Weaviate's batch delete limit, keeping
+// the class and its schema. progress, when non-nil, is called after each chunk with the total
+// deleted so far. A canceled ctx stops it between (or during) chunks.
+func (c *Client) DeleteAllCards(ctx context.Context, progress func(deleted int)) error {
+    // Every object has an id, so Like "*" matches them all.
+    body, _ := json.Marshal(map[string]any{
+        "match":  map[string]any{"class": "Card", "where": map[string]any{"path": []string{"id"}, "operator": "Like", "valueText": "*"}},
+        "output": "minimal",
+    })
+    deleted := 0
+    for {
+        if err := ctx.Err(); err != nil { return err }
+        matches, ok, err := c.batchDelete(ctx, body)
+        if err != nil { return err }
+        deleted += ok
+        if progress != nil && ok > 0 { progress(deleted) }
+        if matches == 0 { return nil }
+        if ok < matches { return fmt.Errorf("batch delete: %d of %d objects failed (%d deleted)", matches-ok, matches, deleted) }
+    }
+}
+
+// batchDelete runs one DELETE /v1/batch/objects call and returns how many objects matched and
+// how many of those were deleted.
+func (c *Client) batchDelete(ctx context.Context, body []byte) (int, int, error) {
+    if c.limiter != nil {
+        if err := c.limiter.wait(ctx); err != nil { return 0, 0, err }
+    }
+    ctx, cancel := c.withTimeout(ctx, OpDelete)
+    defer cancel()
+    req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/v1/batch/objects", bytes.NewReader(body))
+    if err != nil { return 0, 0, err }
+    req.Header.Set("Content-Type", "application/json")
+    resp, err := c.http.Do(req)
+    if err != nil { return 0, 0, err }
+    defer resp.Body.Close()
+    data, _ := io.ReadAll(resp.Body)
+    if resp.StatusCode/100 != 2 { return 0, 0, fmt.Errorf("batch delete status %d: %s", resp.StatusCode, strings.TrimSpace(string(data))) }
+    var o struct { Results struct {
+        Matches    int `json:"matches"`
+        Successful int `json:"successful"`
+    } `json:"results"` }
+    if err := json.Unmarshal(data, &o); err != nil { return 0, 0, fmt.Errorf("batch delete: %w", err) }
+    return o.Results.Matches, o.Results.Successful, nil
+}
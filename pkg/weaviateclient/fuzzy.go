@@ -0,0 +1,83 @@
+package weaviateclient
+
+import (
+    "context"
+    "sort"
+    "strings"
+)
+
+// fuzzyCandidates caps the broadened candidate set that gets re-ranked client-side.
+const fuzzyCandidates = 300
+
+// SearchNameFuzzy is a typo-tolerant name search. It broadens the query into a
+// set of trigram-prefix LIKE candidates (e.g. "lightnig bolt" → *lig* OR *bol*),
+// then re-ranks them by Levenshtein distance to the query, dropping poor matches.
+// Use FindByNameLike first; this is the slower fallback.
+func (c *Client) SearchNameFuzzy(ctx context.Context, name string, limit int) ([]Card, error) {
+    query := strings.ToLower(strings.TrimSpace(name))
+    if query == "" { return nil, nil }
+    var operands []*WhereFilter
+    for _, tok := range strings.Fields(query) {
+        r := []rune(tok)
+        if len(r) < 3 { continue }
+        operands = append(operands, TextFilter("Like", "name", likeContains(string(r[:3]))))
+    }
+    if len(operands) == 0 {
+        return c.FindByNameLike(ctx, name, limit)
+    }
+    where := Or(operands...)
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: where, limit: fuzzyCandidates, fields: listFields}.String()
+    data, err := c.doOptional(ctx, OpList, q, listOptional...)
+    if err != nil { return nil, err }
+    cands, err := decodeCardList(data)
+    if err != nil { return nil, err }
+
+    type scored struct { card Card; dist int }
+    maxDist := len([]rune(query))/3 + 1
+    best := map[string]scored{} // one entry per name
+    for _, cd := range cands {
+        d := nameDistance(query, strings.ToLower(cd.Name))
+        if d > maxDist { continue }
+        if prev, ok := best[cd.Name]; !ok || d < prev.dist { best[cd.Name] = scored{cd, d} }
+    }
+    ranked := make([]scored, 0, len(best))
+    for _, s := range best { ranked = append(ranked, s) }
+    sort.Slice(ranked, func(i, j int) bool {
+        if ranked[i].dist == ranked[j].dist { return ranked[i].card.Name < ranked[j].card.Name }
+        return ranked[i].dist < ranked[j].dist
+    })
+    if limit > 0 && len(ranked) > limit { ranked = ranked[:limit] }
+    out := make([]Card, 0, len(ranked))
+    for _, s := range ranked { out = append(out, s.card) }
+    return out, nil
+}
+
+// nameDistance compares the query against the whole name and against each
+// equally long run of name words, so "bolt" still matches "Lightning Bolt".
+func nameDistance(query, name string) int {
+    best := levenshtein(query, name)
+    qn := len(strings.Fields(query))
+    words := strings.Fields(name)
+    for i := 0; i+qn <= len(words); i++ {
+        if d := levenshtein(query, strings.Join(words[i:i+qn], " ")); d < best { best = d }
+    }
+    return best
+}
+
+// levenshtein returns the edit distance between a and b (rune-aware).
+func levenshtein(a, b string) int {
+    ra, rb := []rune(a), []rune(b)
+    prev := make([]int, len(rb)+1)
+    cur := make([]int, len(rb)+1)
+    for j := range prev { prev[j] = j }
+    for i := 1; i <= len(ra); i++ {
+        cur[0] = i
+        for j := 1; j <= len(rb); j++ {
+            cost := 1
+            if ra[i-1] == rb[j-1] { cost = 0 }
+            cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+        }
+        prev, cur = cur, prev
+    }
+    return prev[len(rb)]
+}
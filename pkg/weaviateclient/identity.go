@@ -0,0 +1,51 @@
+package weaviateclient
+
+import (
+    "context"
+    "strings"
+)
+
+// IdentityFit reports whether a card may go in a Commander deck led by a commander: the
+// card's color identity must be a subset of the commander's. Outside lists the offending colors.
+type IdentityFit struct {
+    Commander         string   `json:"commander"`
+    Card              string   `json:"card"`
+    CommanderIdentity []string `json:"commander_identity"`
+    CardIdentity      []string `json:"card_identity"`
+    Fits              bool     `json:"fits"`
+    Outside           []string `json:"outside"`
+}
+
+// FitsIdentity fetches both cards' color_identity by exact name and compares them. A name
+// matching no card yields ErrCardNotFound.
+func (c *Client) FitsIdentity(ctx context.Context, commander, card string) (IdentityFit, error) {
+    commander, card = strings.TrimSpace(commander), strings.TrimSpace(card)
+    found, err := c.GetCardsByNames(ctx, []string{commander, card})
+    if err != nil { return IdentityFit{}, err }
+    cmdr, ok := found[strings.ToLower(commander)]
+    if !ok { return IdentityFit{}, notFound(commander) }
+    cand, ok := found[strings.ToLower(card)]
+    if !ok { return IdentityFit{}, notFound(card) }
+    outside := OutsideIdentity(cmdr.ColorID, cand.ColorID)
+    return IdentityFit{
+        Commander:         cmdr.Name,
+        Card:              cand.Name,
+        CommanderIdentity: OutsideIdentity(nil, cmdr.ColorID),
+        CardIdentity:      OutsideIdentity(nil, cand.ColorID),
+        Fits:              len(outside) == 0,
+        Outside:           outside,
+    }, nil
+}
+
+// OutsideIdentity returns the colors of card not in identity, upper-cased in WUBRG order
+// (never nil). With a nil identity it just normalizes card.
+func OutsideIdentity(identity, card []string) []string {
+    in, has := map[string]bool{}, map[string]bool{}
+    for _, c := range identity { in[strings.ToUpper(strings.TrimSpace(c))] = true }
+    for _, c := range card { has[strings.ToUpper(strings.TrimSpace(c))] = true }
+    out := []string{}
+    for _, c := range []string{"W", "U", "B", "R", "G"} {
+        if has[c] && !in[c] { out = append(out, c) }
+    }
+    return out
+}
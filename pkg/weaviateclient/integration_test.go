@@ -0,0 +1,164 @@
+//go:build integration
+
+package weaviateclient
+
+import (
+    "bytes"
+    "cmp"
+    "context"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/testcontainers/testcontainers-go"
+    "github.com/testcontainers/testcontainers-go/wait"
+)
+
+// weaviateImage is the server the integration tests run against; keep it close to the one in
+// ops/docker-compose.weaviate.yml.
+const weaviateImage = "semitechnologies/weaviate:latest"
+
+// fixtureCard is a Card object for the integration tests, with its vector.
+type fixtureCard struct {
+    id     string
+    props  map[string]any
+    vector []float64
+}
+
+var fixtureCards = []fixtureCard{
+    {"00000000-0000-4000-8000-000000000001", map[string]any{"scryfall_id": "00000000-0000-4000-8000-000000000001", "name": "Lightning Bolt", "type_line": "Instant", "mana_cost": "{R}", "cmc": 1, "colors": []string{"R"}, "set": "m21", "rarity": "uncommon", "oracle_text": "Lightning Bolt deals 3 damage to any target."}, []float64{1, 0, 0}},
+    {"00000000-0000-4000-8000-000000000002", map[string]any{"scryfall_id": "00000000-0000-4000-8000-000000000002", "name": "Shock", "type_line": "Instant", "mana_cost": "{R}", "cmc": 1, "colors": []string{"R"}, "set": "m21", "rarity": "common", "oracle_text": "Shock deals 2 damage to any target."}, []float64{0.9, 0.1, 0}},
+    {"00000000-0000-4000-8000-000000000003", map[string]any{"scryfall_id": "00000000-0000-4000-8000-000000000003", "name": "Counterspell", "type_line": "Instant", "mana_cost": "{U}{U}", "cmc": 2, "colors": []string{"U"}, "set": "mh2", "rarity": "uncommon", "oracle_text": "Counter target spell."}, []float64{0, 1, 0}},
+    {"00000000-0000-4000-8000-000000000004", map[string]any{"scryfall_id": "00000000-0000-4000-8000-000000000004", "name": "Llanowar Elves", "type_line": "Creature — Elf Druid", "mana_cost": "{G}", "cmc": 1, "colors": []string{"G"}, "set": "dom", "rarity": "common", "oracle_text": "{T}: Add {G}."}, []float64{0, 0, 1}},
+}
+
+// startWeaviate runs a throwaway Weaviate container and returns a client for it. The test is
+// skipped when no Docker daemon is reachable.
+func startWeaviate(t *testing.T) *Client {
+    t.Helper()
+    testcontainers.SkipIfProviderIsNotHealthy(t)
+    ctx := context.Background()
+    ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+        ContainerRequest: testcontainers.ContainerRequest{
+            Image:        weaviateImage,
+            ExposedPorts: []string{"8080/tcp"},
+            Env: map[string]string{
+                "AUTHENTICATION_ANONYMOUS_ACCESS_ENABLED": "true",
+                "PERSISTENCE_DATA_PATH":                   "/var/lib/weaviate",
+                "DEFAULT_VECTORIZER_MODULE":               "none",
+                "ENABLE_MODULES":                          "",
+                "CLUSTER_HOSTNAME":                        "node1",
+            },
+            WaitingFor: wait.ForHTTP("/v1/.well-known/ready").WithPort("8080/tcp").WithStartupTimeout(2 * time.Minute),
+        },
+        Started: true,
+    })
+    testcontainers.CleanupContainer(t, ctr)
+    if err != nil { t.Fatalf("start weaviate: %v", err) }
+    endpoint, err := ctr.PortEndpoint(ctx, "8080/tcp", "http")
+    if err != nil { t.Fatalf("weaviate endpoint: %v", err) }
+    return NewClient(endpoint)
+}
+
+// ingestFixtures writes the fixture cards through the REST batch endpoint, as ingest_batch.sh does.
+func ingestFixtures(t *testing.T, cli *Client) {
+    t.Helper()
+    objects := make([]map[string]any, len(fixtureCards))
+    for i, fc := range fixtureCards {
+        objects[i] = map[string]any{"class": "Card", "id": fc.id, "properties": fc.props, "vector": fc.vector}
+    }
+    body, _ := json.Marshal(map[string]any{"objects": objects})
+    resp, err := http.Post(cli.baseURL+"/v1/batch/objects", "application/json", bytes.NewReader(body))
+    if err != nil { t.Fatalf("batch ingest: %v", err) }
+    defer resp.Body.Close()
+    var results []struct {
+        Result struct { Errors any `json:"errors"` } `json:"result"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&results); err != nil || resp.StatusCode != http.StatusOK {
+        t.Fatalf("batch ingest: status %d, err %v", resp.StatusCode, err)
+    }
+    for i, r := range results {
+        if r.Result.Errors != nil { t.Fatalf("batch ingest %s: %v", fixtureCards[i].id, r.Result.Errors) }
+    }
+}
+
+func TestIntegrationCardQueries(t *testing.T) {
+    cli := startWeaviate(t)
+    ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+    defer cancel()
+
+    created, err := cli.EnsureCardClass(ctx)
+    if err != nil || !created { t.Fatalf("EnsureCardClass = %v, %v; want created", created, err) }
+    if created, err := cli.EnsureCardClass(ctx); err != nil || created { t.Fatalf("second EnsureCardClass = %v, %v; want existing class kept", created, err) }
+    ingestFixtures(t, cli)
+
+    t.Run("ListCards", func(t *testing.T) {
+        cards, err := cli.ListCards(WithSort(ctx, "name", false), 0, 10)
+        if err != nil { t.Fatalf("ListCards: %v", err) }
+        var names []string
+        for _, c := range cards { names = append(names, c.Name) }
+        want := []string{"Counterspell", "Lightning Bolt", "Llanowar Elves", "Shock"}
+        if len(names) != len(want) { t.Fatalf("names = %v, want %v", names, want) }
+        for i := range want {
+            if names[i] != want[i] { t.Fatalf("names = %v, want %v", names, want) }
+        }
+        if page, err := cli.ListCards(WithSort(ctx, "name", false), 3, 10); err != nil || len(page) != 1 || page[0].Name != "Shock" {
+            t.Errorf("offset 3 = %v, %v; want [Shock]", page, err)
+        }
+    })
+
+    t.Run("ListCardsPage", func(t *testing.T) {
+        want, err := cli.CountCards(ctx)
+        if err != nil { t.Fatalf("CountCards: %v", err) }
+        cards, total, err := cli.ListCardsPage(ctx, 0, 2)
+        if err != nil || len(cards) != 2 || total != want { t.Errorf("ListCardsPage = %d cards, total %d, %v; want 2 cards, total %d", len(cards), total, err, want) }
+    })
+
+    t.Run("FindByNameLike", func(t *testing.T) {
+        cards, err := cli.FindByNameLike(ctx, "bolt", 10)
+        if err != nil { t.Fatalf("FindByNameLike: %v", err) }
+        if len(cards) != 1 || cards[0].Name != "Lightning Bolt" || cards[0].Set != "m21" { t.Errorf("cards = %+v", cards) }
+    })
+
+    t.Run("FetchVectorForName", func(t *testing.T) {
+        vec, id, err := cli.FetchVectorForName(ctx, "Counterspell")
+        if err != nil { t.Fatalf("FetchVectorForName: %v", err) }
+        if id != fixtureCards[2].id || len(vec) != 3 { t.Errorf("got %v, %s", vec, id) }
+        if _, _, err := cli.FetchVectorForName(ctx, "Elves"); err != nil { t.Errorf("LIKE fallback: %v", err) }
+        if _, _, err := cli.FetchVectorForName(ctx, "No Such Card"); !errors.Is(err, ErrCardNotFound) { t.Errorf("missing card: err = %v, want ErrCardNotFound", err) }
+    })
+
+    t.Run("SearchNearVector", func(t *testing.T) {
+        cards, err := cli.SearchNearVector(ctx, fixtureCards[0].vector, 2)
+        if err != nil { t.Fatalf("SearchNearVector: %v", err) }
+        if len(cards) != 2 || cards[0].Name != "Lightning Bolt" || cards[1].Name != "Shock" { t.Fatalf("cards = %+v", cards) }
+        if cards[0].Similarity < 0.99 || cards[1].Similarity >= cards[0].Similarity { t.Errorf("similarities = %v, %v", cards[0].Similarity, cards[1].Similarity) }
+        filtered, err := cli.SearchNearVectorFiltered(ctx, fixtureCards[0].vector, 2, TextFilter("Equal", "set", "mh2"))
+        if err != nil { t.Fatalf("SearchNearVectorFiltered: %v", err) }
+        if len(filtered) != 1 || filtered[0].Name != "Counterspell" { t.Errorf("filtered = %+v", filtered) }
+    })
+
+    t.Run("CountPresentAtAll", func(t *testing.T) {
+        ids := []string{fixtureCards[0].id, fixtureCards[3].id, "00000000-0000-4000-8000-000000000009"}
+        n, err := cli.CountPresent(WithConsistency(ctx, ConsistencyAll), ids)
+        if err != nil || n != 2 { t.Errorf("CountPresent = %d, %v; want 2", n, err) }
+    })
+}
+
+// TestVerifySample reads a few Cards back from the Weaviate at WEAVIATE_URL at consistency
+// level CONSISTENCY (default ALL), as `make verify-sample` does right after ingestion. It is
+// skipped when WEAVIATE_URL is unset.
+func TestVerifySample(t *testing.T) {
+    url := os.Getenv("WEAVIATE_URL")
+    if url == "" { t.Skip("WEAVIATE_URL not set") }
+    level := cmp.Or(os.Getenv("CONSISTENCY"), ConsistencyAll)
+    ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+    defer cancel()
+    cards, err := NewClient(url, WithConsistencyLevel(level)).ListCards(ctx, 0, 3)
+    if err != nil { t.Fatalf("read at %s: %v", level, err) }
+    if len(cards) == 0 { t.Fatalf("no cards readable at %s", level) }
+    for _, c := range cards { t.Logf("%s %s", c.ID, c.Name) }
+}
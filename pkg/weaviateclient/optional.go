@@ -0,0 +1,66 @@
+package weaviateclient
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "strings"
+)
+
+// Properties added to the schema after the first release. Collections ingested before
+// them reject queries selecting them, so queries treat them as optional.
+const (
+    propCardFaces  = "card_faces"
+    propPrices     = "prices"
+    propOracleID   = "oracle_id"
+    propImageLarge = "image_large"
+)
+
+// doOptional runs q, which selects each of the optional properties as " name". When Weaviate
+// rejects one of them because the collection predates it, the property is remembered as missing
+// and the query is retried without it; later queries skip known-missing properties up front.
+func (c *Client) doOptional(ctx context.Context, op Operation, q string, optional ...string) (json.RawMessage, error) {
+    for _, p := range optional {
+        if _, gone := c.missing.Load(p); gone { q = dropField(q, p) }
+    }
+    for {
+        data, err := c.do(ctx, op, q)
+        if err == nil { return data, nil }
+        // A partial response means the schema accepted the query; a field failing to resolve
+        // is not a missing property.
+        var partial *PartialError
+        if errors.As(err, &partial) { return data, err }
+        retry := q
+        for _, p := range optional {
+            // Weaviate reports e.g. `Cannot query field "prices" on type "Card".`
+            if strings.Contains(err.Error(), `"`+p+`"`) && strings.Contains(retry, " "+p) {
+                c.missing.Store(p, struct{}{})
+                retry = dropField(retry, p)
+            }
+        }
+        if retry == q { return nil, err }
+        q = retry
+    }
+}
+
+// dropField removes the last " name" from q. Selections follow the arguments, so this never
+// touches user text inside a where filter that happens to contain the property name.
+func dropField(q, name string) string {
+    i := strings.LastIndex(q, " "+name)
+    if i < 0 { return q }
+    return q[:i] + q[i+1+len(name):]
+}
+
+// parsePrices reads the prices JSON string written at ingest (Scryfall's prices object, e.g.
+// {"usd":"0.25","eur":"0.20","tix":"0.03"}). Missing or null prices come back empty.
+func parsePrices(s string) (usd, eur, tix string) {
+    if s == "" { return "", "", "" }
+    var p struct {
+        USD *string `json:"usd"`
+        EUR *string `json:"eur"`
+        Tix *string `json:"tix"`
+    }
+    if json.Unmarshal([]byte(s), &p) != nil { return "", "", "" }
+    deref := func(v *string) string { if v == nil { return "" }; return *v }
+    return deref(p.USD), deref(p.EUR), deref(p.Tix)
+}
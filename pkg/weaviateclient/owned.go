@@ -0,0 +1,84 @@
+package weaviateclient
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "sort"
+    "strings"
+)
+
+// propOwned is the boolean Card property marking cards in the user's collection. It is written
+// by SetOwned; collections that predate it get it through Weaviate's auto-schema on first write.
+const propOwned = "owned"
+
+// maxOwned caps ListOwned at Weaviate's default QUERY_MAXIMUM_RESULTS.
+const maxOwned = 10000
+
+// OwnedError reports a SetOwned call that updated only some cards. Failed maps each Scryfall ID
+// that could not be updated to its error.
+type OwnedError struct {
+    Updated int
+    Failed  map[string]error
+}
+
+func (e *OwnedError) Error() string {
+    ids := make([]string, 0, len(e.Failed))
+    for id := range e.Failed { ids = append(ids, id) }
+    sort.Strings(ids)
+    return fmt.Sprintf("set owned: %d of %d updates failed (first %s: %v)", len(e.Failed), e.Updated+len(e.Failed), ids[0], e.Failed[ids[0]])
+}
+
+// SetOwned sets the owned property on the Card objects with these Scryfall IDs (object IDs are
+// Scryfall IDs, see embed_cards.py) with one object PATCH each, leaving other properties and the
+// vector untouched. It keeps going past failures and returns an *OwnedError listing them; a
+// canceled ctx stops it early.
+func (c *Client) SetOwned(ctx context.Context, scryfallIDs []string, owned bool) error {
+    body, _ := json.Marshal(map[string]any{"class": "Card", "properties": map[string]any{propOwned: owned}})
+    oe := &OwnedError{Failed: map[string]error{}}
+    for _, id := range scryfallIDs {
+        if err := ctx.Err(); err != nil { return err }
+        if err := c.patchObject(ctx, id, body); err != nil { oe.Failed[id] = err; continue }
+        oe.Updated++
+    }
+    if len(oe.Failed) > 0 { return oe }
+    return nil
+}
+
+func (c *Client) patchObject(ctx context.Context, id string, body []byte) error {
+    if c.limiter != nil {
+        if err := c.limiter.wait(ctx); err != nil { return err }
+    }
+    ctx, cancel := c.withTimeout(ctx, OpAdmin)
+    defer cancel()
+    req, err := http.NewRequestWithContext(ctx, http.MethodPatch, c.baseURL+"/v1/objects/Card/"+url.PathEscape(id), bytes.NewReader(body))
+    if err != nil { return err }
+    req.Header.Set("Content-Type", "application/json")
+    resp, err := c.http.Do(req)
+    if err != nil { return err }
+    defer resp.Body.Close()
+    switch {
+    case resp.StatusCode == http.StatusNotFound:
+        return notFound(id)
+    case resp.StatusCode/100 != 2:
+        data, _ := io.ReadAll(resp.Body)
+        return fmt.Errorf("patch status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+    }
+    return nil
+}
+
+// ListOwned returns every card marked owned, in no particular order. A collection where nothing
+// was ever marked (so Weaviate doesn't know the property yet) has none.
+func (c *Client) ListOwned(ctx context.Context) ([]Card, error) {
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: BoolFilter("Equal", propOwned, true), limit: maxOwned, fields: listFields}.String()
+    data, err := c.doOptional(ctx, OpList, q, listOptional...)
+    if err != nil {
+        if strings.Contains(err.Error(), "no such prop") && strings.Contains(err.Error(), propOwned) { return []Card{}, nil }
+        return nil, err
+    }
+    return decodeCardList(data)
+}
@@ -0,0 +1,36 @@
+package weaviateclient
+
+import (
+    "context"
+    "fmt"
+    "strings"
+)
+
+// PartialError reports a GraphQL response that carried both data and errors: some fields
+// resolved, others did not (e.g. one property failing to resolve for one object). Client
+// methods return it as a failure unless the context allows partial results (see
+// WithPartialResults). Test with errors.As.
+type PartialError struct {
+    Messages  []string
+    RequestID string // from the request context, if any
+}
+
+func (e *PartialError) Error() string {
+    msg := "partial response: " + strings.Join(e.Messages, "; ")
+    if e.RequestID != "" { msg += fmt.Sprintf(" (request_id=%s)", e.RequestID) }
+    return msg
+}
+
+type partialKey struct{}
+
+// WithPartialResults lets calls made with the returned context use the data of a partial
+// response, logging its errors instead of failing; fields that failed to resolve come back
+// empty. Suits pages that can render a card without its optional fields, like a detail view.
+func WithPartialResults(ctx context.Context) context.Context {
+    return context.WithValue(ctx, partialKey{}, true)
+}
+
+func partialAllowed(ctx context.Context) bool {
+    ok, _ := ctx.Value(partialKey{}).(bool)
+    return ok
+}
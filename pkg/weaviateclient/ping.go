@@ -0,0 +1,21 @@
+package weaviateclient
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+)
+
+// Ping checks that Weaviate is up and ready to serve queries (GET /v1/.well-known/ready), so
+// tools can report an unreachable server up front instead of on their first query.
+func (c *Client) Ping(ctx context.Context) error {
+    ctx, cancel := c.withTimeout(ctx, OpAdmin)
+    defer cancel()
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/.well-known/ready", nil)
+    if err != nil { return err }
+    resp, err := c.http.Do(req)
+    if err != nil { return err }
+    resp.Body.Close()
+    if resp.StatusCode/100 != 2 { return fmt.Errorf("not ready: status %d", resp.StatusCode) }
+    return nil
+}
@@ -0,0 +1,492 @@
+package weaviateclient
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestQueryBuilders(t *testing.T) {
+    tests := []struct {
+        name, got, want string
+    }{
+        {
+            name: "vector by name",
+            got:  vectorByNameQuery(`Ach! Hans, "Run"!`, ""),
+            want: `{ Get { Card(where:{path:["name"], operator: Equal, valueString:"Ach! Hans, \"Run\"!"}, limit:1){ name _additional{ id vector } } } }`,
+        },
+        {
+            name: "vector by name like",
+            got:  vectorByNameLikeQuery("50% Off?", ""),
+            want: `{ Get { Card(where:{path:["name"], operator: Like, valueText:"*50% Off?*"}, limit:4){ name _additional{ id vector } } } }`,
+        },
+        {
+            name: "vector by scryfall id",
+            got:  vectorByScryfallIDQuery("abc-123", ""),
+            want: `{ Get { Card(where:{path:["scryfall_id"], operator: Equal, valueString:"abc-123"}, limit:1){ scryfall_id _additional{ id vector } } } }`,
+        },
+        {
+            name: "card by scryfall id",
+            got:  cardByScryfallIDQuery("abc-123"),
+            want: `{ Get { Card(where:{path:["scryfall_id"], operator: Equal, valueString:"abc-123"}, limit:1){ ` + detailFields + ` } } }`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if tt.got != tt.want { t.Errorf("query =\n%s\nwant\n%s", tt.got, tt.want) }
+        })
+    }
+    for _, f := range []string{"edhrec_rank", "legalities", "color_identity", "keywords", "card_faces", "prices", "oracle_id", "image_large"} {
+        if !strings.Contains(detailFields, " "+f+" ") { t.Errorf("detailFields does not select %s", f) }
+    }
+}
+
+func TestGetQuery(t *testing.T) {
+    tests := []struct {
+        name string
+        q    queryBuilder
+        want string
+    }{
+        {
+            name: "fields only",
+            q:    queryBuilder{fields: "name"},
+            want: `{ Get { Card{ name } } }`,
+        },
+        {
+            name: "consistency level and cursor",
+            q:    queryBuilder{consistency: ConsistencyQuorum, limit: 1000, after: "obj-9", fields: "_additional{ id }"},
+            want: `{ Get { Card(consistencyLevel:QUORUM, limit:1000, after:"obj-9"){ _additional{ id } } } }`,
+        },
+        {
+            name: "limit and offset",
+            q:    queryBuilder{limit: 20, offset: 40, fields: "name"},
+            want: `{ Get { Card(limit:20, offset:40){ name } } }`,
+        },
+        {
+            name: "escaped where and sort",
+            q:    queryBuilder{where: TextFilter("Like", "name", `*"Ach"\*`), sort: sortSpec{path: "cmc", desc: true}, limit: 5, fields: "name"},
+            want: `{ Get { Card(where:{path:["name"], operator: Like, valueText:"*\"Ach\"\\*"}, sort:[{path:["cmc"], order:desc}, {path:["name"], order:desc}], limit:5){ name } } }`,
+        },
+        {
+            name: "near vector in argument order",
+            q:    queryBuilder{limit: 3, autocut: 1, nearVector: []float64{0.5, -1}, where: TextFilter("NotEqual", "id", "abc"), fields: "name _additional{ id distance }"},
+            want: `{ Get { Card(where:{path:["id"], operator: NotEqual, valueText:"abc"}, nearVector:{ vector:[0.5,-1] }, autocut:1, limit:3){ name _additional{ id distance } } } }`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := tt.q.String(); got != tt.want { t.Errorf("query =\n%s\nwant\n%s", got, tt.want) }
+        })
+    }
+}
+
+// TestGetQueryCallers pins the queries of the methods built on queryBuilder to the text they
+// sent when they formatted GraphQL by hand.
+func TestGetQueryCallers(t *testing.T) {
+    const nearFields = `scryfall_id name type_line mana_cost cmc colors set collector_number rarity oracle_text image_small image_normal image_large legalities prices _additional{ id distance } } } }`
+    tests := []struct {
+        name string
+        call func(context.Context, *Client) error
+        want string
+    }{
+        {
+            name: "ListCards",
+            call: func(ctx context.Context, c *Client) error { _, err := c.ListCards(ctx, 40, 20); return err },
+            want: `{ Get { Card(limit:20, offset:40){ ` + browseFields + ` } } }`,
+        },
+        {
+            name: "ListCards sorted",
+            call: func(ctx context.Context, c *Client) error { _, err := c.ListCards(WithSort(ctx, "name", false), 40, 20); return err },
+            want: `{ Get { Card(sort:[{path:["name"], order:asc}], limit:20, offset:40){ ` + browseFields + ` } } }`,
+        },
+        {
+            name: "FindByNameLike",
+            call: func(ctx context.Context, c *Client) error { _, err := c.FindByNameLike(ctx, `Ach! "Hans"`, 5); return err },
+            want: `{ Get { Card(where:{path:["name"], operator: Like, valueText:"*Ach! \"Hans\"*"}, limit:5){ ` + browseFields + ` } } }`,
+        },
+        {
+            name: "SearchNearVector",
+            call: func(ctx context.Context, c *Client) error { _, err := c.SearchNearVector(WithAutocut(ctx, 2), []float64{0.25, 1}, 3); return err },
+            want: `{ Get { Card(nearVector:{ vector:[0.25,1] }, autocut:2, limit:3){ ` + nearFields,
+        },
+        {
+            name: "SearchNearVectorFiltered",
+            call: func(ctx context.Context, c *Client) error {
+                _, err := c.SearchNearVectorFiltered(ctx, []float64{1}, 3, TextFilter("Equal", "set", "mh3"))
+                return err
+            },
+            want: `{ Get { Card(where:{path:["set"], operator: Equal, valueText:"mh3"}, nearVector:{ vector:[1] }, limit:3){ ` + nearFields,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            cli, queries := fakeWeaviate(t, fakeRoute{"", emptyGet})
+            if err := tt.call(context.Background(), cli); err != nil { t.Fatalf("%s: %v", tt.name, err) }
+            qs := queries()
+            if len(qs) != 1 { t.Fatalf("got %d queries, want 1", len(qs)) }
+            if got := qs[0]; got != tt.want { t.Errorf("query =\n%s\nwant\n%s", got, tt.want) }
+        })
+    }
+}
+
+func TestFetchVectorForName(t *testing.T) {
+    const (
+        none    = `{"data":{"Get":{"Card":[]}}}`
+        bolt    = `{"data":{"Get":{"Card":[{"name":"Lightning Bolt","_additional":{"id":"obj-1","vector":[0.5,0.25]}}]}}}`
+        noVec   = `{"data":{"Get":{"Card":[{"name":"Lightning Bolt","_additional":{"id":"obj-1","vector":null}}]}}}`
+        failure = `{"errors":[{"message":"like exploded"}]}`
+    )
+    tests := []struct {
+        name        string
+        exact, like string // responses; like is unused when exact matches
+        wantVec     []float64
+        wantID      string
+        wantErr     error
+        wantQueries int
+    }{
+        {name: "exact match", exact: bolt, wantVec: []float64{0.5, 0.25}, wantID: "obj-1", wantQueries: 1},
+        {name: "like fallback", exact: none, like: bolt, wantVec: []float64{0.5, 0.25}, wantID: "obj-1", wantQueries: 2},
+        {name: "not found", exact: none, like: none, wantErr: ErrCardNotFound, wantQueries: 2},
+        {name: "exact without vector", exact: noVec, wantID: "obj-1", wantErr: ErrNoVector, wantQueries: 1},
+        {name: "like without vector", exact: none, like: noVec, wantID: "obj-1", wantErr: ErrNoVector, wantQueries: 2},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            cli, queries := fakeWeaviate(t,
+                fakeRoute{"operator: Equal", tt.exact},
+                fakeRoute{"operator: Like", tt.like},
+            )
+            vec, id, err := cli.FetchVectorForName(context.Background(), "Bolt")
+            if tt.wantErr != nil {
+                if !errors.Is(err, tt.wantErr) { t.Fatalf("err = %v, want %v", err, tt.wantErr) }
+            } else if err != nil {
+                t.Fatalf("FetchVectorForName: %v", err)
+            }
+            if !reflect.DeepEqual(vec, tt.wantVec) { t.Errorf("vector = %v, want %v", vec, tt.wantVec) }
+            if id != tt.wantID { t.Errorf("id = %q, want %q", id, tt.wantID) }
+            qs := queries()
+            if len(qs) != tt.wantQueries { t.Fatalf("sent %d queries, want %d: %q", len(qs), tt.wantQueries, qs) }
+            if qs[0] != vectorByNameQuery("Bolt", "") { t.Errorf("first query = %s", qs[0]) }
+            if len(qs) > 1 && qs[1] != vectorByNameLikeQuery("Bolt", "") { t.Errorf("fallback query = %s", qs[1]) }
+        })
+    }
+
+    t.Run("like error", func(t *testing.T) {
+        cli, _ := fakeWeaviate(t, fakeRoute{"operator: Equal", none}, fakeRoute{"operator: Like", failure})
+        _, _, err := cli.FetchVectorForName(context.Background(), "Bolt")
+        if err == nil || !strings.Contains(err.Error(), "like lookup for Bolt") || !strings.Contains(err.Error(), "like exploded") {
+            t.Fatalf("err = %v, want the wrapped LIKE failure", err)
+        }
+    })
+}
+
+func TestGetCardByScryfallID(t *testing.T) {
+    const resp = `{"data":{"Get":{"Card":[{
+        "scryfall_id":"abc-123","oracle_id":"or-1","name":"Delver of Secrets // Insectile Aberration",
+        "type_line":"Creature — Human Wizard // Creature — Human Insect","mana_cost":"{U}","cmc":1,
+        "oracle_text":"At the beginning of your upkeep...","power":"1","toughness":"1",
+        "colors":["U"],"color_identity":["U"],"keywords":["Transform"],"edhrec_rank":1234,
+        "set":"isd","collector_number":"51","rarity":"common","layout":"transform",
+        "legalities":"{\"modern\":\"legal\",\"standard\":\"not_legal\",\"vintage\":\"restricted\"}",
+        "image_normal":"https://img/front.jpg",
+        "card_faces":"[{\"name\":\"Delver of Secrets\",\"mana_cost\":\"{U}\"},{\"name\":\"Insectile Aberration\",\"power\":\"3\",\"toughness\":\"2\"}]",
+        "prices":"{\"usd\":\"0.25\",\"eur\":null,\"tix\":\"0.03\"}",
+        "_additional":{"id":"obj-9"}}]}}}`
+    cli, queries := fakeWeaviate(t, fakeRoute{`valueString:"abc-123"`, resp})
+    got, err := cli.GetCardByScryfallID(context.Background(), "abc-123")
+    if err != nil { t.Fatalf("GetCardByScryfallID: %v", err) }
+    if qs := queries(); len(qs) != 1 || qs[0] != cardByScryfallIDQuery("abc-123") { t.Errorf("queries = %q", qs) }
+
+    want := Card{
+        ID: "obj-9", ScryfallID: "abc-123", OracleID: "or-1", Name: "Delver of Secrets // Insectile Aberration",
+        TypeLine: "Creature — Human Wizard // Creature — Human Insect", ManaCost: "{U}", CMC: 1,
+        OracleText: "At the beginning of your upkeep...", Power: "1", Toughness: "1",
+        Colors: []string{"U"}, ColorID: []string{"U"}, Keywords: []string{"Transform"}, EDHRECRank: 1234,
+        Set: "isd", CollectorNum: "51", Rarity: "common", Layout: "transform", ImageNormal: "https://img/front.jpg",
+        Legalities: map[string]string{"modern": "legal", "standard": "not_legal", "vintage": "restricted"},
+        Faces: []CardFace{{Name: "Delver of Secrets", ManaCost: "{U}"}, {Name: "Insectile Aberration", Power: "3", Toughness: "2"}},
+        PriceUSD: "0.25", PriceTix: "0.03",
+    }
+    if !reflect.DeepEqual(got, want) { t.Errorf("card =\n%+v\nwant\n%+v", got, want) }
+}
+
+func TestGetCardBySetAndCollector(t *testing.T) {
+    // Weaviate tokenizes "100★" to "100", so both printings answer either lookup.
+    const neo = `{"data":{"Get":{"Card":[
+        {"scryfall_id":"neo-100s","name":"Moon-Circuit Hacker","set":"neo","collector_number":"100★","_additional":{"id":"obj-2"}},
+        {"scryfall_id":"neo-100","name":"Moon-Circuit Hacker","set":"neo","collector_number":"100","_additional":{"id":"obj-1"}}]}}}`
+    const starOnly = `{"data":{"Get":{"Card":[{"scryfall_id":"neo-101s","name":"Mukotai Ambusher","set":"neo","collector_number":"101★","_additional":{"id":"obj-3"}}]}}}`
+    cli, queries := fakeWeaviate(t, fakeRoute{`valueText:"100`, neo}, fakeRoute{`valueText:"101"`, starOnly}, fakeRoute{"", emptyGet})
+    got, err := cli.GetCardBySetAndCollector(context.Background(), " NEO", "100")
+    if err != nil { t.Fatalf("GetCardBySetAndCollector: %v", err) }
+    if got.ScryfallID != "neo-100" || got.Set != "neo" || got.CollectorNum != "100" { t.Errorf("card = %+v", got) }
+    want := `where:{operator: And, operands:[{path:["set"], operator: Equal, valueText:"neo"}, {path:["collector_number"], operator: Equal, valueText:"100"}]}, limit:5){ ` + detailFields
+    if qs := queries(); len(qs) != 1 || !strings.Contains(qs[0], want) { t.Errorf("queries = %q, want one containing %s", qs, want) }
+
+    if got, err := cli.GetCardBySetAndCollector(context.Background(), "neo", "100★"); err != nil || got.ScryfallID != "neo-100s" { t.Errorf("star variant: got %+v, %v", got, err) }
+    if _, err := cli.GetCardBySetAndCollector(context.Background(), "neo", "101"); !errors.Is(err, ErrCardNotFound) { t.Errorf("only a variant: err = %v, want ErrCardNotFound", err) }
+    if _, err := cli.GetCardBySetAndCollector(context.Background(), "neo", "999"); !errors.Is(err, ErrCardNotFound) { t.Errorf("no match: err = %v, want ErrCardNotFound", err) }
+    if _, err := cli.GetCardBySetAndCollector(context.Background(), "neo", " "); !errors.Is(err, ErrCardNotFound) { t.Errorf("empty number: err = %v, want ErrCardNotFound", err) }
+    if n := len(queries()); n != 4 { t.Errorf("sent %d queries, want 4 (an empty number is not looked up)", n) }
+}
+
+func TestGetCardsByScryfallIDs(t *testing.T) {
+    // The IDs share every segment but the last, and Weaviate also returns a near miss.
+    const resp = `{"data":{"Get":{"Card":[
+        {"scryfall_id":"00000000-0000-4000-8000-000000000009","name":"Near Miss","_additional":{"id":"o9"}},
+        {"scryfall_id":"00000000-0000-4000-8000-000000000002","name":"Two","_additional":{"id":"o2"}},
+        {"scryfall_id":"00000000-0000-4000-8000-000000000001","name":"One","_additional":{"id":"o1"}}]}}}`
+    cli, queries := fakeWeaviate(t, fakeRoute{"", resp})
+    ids := []string{"00000000-0000-4000-8000-000000000001", "00000000-0000-4000-8000-000000000002", " 00000000-0000-4000-8000-000000000001", "00000000-0000-4000-8000-000000000003"}
+    got, err := cli.GetCardsByScryfallIDs(context.Background(), ids)
+    if err != nil { t.Fatalf("GetCardsByScryfallIDs: %v", err) }
+    if len(got) != 2 || got[ids[0]].Name != "One" || got[ids[1]].Name != "Two" { t.Errorf("cards = %+v", got) }
+    qs := queries()
+    if len(qs) != 1 { t.Fatalf("got %d queries, want 1", len(qs)) }
+    if strings.Contains(qs[0], "ContainsAny") || strings.Count(qs[0], "operator: Equal") != 3 || !strings.Contains(qs[0], "operator: Or") {
+        t.Errorf("query = %s, want an Or of one Equal per distinct ID", qs[0])
+    }
+}
+
+func TestCountPresent(t *testing.T) {
+    // Two of the three IDs exist; a neighbour sharing their segments comes back as well.
+    const resp = `{"data":{"Get":{"Card":[
+        {"_additional":{"id":"00000000-0000-4000-8000-000000000001"}},
+        {"_additional":{"id":"00000000-0000-4000-8000-000000000009"}},
+        {"_additional":{"id":"00000000-0000-4000-8000-000000000002"}}]}}}`
+    cli, queries := fakeWeaviate(t, fakeRoute{"", resp})
+    ids := []string{"00000000-0000-4000-8000-000000000001", "00000000-0000-4000-8000-000000000002", "00000000-0000-4000-8000-000000000003", "00000000-0000-4000-8000-000000000001"}
+    n, err := cli.CountPresent(context.Background(), ids)
+    if err != nil { t.Fatalf("CountPresent: %v", err) }
+    if n != 2 { t.Errorf("CountPresent = %d, want 2", n) }
+    if qs := queries(); len(qs) != 1 || strings.Contains(qs[0], "ContainsAny") || strings.Count(qs[0], "operator: Equal") != 3 { t.Errorf("queries = %q, want one Or of Equal per distinct ID", qs) }
+}
+
+func TestConsistencyLevel(t *testing.T) {
+    cli, queries := fakeWeaviate(t, fakeRoute{"", emptyGet})
+    WithConsistencyLevel(" quorum")(cli)
+    ctx := context.Background()
+    if _, err := cli.ListCards(ctx, 0, 5); err != nil { t.Fatalf("ListCards: %v", err) }
+    if _, err := cli.CountPresent(WithConsistency(ctx, "all"), []string{"abc"}); err != nil { t.Fatalf("CountPresent: %v", err) }
+    if _, err := cli.CountWhere(WithConsistency(ctx, ConsistencyAll), nil); err != nil { t.Fatalf("CountWhere: %v", err) }
+    qs := queries()
+    if len(qs) != 3 { t.Fatalf("got %d queries, want 3", len(qs)) }
+    // listing, name and keyword search carry it too
+    if _, err := cli.ListCardsFiltered(ctx, 0, 5, "mh3", ""); err != nil { t.Fatalf("ListCardsFiltered: %v", err) }
+    if _, err := cli.SearchCards(ctx, "bolt", 5); err != nil { t.Fatalf("SearchCards: %v", err) }
+    if _, err := cli.SuggestNames(ctx, "bol", 5); err != nil { t.Fatalf("SuggestNames: %v", err) }
+    if _, err := cli.SearchNameFuzzy(ctx, "lightnin bolt", 5); err != nil { t.Fatalf("SearchNameFuzzy: %v", err) }
+    if _, err := cli.FindByKeywords(ctx, []string{"Flash"}, false, 5); err != nil { t.Fatalf("FindByKeywords: %v", err) }
+    for _, q := range queries()[3:] {
+        if !strings.HasPrefix(q, "{ Get { Card(consistencyLevel:QUORUM, ") { t.Errorf("client default not applied: %s", q) }
+    }
+    if !strings.HasPrefix(qs[0], "{ Get { Card(consistencyLevel:QUORUM, ") { t.Errorf("client default not applied: %s", qs[0]) }
+    if !strings.HasPrefix(qs[1], "{ Get { Card(consistencyLevel:ALL, ") { t.Errorf("context override not applied: %s", qs[1]) }
+    if strings.Contains(qs[2], "consistencyLevel") { t.Errorf("Aggregate query carries a consistency level: %s", qs[2]) }
+}
+
+func TestGetCardsByNames(t *testing.T) {
+    // A full first page of "Snow-Covered Forest" pushes the real Forest to the second page.
+    var snow []string
+    for i := 0; i < namePage; i++ { snow = append(snow, fmt.Sprintf(`{"name":"Snow-Covered Forest","set":"s%d","_additional":{"id":"snow-%d"}}`, i, i)) }
+    cli, queries := fakeWeaviate(t,
+        fakeRoute{`valueText:"Forest"}, limit:20){`, `{"data":{"Get":{"Card":[` + strings.Join(snow, ",") + `]}}}`},
+        fakeRoute{`valueText:"Forest"}, limit:20, offset:20)`, `{"data":{"Get":{"Card":[{"name":"Forest","set":"m21","_additional":{"id":"forest"}}]}}}`},
+        fakeRoute{`valueText:"Shock"`, `{"data":{"Get":{"Card":[{"name":"Electrostatic Shock","_additional":{"id":"es"}},{"name":"Shock Troops","_additional":{"id":"st"}}]}}}`},
+        fakeRoute{`valueText:"delver of secrets"`, `{"data":{"Get":{"Card":[{"name":"Delver of Secrets // Insectile Aberration","_additional":{"id":"delver"}}]}}}`},
+    )
+    got, err := cli.GetCardsByNames(context.Background(), []string{"Forest", " Shock", "delver of secrets", "forest", ""})
+    if err != nil { t.Fatalf("GetCardsByNames: %v", err) }
+    if len(got) != 2 || got["forest"].ID != "forest" || got["delver of secrets"].ID != "delver" { t.Errorf("cards = %+v", got) }
+    if _, ok := got["shock"]; ok { t.Errorf("Shock resolved to %+v, want unresolved", got["shock"]) }
+    if n := len(queries()); n != 4 { t.Errorf("sent %d queries, want 4 (two pages for Forest, one each for the others)", n) }
+}
+
+func TestListPrintingsByName(t *testing.T) {
+    // Equal on name also matches Snow-Covered Forest, which fills most of the first page.
+    cli, queries := fakeWeaviate(t,
+        fakeRoute{"offset:2", `{"data":{"Get":{"Card":[{"scryfall_id":"f2","name":"forest","oracle_id":"o1","set":"m21","_additional":{"id":"b"}}]}}}`},
+        fakeRoute{"", `{"data":{"Get":{"Card":[{"scryfall_id":"s1","name":"Snow-Covered Forest","oracle_id":"o2","set":"khm","_additional":{"id":"s"}},{"scryfall_id":"f1","name":"Forest","oracle_id":"o1","set":"neo","_additional":{"id":"a"}}]}}}`},
+    )
+    got, err := cli.ListPrintingsByName(context.Background(), "Forest", 2)
+    if err != nil { t.Fatalf("ListPrintingsByName: %v", err) }
+    var ids []string
+    for _, p := range got { ids = append(ids, p.ScryfallID) }
+    if want := []string{"f1", "f2"}; !reflect.DeepEqual(ids, want) { t.Errorf("printings = %q, want %q", ids, want) }
+    if qs := queries(); len(qs) != 2 || !strings.Contains(qs[0], " name ") { t.Errorf("queries = %q, want two pages selecting name", qs) }
+}
+
+func TestSearchCardsRanksBeforeLimit(t *testing.T) {
+    // Rules-text hits for "Flash" alone would fill the limit before the card named Flash.
+    cli, queries := fakeWeaviate(t,
+        fakeRoute{"operator: Equal", `{"data":{"Get":{"Card":[{"name":"Flash Flood","_additional":{"id":"ff"}},{"name":"Flash","_additional":{"id":"f"}}]}}}`},
+        fakeRoute{"operator: Or", `{"data":{"Get":{"Card":[{"name":"Ambush Viper","_additional":{"id":"o1"}},{"name":"Brazen Borrower","_additional":{"id":"o2"}}]}}}`},
+        fakeRoute{"operator: Like", `{"data":{"Get":{"Card":[{"name":"Flash Flood","_additional":{"id":"ff"}},{"name":"Flashfreeze","_additional":{"id":"fz"}}]}}}`},
+    )
+    got, err := cli.SearchCards(context.Background(), "flash", 3)
+    if err != nil { t.Fatalf("SearchCards: %v", err) }
+    var names []string
+    for _, c := range got { names = append(names, c.Name) }
+    if want := []string{"Flash", "Flash Flood", "Flashfreeze"}; !reflect.DeepEqual(names, want) { t.Errorf("SearchCards = %q, want %q", names, want) }
+    if n := len(queries()); n != 2 { t.Errorf("sent %d queries, want 2 (the rules-text query is skipped once the limit is met)", n) }
+
+    // With a sort, one query over name and rules text keeps Weaviate's order.
+    if _, err := cli.SearchCards(WithSort(context.Background(), "name", false), "flash", 3); err != nil { t.Fatalf("sorted SearchCards: %v", err) }
+    if qs := queries(); len(qs) != 3 || !strings.Contains(qs[2], "operator: Or") || !strings.Contains(qs[2], "sort:") { t.Errorf("sorted query = %q", qs[len(qs)-1]) }
+}
+
+func TestDecodeCardDetailsLegalities(t *testing.T) {
+    tests := []struct {
+        name  string
+        legal string // the legalities property as stored: a JSON-encoded string
+        want  map[string]string
+    }{
+        {name: "object", legal: `"{\"commander\":\"legal\",\"pauper\":\"banned\"}"`, want: map[string]string{"commander": "legal", "pauper": "banned"}},
+        {name: "empty string", legal: `""`, want: map[string]string{}},
+        {name: "null", legal: `null`, want: map[string]string{}},
+        {name: "malformed", legal: `"{not json"`, want: map[string]string{}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            data := json.RawMessage(`{"Get":{"Card":[{"name":"X","legalities":` + tt.legal + `}]}}`)
+            cards, err := decodeCardDetails(data)
+            if err != nil { t.Fatalf("decodeCardDetails: %v", err) }
+            if len(cards) != 1 { t.Fatalf("got %d cards, want 1", len(cards)) }
+            if !reflect.DeepEqual(cards[0].Legalities, tt.want) { t.Errorf("legalities = %v, want %v", cards[0].Legalities, tt.want) }
+        })
+    }
+}
+
+func TestGetCardByScryfallIDMissingOptionalProps(t *testing.T) {
+    const card = `{"data":{"Get":{"Card":[{"scryfall_id":"abc","name":"Old Card","_additional":{"id":"o"}}]}}}`
+    cli, queries := fakeWeaviate(t,
+        fakeRoute{" prices", `{"errors":[{"message":"Cannot query field \"prices\" on type \"Card\"."}]}`},
+        fakeRoute{`valueString:"abc"`, card},
+    )
+    got, err := cli.GetCardByScryfallID(context.Background(), "abc")
+    if err != nil { t.Fatalf("GetCardByScryfallID: %v", err) }
+    if got.Name != "Old Card" || got.PriceUSD != "" { t.Errorf("card = %+v", got) }
+    qs := queries()
+    if len(qs) != 2 { t.Fatalf("sent %d queries, want 2", len(qs)) }
+    if strings.Contains(qs[1], " prices") { t.Errorf("retry still selects prices: %s", qs[1]) }
+
+    // The missing property is remembered, so the next lookup skips it up front.
+    if _, err := cli.GetCardByScryfallID(context.Background(), "abc"); err != nil { t.Fatalf("second lookup: %v", err) }
+    if qs := queries(); len(qs) != 3 || strings.Contains(qs[2], " prices") { t.Errorf("second lookup queries = %q", qs[2:]) }
+}
+
+func TestTargetVector(t *testing.T) {
+    cli, queries := fakeWeaviate(t,
+        fakeRoute{"/v1/schema/Card", `{"class":"Card","vectorConfig":{"oracle":{"vectorizer":{"none":{}}},"name":{"vectorizer":{"none":{}}}}}`},
+        fakeRoute{"", `{"data":{"Get":{"Card":[{"name":"Bolt","_additional":{"id":"obj-1","distance":0.1,"vectors":{"oracle":[0.5,0.25]}}}]}}}`},
+    )
+    ctx := WithTargetVector(context.Background(), "oracle")
+
+    vec, id, err := cli.FetchVectorForName(ctx, "Bolt")
+    if err != nil { t.Fatalf("FetchVectorForName: %v", err) }
+    if id != "obj-1" || !reflect.DeepEqual(vec, []float64{0.5, 0.25}) { t.Errorf("got %v, %s", vec, id) }
+    if !strings.Contains(queries()[0], "_additional{ id vectors { oracle } }") { t.Errorf("lookup query = %s", queries()[0]) }
+
+    if _, err := cli.SearchNearVector(ctx, vec, 3); err != nil { t.Fatalf("SearchNearVector: %v", err) }
+    if q := queries()[len(queries())-1]; !strings.Contains(q, `nearVector:{ vector:[0.5,0.25], targetVectors:["oracle"] }`) { t.Errorf("search query = %s", q) }
+
+    // Without a target the unnamed vector is used, as before.
+    if _, _, err := cli.FetchVectorForName(context.Background(), "Bolt"); !errors.Is(err, ErrNoVector) { t.Errorf("unnamed vector: err = %v, want ErrNoVector", err) }
+
+    sent := len(queries())
+    for _, target := range []string{"rules", "oracle }"} {
+        _, err := cli.SearchNearVector(WithTargetVector(context.Background(), target), vec, 3)
+        if !errors.Is(err, ErrUnknownTargetVector) { t.Errorf("target %q: err = %v, want ErrUnknownTargetVector", target, err) }
+    }
+    if qs := queries(); len(qs) != sent { t.Errorf("unknown targets still queried Weaviate: %q", qs[sent:]) }
+}
+
+func TestSampleCards(t *testing.T) {
+    const (
+        count = `{"data":{"Aggregate":{"Card":[{"meta":{"count":3}}]}}}`
+        rares = `{"data":{"Get":{"Card":[{"scryfall_id":"s1","name":"Shared","colors":["W"],"rarity":"rare","_additional":{"id":"s1"}}]}}}`
+        any   = `{"data":{"Get":{"Card":[{"scryfall_id":"s2","name":"Shared","colors":["W"],"rarity":"common","_additional":{"id":"s2"}},{"scryfall_id":"b","name":"Beta","cmc":2,"colors":["W"],"rarity":"common","_additional":{"id":"b"}},{"scryfall_id":"a","name":"Alpha","cmc":2,"colors":["W"],"rarity":"common","_additional":{"id":"a"}}]}}}`
+    )
+    cli, queries := fakeWeaviate(t, fakeRoute{"Aggregate", count}, fakeRoute{`valueText:"rare"`, rares}, fakeRoute{"Get", any})
+    spec := SampleSpec{Colors: map[string]int{"W": 3}, Rarities: map[string]int{"Rare": 1}, Where: LegalIn("vintage"), Seed: 1}
+    got, err := cli.SampleCards(context.Background(), spec)
+    if err != nil { t.Fatalf("SampleCards: %v", err) }
+    var names []string
+    for _, c := range got { names = append(names, c.Name) }
+    // The rare slot takes Shared, so its common printing is skipped; cards sort by mana value.
+    if want := []string{"Shared", "Alpha", "Beta"}; !reflect.DeepEqual(names, want) { t.Errorf("pool = %v, want %v", names, want) }
+    for _, q := range queries() {
+        if !strings.Contains(q, `valueText:"vintage"`) || !strings.Contains(q, `{path:["colors"], operator: Equal, valueText:"W"}`) { t.Errorf("query lacks the format or color filter: %s", q) }
+    }
+    if colors, rarities := spec.Shortfall(got); len(colors) != 0 || len(rarities) != 0 { t.Errorf("shortfall = %v %v, want none", colors, rarities) }
+
+    short := SampleSpec{Colors: map[string]int{"W": 3, "U": 1}, Rarities: map[string]int{"mythic": 1}}
+    colors, rarities := short.Shortfall(got)
+    if !reflect.DeepEqual(colors, map[string]int{"U": 1}) || !reflect.DeepEqual(rarities, map[string]int{"mythic": 1}) { t.Errorf("shortfall = %v %v", colors, rarities) }
+
+    for _, bad := range []SampleSpec{
+        {Colors: map[string]int{"X": 1}},
+        {Colors: map[string]int{"W": -1}},
+        {Colors: map[string]int{"W": 1}, Rarities: map[string]int{"rare": 2}},
+    } {
+        if _, err := cli.SampleCards(context.Background(), bad); err == nil { t.Errorf("SampleCards(%+v) succeeded, want a validation error", bad) }
+    }
+}
+
+func TestBucketFilter(t *testing.T) {
+    for _, tc := range []struct{ bucket, want string }{
+        {"R", `{operator: And, operands:[{path:["colors"], operator: Equal, valueText:"R"}, {path:["colors"], operator: NotEqual, valueText:"W"}, {path:["colors"], operator: NotEqual, valueText:"U"}, {path:["colors"], operator: NotEqual, valueText:"B"}, {path:["colors"], operator: NotEqual, valueText:"G"}]}`},
+        {"C", `{operator: And, operands:[{path:["colors"], operator: NotEqual, valueText:"W"}, {path:["colors"], operator: NotEqual, valueText:"U"}, {path:["colors"], operator: NotEqual, valueText:"B"}, {path:["colors"], operator: NotEqual, valueText:"R"}, {path:["colors"], operator: NotEqual, valueText:"G"}]}`},
+    } {
+        if got := bucketFilter(tc.bucket).String(); got != tc.want { t.Errorf("bucketFilter(%s) = %s, want %s", tc.bucket, got, tc.want) }
+    }
+    if m := bucketFilter(BucketMulticolor); m.Operator != "Or" || len(m.Operands) != 10 { t.Errorf("multicolor filter = %s, want an Or over the 10 color pairs", m) }
+    if ColorBucket(nil) != BucketColorless || ColorBucket([]string{"u"}) != "U" || ColorBucket([]string{"W", "U"}) != BucketMulticolor { t.Error("ColorBucket misfiled a card") }
+}
+
+func TestPartialResponse(t *testing.T) {
+    // One property failed to resolve; the rest of the card came back.
+    const partial = `{"data":{"Get":{"Card":[{"scryfall_id":"abc","name":"Half Card","prices":null,"_additional":{"id":"o"}}]}},
+        "errors":[{"message":"resolve field \"prices\": storage read failed","path":["Get","Card",0,"prices"]}]}`
+    cli, queries := fakeWeaviate(t, fakeRoute{`valueString:"abc"`, partial})
+
+    _, err := cli.GetCardByScryfallID(context.Background(), "abc")
+    var pe *PartialError
+    if !errors.As(err, &pe) { t.Fatalf("err = %v, want a *PartialError", err) }
+    if len(pe.Messages) != 1 || !strings.Contains(pe.Messages[0], "storage read failed") { t.Errorf("messages = %q", pe.Messages) }
+    if qs := queries(); len(qs) != 1 { t.Errorf("sent %d queries, want 1 (a partial response is not a missing property)", len(qs)) }
+
+    got, err := cli.GetCardByScryfallID(WithPartialResults(context.Background()), "abc")
+    if err != nil { t.Fatalf("with partial results: %v", err) }
+    if got.Name != "Half Card" || got.PriceUSD != "" { t.Errorf("card = %+v", got) }
+    if qs := queries(); !strings.Contains(qs[len(qs)-1], " prices") { t.Errorf("prices dropped from the query after a partial response: %s", qs[len(qs)-1]) }
+
+    // A response with errors and no data still fails, whatever the context allows.
+    cli, _ = fakeWeaviate(t, fakeRoute{"", `{"data":null,"errors":[{"message":"boom"}]}`})
+    if _, err := cli.GetCardByScryfallID(WithPartialResults(context.Background()), "abc"); err == nil || errors.As(err, &pe) { t.Errorf("no data: err = %v, want a plain error", err) }
+    // Nor does one whose only class resolved to null: the query failed outright.
+    cli, _ = fakeWeaviate(t, fakeRoute{"", `{"data":{"Get":{"Card":null}},"errors":[{"message":"boom"}]}`})
+    if _, err := cli.GetCardByScryfallID(WithPartialResults(context.Background()), "abc"); err == nil || errors.As(err, &pe) { t.Errorf("null class: err = %v, want a plain error", err) }
+}
+
+func TestTextSearch(t *testing.T) {
+    cli, queries := fakeWeaviate(t,
+        fakeRoute{"bm25:", `{"data":{"Get":{"Card":[{"name":"Shock","_additional":{"id":"o1","score":"2.5"}}]}}}`},
+        fakeRoute{"nearText:", `{"data":{"Get":{"Card":[{"name":"Shock","_additional":{"id":"o1","distance":0.25}}]}}}`},
+    )
+    ctx := context.Background()
+    got, err := cli.SearchBM25(ctx, `deal "2" damage`, 5, nil)
+    if err != nil { t.Fatalf("SearchBM25: %v", err) }
+    if len(got) != 1 || got[0].Score != 2.5 || got[0].Similarity != 0 { t.Errorf("bm25 cards = %+v", got) }
+    want := `bm25:{ query:"deal \"2\" damage", properties:["name", "type_line", "oracle_text"] }, limit:5){ `
+    if qs := queries(); !strings.Contains(qs[0], want) || !strings.Contains(qs[0], "_additional{ id score }") { t.Errorf("bm25 query = %s", qs[0]) }
+
+    got, err = cli.SearchNearText(ctx, "draw a card", 5, nil)
+    if err != nil { t.Fatalf("SearchNearText: %v", err) }
+    if len(got) != 1 || got[0].Similarity != 0.75 || got[0].Score != 0 { t.Errorf("nearText cards = %+v", got) }
+    if qs := queries(); !strings.Contains(qs[1], `nearText:{ concepts:["draw a card"] }, limit:5`) { t.Errorf("nearText query = %s", qs[1]) }
+}
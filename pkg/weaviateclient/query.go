@@ -0,0 +1,56 @@
+package weaviateclient
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+)
+
+// queryBuilder composes a `{ Get { Card(...){ fields } } }` query from typed arguments. Zero
+// values are left out, and every value is rendered here (text through gqlString, via
+// WhereFilter), so callers never splice user input into query text themselves.
+type queryBuilder struct {
+    where        *WhereFilter
+    nearVector   []float64
+    nearText     string // concept for the class's vectorizer module
+    bm25         string // keyword query over bm25Properties
+    targetVector string // named vector the nearVector or nearText search runs against; "" for the unnamed one
+    sort         sortSpec
+    autocut      int
+    limit        int
+    offset       int
+    after        string // cursor: the object ID a full scan resumes after
+    consistency  string // consistencyLevel (ONE, QUORUM, ALL); "" for Weaviate's default
+    fields       string
+}
+
+// String renders the query; arguments appear in a fixed order.
+func (b queryBuilder) String() string {
+    var args []string
+    if b.consistency != "" { args = append(args, "consistencyLevel:"+b.consistency) }
+    if b.where != nil { args = append(args, "where:"+b.where.String()) }
+    if b.nearVector != nil {
+        vb, _ := json.Marshal(b.nearVector)
+        target := ""
+        if b.targetVector != "" { target = ", targetVectors:[" + gqlString(b.targetVector) + "]" }
+        args = append(args, "nearVector:{ vector:"+string(vb)+target+" }")
+    }
+    if b.nearText != "" {
+        target := ""
+        if b.targetVector != "" { target = ", targetVectors:[" + gqlString(b.targetVector) + "]" }
+        args = append(args, "nearText:{ concepts:["+gqlString(b.nearText)+"]"+target+" }")
+    }
+    if b.bm25 != "" {
+        props := make([]string, len(bm25Properties))
+        for i, p := range bm25Properties { props[i] = gqlString(p) }
+        args = append(args, "bm25:{ query:"+gqlString(b.bm25)+", properties:["+strings.Join(props, ", ")+"] }")
+    }
+    if s := b.sort.String(); s != "" { args = append(args, "sort:"+s) }
+    if b.autocut > 0 { args = append(args, fmt.Sprintf("autocut:%d", b.autocut)) }
+    if b.limit > 0 { args = append(args, fmt.Sprintf("limit:%d", b.limit)) }
+    if b.offset > 0 { args = append(args, fmt.Sprintf("offset:%d", b.offset)) }
+    if b.after != "" { args = append(args, "after:"+gqlString(b.after)) }
+    q := "{ Get { Card"
+    if len(args) > 0 { q += "(" + strings.Join(args, ", ") + ")" }
+    return q + "{ " + b.fields + " } } }"
+}
@@ -0,0 +1,98 @@
+package weaviateclient
+
+import (
+    "context"
+    "fmt"
+    "log/slog"
+    "math"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// WithRateLimit caps the client at rps GraphQL requests per second, allowing bursts of up to
+// burst requests. Calls over the limit wait for a token (or fail when their context ends first).
+// rps <= 0 disables limiting, the default.
+func WithRateLimit(rps float64, burst int) Option {
+    return func(c *Client) {
+        if rps <= 0 { c.limiter = nil; return }
+        c.limiter = newLimiter(rps, burst)
+    }
+}
+
+// RateLimitFromEnv builds WithRateLimit from WEAVIATE_RPS (requests per second, may be
+// fractional) and WEAVIATE_BURST (defaults to the rate rounded up). Unset or invalid
+// WEAVIATE_RPS leaves the client unlimited.
+func RateLimitFromEnv() Option {
+    v := strings.TrimSpace(os.Getenv("WEAVIATE_RPS"))
+    if v == "" { return WithRateLimit(0, 0) }
+    rps, err := strconv.ParseFloat(v, 64)
+    if err != nil || !(rps > 0) || math.IsInf(rps, 0) { // !(rps > 0) also rejects NaN
+        slog.Warn("ignoring invalid env value", "key", "WEAVIATE_RPS", "value", v)
+        return WithRateLimit(0, 0)
+    }
+    burst := int(math.Ceil(rps))
+    if b := strings.TrimSpace(os.Getenv("WEAVIATE_BURST")); b != "" {
+        if n, err := strconv.Atoi(b); err == nil && n > 0 { burst = n } else { slog.Warn("ignoring invalid env value", "key", "WEAVIATE_BURST", "value", b) }
+    }
+    slog.Info("weaviate rate limit", "rps", rps, "burst", burst)
+    return WithRateLimit(rps, burst)
+}
+
+// Limiter is the client's token bucket, exported so callers can pace requests to other
+// services (e.g. Scryfall's image CDN) the same way.
+type Limiter struct{ l *limiter }
+
+// NewLimiter allows rps requests per second with bursts of up to burst.
+func NewLimiter(rps float64, burst int) *Limiter { return &Limiter{newLimiter(rps, burst)} }
+
+// Wait blocks until the caller may make a request, or returns early with ctx's error (or
+// context.DeadlineExceeded when the wait would outlast ctx's deadline).
+func (l *Limiter) Wait(ctx context.Context) error { return l.l.wait(ctx) }
+
+// limiter is a token bucket: it holds up to burst tokens and refills at rate per second.
+// Callers reserve a token up front, so waiters are served in arrival order.
+type limiter struct {
+    mu     sync.Mutex
+    rate   float64
+    burst  float64
+    tokens float64 // negative while callers are waiting on reserved tokens
+    last   time.Time
+}
+
+func newLimiter(rps float64, burst int) *limiter {
+    b := float64(max(burst, 1))
+    return &limiter{rate: rps, burst: b, tokens: b, last: time.Now()}
+}
+
+// wait blocks until the caller's token is available. It returns early, without using up a
+// token, if ctx is done or its deadline would pass before the token arrives.
+func (l *limiter) wait(ctx context.Context) error {
+    l.mu.Lock()
+    now := time.Now()
+    l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+    l.last = now
+    l.tokens--
+    var delay time.Duration
+    if l.tokens < 0 { delay = time.Duration(-l.tokens / l.rate * float64(time.Second)) }
+    if dl, ok := ctx.Deadline(); ok && delay > 0 && now.Add(delay).After(dl) {
+        l.tokens++
+        l.mu.Unlock()
+        return fmt.Errorf("rate limit: would wait %s past the context deadline: %w", delay.Round(time.Millisecond), context.DeadlineExceeded)
+    }
+    l.mu.Unlock()
+    if delay == 0 { return nil }
+    t := time.NewTimer(delay)
+    defer t.Stop()
+    select {
+    case <-t.C:
+        return nil
+    case <-ctx.Done():
+        l.mu.Lock()
+        l.tokens++ // hand the reservation back
+        l.mu.Unlock()
+        return ctx.Err()
+    }
+}
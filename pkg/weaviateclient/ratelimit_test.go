@@ -0,0 +1,80 @@
+package weaviateclient
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "testing"
+    "time"
+)
+
+func TestRateLimitPacesRequests(t *testing.T) {
+    var mu sync.Mutex
+    var hits []time.Time
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mu.Lock()
+        hits = append(hits, time.Now())
+        mu.Unlock()
+        w.Header().Set("Content-Type", "application/json")
+        _, _ = w.Write([]byte(emptyGet))
+    }))
+    t.Cleanup(srv.Close)
+
+    const rps, burst, n = 20, 2, 8
+    cli := NewClient(srv.URL, WithRateLimit(rps, burst))
+    start := time.Now()
+    var wg sync.WaitGroup
+    for range n {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            if _, err := cli.ListCards(context.Background(), 0, 1); err != nil { t.Errorf("ListCards: %v", err) }
+        }()
+    }
+    wg.Wait()
+
+    // The burst goes out at once; each further request waits 1/rps for a token.
+    want := time.Duration(n-burst) * time.Second / rps
+    if elapsed := time.Since(start); elapsed < want-10*time.Millisecond {
+        t.Fatalf("%d requests took %s, want at least %s at %d rps with burst %d", n, elapsed, want, rps, burst)
+    }
+    mu.Lock()
+    defer mu.Unlock()
+    if len(hits) != n { t.Fatalf("server saw %d requests, want %d", len(hits), n) }
+    // After the burst, request i reaches Weaviate no sooner than (i-burst+1)/rps after the first.
+    for i := burst; i < n; i++ {
+        gap := hits[i].Sub(hits[0])
+        earliest := time.Duration(i-burst+1) * time.Second / rps
+        if gap < earliest-10*time.Millisecond {
+            t.Errorf("request %d arrived %s after the first, want at least %s", i, gap, earliest)
+        }
+    }
+}
+
+func TestRateLimitRespectsContext(t *testing.T) {
+    cli, queries := fakeWeaviate(t, fakeRoute{"", emptyGet})
+    cli.limiter = newLimiter(1, 1) // one token now, the next in a second
+
+    if _, err := cli.ListCards(context.Background(), 0, 1); err != nil { t.Fatalf("first call: %v", err) }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+    defer cancel()
+    start := time.Now()
+    _, err := cli.ListCards(ctx, 0, 1)
+    if !errors.Is(err, context.DeadlineExceeded) { t.Fatalf("err = %v, want DeadlineExceeded", err) }
+    if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+        t.Fatalf("limited call blocked %s despite a 50ms deadline", elapsed)
+    }
+    if n := len(queries()); n != 1 { t.Fatalf("Weaviate saw %d queries, want only the first", n) }
+
+    // The abandoned reservation was handed back, so the next token is still about a second away
+    // from the first call rather than two.
+    ctx2, cancel2 := context.WithTimeout(context.Background(), 2*time.Second)
+    defer cancel2()
+    if _, err := cli.ListCards(ctx2, 0, 1); err != nil { t.Fatalf("third call: %v", err) }
+    if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+        t.Fatalf("third call waited %s, want about 1s", elapsed)
+    }
+}
@@ -0,0 +1,246 @@
+package weaviateclient
+
+import (
+    "cmp"
+    "context"
+    "fmt"
+    "math/rand"
+    "sort"
+    "strings"
+)
+
+// Color buckets for SampleSpec.Colors: the five mono colors, multicolored and colorless.
+const (
+    BucketMulticolor = "M"
+    BucketColorless  = "C"
+)
+
+// SampleBuckets lists the color buckets in cube order.
+var SampleBuckets = []string{"W", "U", "B", "R", "G", BucketMulticolor, BucketColorless}
+
+// maxSampleOffset keeps sample windows inside Weaviate's default QUERY_MAXIMUM_RESULTS;
+// larger cells draw from their first 10000 cards.
+const maxSampleOffset = 10000
+
+// SampleSpec describes a pool for SampleCards. Colors maps color buckets (see SampleBuckets)
+// to how many cards each gets; the counts add up to the pool size. Rarities optionally sets
+// how many cards of the pool have each rarity (common, uncommon, rare, mythic); slots beyond
+// the rarity targets take any rarity. Where restricts every card (e.g. LegalIn("vintage")),
+// and Seed makes the draw repeatable (0 draws a fresh one).
+type SampleSpec struct {
+    Colors   map[string]int
+    Rarities map[string]int
+    Where    *WhereFilter
+    Seed     int64
+}
+
+// Size is the pool size the spec asks for.
+func (s SampleSpec) Size() int {
+    n := 0
+    for _, v := range s.Colors { n += v }
+    return n
+}
+
+// Validate checks the buckets and counts. The rarity targets may not exceed the pool size.
+func (s SampleSpec) Validate() error {
+    for b, n := range s.Colors {
+        if !validBucket(b) { return fmt.Errorf("unknown color bucket %q (want one of %s)", b, strings.Join(SampleBuckets, ", ")) }
+        if n < 0 { return fmt.Errorf("color %s: count must not be negative", b) }
+    }
+    rarities := 0
+    for r, n := range s.Rarities {
+        if n < 0 { return fmt.Errorf("rarity %s: count must not be negative", r) }
+        rarities += n
+    }
+    if rarities > s.Size() { return fmt.Errorf("rarity targets add up to %d, more than the pool size of %d", rarities, s.Size()) }
+    return nil
+}
+
+func validBucket(b string) bool {
+    for _, v := range SampleBuckets {
+        if b == v { return true }
+    }
+    return false
+}
+
+// ColorBucket is the SampleSpec bucket a card with these colors falls into.
+func ColorBucket(colors []string) string {
+    switch len(colors) {
+    case 0:
+        return BucketColorless
+    case 1:
+        return strings.ToUpper(colors[0])
+    }
+    return BucketMulticolor
+}
+
+// bucketFilter matches the cards of one color bucket. On text[] properties Equal means
+// "contains" and NotEqual "does not contain" (see WithinIdentity); multicolored cards contain
+// at least one pair of colors.
+func bucketFilter(bucket string) *WhereFilter {
+    wubrg := []string{"W", "U", "B", "R", "G"}
+    var ops []*WhereFilter
+    switch bucket {
+    case BucketColorless:
+        for _, c := range wubrg { ops = append(ops, TextFilter("NotEqual", "colors", c)) }
+        return And(ops...)
+    case BucketMulticolor:
+        for i, a := range wubrg {
+            for _, b := range wubrg[i+1:] { ops = append(ops, TextsFilter("ContainsAll", "colors", []string{a, b})) }
+        }
+        return Or(ops...)
+    }
+    ops = append(ops, TextFilter("Equal", "colors", bucket))
+    for _, c := range wubrg {
+        if c != bucket { ops = append(ops, TextFilter("NotEqual", "colors", c)) }
+    }
+    return And(ops...)
+}
+
+// SampleCards draws a random pool meeting spec's color and rarity targets as far as the
+// collection allows, one printing per name. For each color bucket it reads a random window of
+// candidates per targeted rarity (plus one of any rarity when the rarity targets leave slots
+// free); object IDs are random UUIDs, so a window at a random offset is a random sample. It
+// then fills the buckets round by round, each pick choosing among the rarities still wanted,
+// weighted by how many remain, and taking any rarity once those are met or run out. When a
+// bucket or rarity runs out of candidates the pool comes back short; see SampleSpec.Shortfall.
+// Cards are ordered by bucket, mana value and name.
+func (c *Client) SampleCards(ctx context.Context, spec SampleSpec) ([]Card, error) {
+    if err := spec.Validate(); err != nil { return nil, err }
+    seed := spec.Seed
+    if seed == 0 { seed = rand.Int63() }
+    rng := rand.New(rand.NewSource(seed))
+
+    free := spec.Size() // slots not claimed by a rarity target
+    wantRarity := map[string]int{}
+    for r, n := range spec.Rarities {
+        if n == 0 { continue }
+        wantRarity[strings.ToLower(r)] += n
+        free -= n
+    }
+    const anyRarity = ""
+    // pools[bucket][rarity] are shuffled candidates, consumed from the front.
+    pools := map[string]map[string][]Card{}
+    for _, b := range SampleBuckets {
+        n := spec.Colors[b]
+        if n == 0 { continue }
+        pools[b] = map[string][]Card{}
+        cells := make([]string, 0, len(wantRarity)+1)
+        for r := range wantRarity { cells = append(cells, r) }
+        sort.Strings(cells)
+        if free > 0 { cells = append(cells, anyRarity) }
+        for _, r := range cells {
+            where := And(spec.Where, bucketFilter(b))
+            if r != anyRarity { where = And(where, TextFilter("Equal", "rarity", r)) }
+            cards, err := c.sampleWindow(ctx, rng, where, 2*n)
+            if err != nil { return nil, fmt.Errorf("sample %s %s: %w", b, cmp.Or(r, "any rarity"), err) }
+            pools[b][r] = cards
+        }
+    }
+
+    need := map[string]int{}
+    for b, n := range spec.Colors { need[b] = n }
+    used := map[string]bool{}
+    var out []Card
+    // take pops the next unused card from a pool.
+    take := func(b, r string) (Card, bool) {
+        p := pools[b][r]
+        for len(p) > 0 {
+            card := p[0]
+            p = p[1:]
+            if used[strings.ToLower(card.Name)] { continue }
+            pools[b][r] = p
+            return card, true
+        }
+        pools[b][r] = nil
+        return Card{}, false
+    }
+    for progress := true; progress; {
+        progress = false
+        for _, b := range SampleBuckets {
+            if need[b] == 0 { continue }
+            // Weigh the rarities still wanted by how many remain; an exhausted pool drops out
+            // and the pick is retried among the rest. Free slots come last, so they never
+            // take a card (or name) a rarity target needs.
+            weights := map[string]int{}
+            for r, n := range wantRarity {
+                if n > 0 && len(pools[b][r]) > 0 { weights[r] = n }
+            }
+            for len(weights) > 0 || free > 0 {
+                r := anyRarity
+                if len(weights) > 0 { r = weightedPick(rng, weights) }
+                card, ok := take(b, r)
+                if !ok && r == anyRarity { break }
+                if !ok { delete(weights, r); continue }
+                used[strings.ToLower(card.Name)] = true
+                out = append(out, card)
+                need[b]--
+                if r == anyRarity { free-- } else { wantRarity[r]-- }
+                progress = true
+                break
+            }
+        }
+    }
+    order := map[string]int{}
+    for i, b := range SampleBuckets { order[b] = i }
+    sort.SliceStable(out, func(i, j int) bool {
+        bi, bj := order[ColorBucket(out[i].Colors)], order[ColorBucket(out[j].Colors)]
+        if bi != bj { return bi < bj }
+        if out[i].CMC != out[j].CMC { return out[i].CMC < out[j].CMC }
+        return out[i].Name < out[j].Name
+    })
+    return out, nil
+}
+
+// sampleWindow returns up to n shuffled cards matching where, read from a random offset.
+func (c *Client) sampleWindow(ctx context.Context, rng *rand.Rand, where *WhereFilter, n int) ([]Card, error) {
+    total, err := c.CountWhere(ctx, where)
+    if err != nil || total == 0 { return nil, err }
+    total = min(total, maxSampleOffset)
+    n = min(n, total)
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: where, limit: n, offset: rng.Intn(total - n + 1), fields: listFields}.String()
+    data, err := c.doOptional(ctx, OpList, q, listOptional...)
+    if err != nil { return nil, err }
+    cards, err := decodeCardList(data)
+    if err != nil { return nil, err }
+    rng.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+    return cards, nil
+}
+
+// weightedPick draws a key with probability proportional to its weight. Keys are visited in
+// sorted order so a seeded draw is repeatable.
+func weightedPick(rng *rand.Rand, weights map[string]int) string {
+    keys := make([]string, 0, len(weights))
+    total := 0
+    for k, w := range weights {
+        keys = append(keys, k)
+        total += w
+    }
+    sort.Strings(keys)
+    x := rng.Intn(total)
+    for _, k := range keys {
+        if x < weights[k] { return k }
+        x -= weights[k]
+    }
+    return keys[len(keys)-1]
+}
+
+// Shortfall reports, for a pool drawn by SampleCards, how many cards each color bucket and
+// rarity target is missing; targets that were met are left out.
+func (s SampleSpec) Shortfall(cards []Card) (colors, rarities map[string]int) {
+    colors, rarities = map[string]int{}, map[string]int{}
+    gotColor, gotRarity := map[string]int{}, map[string]int{}
+    for _, c := range cards {
+        gotColor[ColorBucket(c.Colors)]++
+        gotRarity[strings.ToLower(c.Rarity)]++
+    }
+    for b, n := range s.Colors {
+        if n > gotColor[b] { colors[b] = n - gotColor[b] }
+    }
+    want := map[string]int{}
+    for r, n := range s.Rarities { want[strings.ToLower(r)] += n }
+    for r, n := range want {
+        if n > gotRarity[r] { rarities[r] = n - gotRarity[r] }
+    }
+    return colors, rarities
+}
@@ -0,0 +1,107 @@
+package weaviateclient
+
+import (
+    "context"
+    "encoding/json"
+    "hash/fnv"
+)
+
+// scanPageSize is the cursor page size for full-collection scans.
+const scanPageSize = 1000
+
+// scanCards walks every Card object with the cursor API (`after:<id>`), calling fn per page
+// with the raw objects. fields must include `_additional{ id }`. Only one page is held at a time,
+// so callers decide how much of each object to keep.
+func (c *Client) scanCards(ctx context.Context, fields string, fn func(page []json.RawMessage) error) error {
+    after := ""
+    for {
+        q := queryBuilder{consistency: c.consistencyFor(ctx), limit: scanPageSize, after: after, fields: fields}.String()
+        data, err := c.do(ctx, OpList, q)
+        if err != nil { return err }
+        var o struct { Get struct { Card []json.RawMessage `json:"Card"` } `json:"Get"` }
+        if err := json.Unmarshal(data, &o); err != nil { return err }
+        page := o.Get.Card
+        if len(page) == 0 { return nil }
+        if err := fn(page); err != nil { return err }
+        var last struct { Add struct { ID string `json:"id"` } `json:"_additional"` }
+        if err := json.Unmarshal(page[len(page)-1], &last); err != nil { return err }
+        if len(page) < scanPageSize || last.Add.ID == "" { return nil }
+        after = last.Add.ID
+    }
+}
+
+// ExistingScryfallIDs returns the IDs of every ingested Card. Object IDs are the Scryfall IDs
+// (see embed_cards.py), so this is an id-only paged scan.
+func (c *Client) ExistingScryfallIDs(ctx context.Context) (map[string]struct{}, error) {
+    out := map[string]struct{}{}
+    err := c.scanCards(ctx, `_additional{ id }`, func(page []json.RawMessage) error {
+        for _, raw := range page {
+            var o struct { Add struct { ID string `json:"id"` } `json:"_additional"` }
+            if err := json.Unmarshal(raw, &o); err != nil { return err }
+            out[o.Add.ID] = struct{}{}
+        }
+        return nil
+    })
+    return out, err
+}
+
+// presentChunk bounds the IDs per CountPresent query.
+const presentChunk = 500
+
+// CountPresent counts how many of the distinct ids exist as Card objects, e.g. to check that
+// a batch landed. scryfall_id is word-tokenized, so a ContainsAny count would also count cards
+// sharing a UUID segment; it looks the IDs up with an Or of Equal filters and counts only
+// objects whose ID is one of ids.
+func (c *Client) CountPresent(ctx context.Context, ids []string) (int, error) {
+    want := map[string]bool{}
+    var uniq []string
+    for _, id := range ids {
+        if id != "" && !want[id] {
+            want[id] = true
+            uniq = append(uniq, id)
+        }
+    }
+    found := map[string]bool{}
+    for start := 0; start < len(uniq); start += presentChunk {
+        chunk := uniq[start:min(start+presentChunk, len(uniq))]
+        eq := make([]*WhereFilter, 0, len(chunk))
+        for _, id := range chunk { eq = append(eq, TextFilter("Equal", "scryfall_id", id)) }
+        q := queryBuilder{consistency: c.consistencyFor(ctx), where: Or(eq...), limit: 2 * len(chunk), fields: "_additional{ id }"}.String()
+        data, err := c.do(ctx, OpList, q)
+        if err != nil { return 0, err }
+        var o struct { Get struct { Card []struct {
+            Add struct { ID string `json:"id"` } `json:"_additional"`
+        } `json:"Card"` } `json:"Get"` }
+        if err := json.Unmarshal(data, &o); err != nil { return 0, err }
+        for _, card := range o.Get.Card {
+            if want[card.Add.ID] { found[card.Add.ID] = true }
+        }
+    }
+    return len(found), nil
+}
+
+// ExistingOracleHashes maps every ingested Card ID to OracleHash of its stored oracle_text.
+// Keeping a hash rather than the text keeps the footprint to a few bytes per card,
+// which is enough to detect cards whose rules text changed since they were embedded.
+func (c *Client) ExistingOracleHashes(ctx context.Context) (map[string]uint64, error) {
+    out := map[string]uint64{}
+    err := c.scanCards(ctx, `oracle_text _additional{ id }`, func(page []json.RawMessage) error {
+        for _, raw := range page {
+            var o struct {
+                Oracle string `json:"oracle_text"`
+                Add    struct { ID string `json:"id"` } `json:"_additional"`
+            }
+            if err := json.Unmarshal(raw, &o); err != nil { return err }
+            out[o.Add.ID] = OracleHash(o.Oracle)
+        }
+        return nil
+    })
+    return out, err
+}
+
+// OracleHash is the FNV-1a hash used to compare oracle text without retaining it.
+func OracleHash(text string) uint64 {
+    h := fnv.New64a()
+    _, _ = h.Write([]byte(text))
+    return h.Sum64()
+}
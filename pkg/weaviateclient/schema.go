@@ -0,0 +1,95 @@
+package weaviateclient
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "sort"
+    "strings"
+
+    "github.com/domano/decktech/weaviate"
+)
+
+// ClassConfig is the part of a Weaviate class definition that decides how its vectors are made.
+// Model is read from the vectorizer's moduleConfig and is empty when the class has none.
+// Vectors lists the class's named vectors (vectorConfig), sorted; empty for a class with only
+// the unnamed vector.
+type ClassConfig struct {
+    Vectorizer string
+    Model      string
+    Vectors    []string
+}
+
+// ErrClassNotFound is returned by CardClassConfig when the Card class does not exist yet.
+var ErrClassNotFound = errors.New("class Card not found")
+
+// CardClassConfig reads the Card class's vectorizer and model from the REST schema endpoint.
+func (c *Client) CardClassConfig(ctx context.Context) (ClassConfig, error) {
+    ctx, cancel := c.withTimeout(ctx, OpAdmin)
+    defer cancel()
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/schema/Card", nil)
+    if err != nil { return ClassConfig{}, err }
+    resp, err := c.http.Do(req)
+    if err != nil { return ClassConfig{}, err }
+    defer resp.Body.Close()
+    if resp.StatusCode == http.StatusNotFound { return ClassConfig{}, ErrClassNotFound }
+    if resp.StatusCode != http.StatusOK {
+        data, _ := io.ReadAll(resp.Body)
+        return ClassConfig{}, fmt.Errorf("schema status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+    }
+    var class struct {
+        Vectorizer   string                                `json:"vectorizer"`
+        ModuleConfig map[string]map[string]json.RawMessage `json:"moduleConfig"`
+        VectorConfig map[string]json.RawMessage            `json:"vectorConfig"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&class); err != nil { return ClassConfig{}, err }
+    out := ClassConfig{Vectorizer: class.Vectorizer}
+    for name := range class.VectorConfig { out.Vectors = append(out.Vectors, name) }
+    sort.Strings(out.Vectors)
+    for _, key := range []string{"model", "modelName", "modelId"} {
+        var s string
+        if raw, ok := class.ModuleConfig[class.Vectorizer][key]; ok && json.Unmarshal(raw, &s) == nil && s != "" {
+            out.Model = s
+            break
+        }
+    }
+    return out, nil
+}
+
+// EnsureCardClass creates the Card class from weaviate/schema.json unless it already exists, and
+// reports whether it did. An existing class is left alone even when it differs from the file.
+func (c *Client) EnsureCardClass(ctx context.Context) (bool, error) {
+    _, err := c.CardClassConfig(ctx)
+    if err == nil { return false, nil }
+    if !errors.Is(err, ErrClassNotFound) { return false, err }
+    class, err := cardClassDefinition()
+    if err != nil { return false, err }
+    ctx, cancel := c.withTimeout(ctx, OpAdmin)
+    defer cancel()
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/schema/classes", bytes.NewReader(class))
+    if err != nil { return false, err }
+    req.Header.Set("Content-Type", "application/json")
+    resp, err := c.http.Do(req)
+    if err != nil { return false, err }
+    defer resp.Body.Close()
+    if resp.StatusCode/100 != 2 {
+        data, _ := io.ReadAll(resp.Body)
+        return false, fmt.Errorf("create class Card: status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+    }
+    return true, nil
+}
+
+// cardClassDefinition picks the Card class out of the embedded schema file.
+func cardClassDefinition() (json.RawMessage, error) {
+    var s struct { Classes []json.RawMessage `json:"classes"` }
+    if err := json.Unmarshal(weaviate.Schema, &s); err != nil { return nil, fmt.Errorf("parse schema.json: %w", err) }
+    for _, raw := range s.Classes {
+        var cl struct { Class string `json:"class"` }
+        if json.Unmarshal(raw, &cl) == nil && cl.Class == "Card" { return raw, nil }
+    }
+    return nil, errors.New("schema.json defines no Card class")
+}
@@ -0,0 +1,74 @@
+package weaviateclient
+
+import (
+    "context"
+    "fmt"
+)
+
+type efKey struct{}
+
+// WithEf asks nearVector searches made with the returned context to explore at least ef HNSW
+// candidates, trading latency for recall. Weaviate has no per-query ef argument, but its HNSW
+// search uses max(ef, limit), so the query limit is raised to ef and the results are trimmed
+// back to k. ef <= k (or unset) leaves the class's configured (or dynamic) ef in charge.
+func WithEf(ctx context.Context, ef int) context.Context {
+    return context.WithValue(ctx, efKey{}, ef)
+}
+
+// searchLimit is the GraphQL limit for a top-k nearVector search under ctx.
+func searchLimit(ctx context.Context, k int) int {
+    if ef, ok := ctx.Value(efKey{}).(int); ok && ef > k { return ef }
+    return k
+}
+
+type autocutKey struct{}
+
+// WithAutocut makes nearVector searches made with the returned context stop after n jumps in
+// the distance distribution (Weaviate's autocut), so k becomes an upper bound and weakly related
+// tail results are dropped. n < 1 disables it.
+func WithAutocut(ctx context.Context, n int) context.Context {
+    return context.WithValue(ctx, autocutKey{}, n)
+}
+
+// autocutOf returns the autocut ctx asks for, or 0 when it sets none.
+func autocutOf(ctx context.Context) int {
+    if n, ok := ctx.Value(autocutKey{}).(int); ok && n >= 1 { return n }
+    return 0
+}
+
+type sortKey struct{}
+
+type sortSpec struct {
+    path string
+    desc bool
+}
+
+// WithSort makes list and search queries made with the returned context (ListCards,
+// ListCardsFiltered, FindByNameLike, SearchCards, FindByKeywords) come back ordered by a
+// sortable Card property such as "name", "cmc" or "edhrec_rank", ties broken by name in the
+// same direction. Weaviate sorts before applying the limit, so a truncated result is the true
+// top of the order. An empty path disables it.
+func WithSort(ctx context.Context, path string, desc bool) context.Context {
+    return context.WithValue(ctx, sortKey{}, sortSpec{path: path, desc: desc})
+}
+
+// sortOf returns the sort ctx asks for; the zero sortSpec means none.
+func sortOf(ctx context.Context) sortSpec {
+    s, _ := ctx.Value(sortKey{}).(sortSpec)
+    return s
+}
+
+// String renders the sort as a GraphQL list, or "" for no sort.
+func (s sortSpec) String() string {
+    if s.path == "" { return "" }
+    order := "asc"
+    if s.desc { order = "desc" }
+    if s.path == "name" { return fmt.Sprintf(`[{path:["name"], order:%s}]`, order) }
+    return fmt.Sprintf(`[{path:[%s], order:%s}, {path:["name"], order:%s}]`, gqlString(s.path), order, order)
+}
+
+// sortArg renders `sort:[...], ` for a Get argument list, or "" when ctx sets no sort.
+func sortArg(ctx context.Context) string {
+    if s := sortOf(ctx).String(); s != "" { return "sort:" + s + ", " }
+    return ""
+}
@@ -0,0 +1,30 @@
+package weaviateclient
+
+import (
+    "context"
+)
+
+// stapleFields is listFields plus the rank Staples sorts by.
+const stapleFields = `scryfall_id name type_line mana_cost cmc colors set rarity oracle_text image_normal prices edhrec_rank _additional{ id }`
+
+// Staples returns up to limit of the most played cards legal in format (e.g. "modern"), by
+// ascending EDHREC rank, one printing per name. Unranked cards (no edhrec_rank) are left out,
+// so an unknown format, or a collection ingested without legalities, yields an empty slice.
+func (c *Client) Staples(ctx context.Context, format string, limit int) ([]Card, error) {
+    where := And(LegalIn(format), IntFilter("GreaterThan", "edhrec_rank", 0))
+    // Over-fetch: several printings of a staple share its rank and collapse into one.
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: where, sort: sortSpec{path: "edhrec_rank"}, limit: 3 * limit, fields: stapleFields}.String()
+    data, err := c.doOptional(ctx, OpList, q, propPrices)
+    if err != nil { return nil, err }
+    cards, err := decodeCardList(data)
+    if err != nil { return nil, err }
+    out := []Card{}
+    seen := map[string]bool{}
+    for _, c0 := range cards {
+        if seen[c0.Name] { continue }
+        seen[c0.Name] = true
+        out = append(out, c0)
+        if len(out) == limit { break }
+    }
+    return out, nil
+}
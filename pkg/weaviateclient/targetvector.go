@@ -0,0 +1,72 @@
+package weaviateclient
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "regexp"
+    "slices"
+    "strings"
+)
+
+type targetVectorKey struct{}
+
+// WithTargetVector makes vector lookups (FetchVectorForName, FetchVectorByScryfallID) and
+// nearVector searches made with the returned context use the named vector target instead of
+// the class's unnamed one, for collections with several named vectors (e.g. one embedding of
+// the rules text and one of the name). An empty name keeps the unnamed vector.
+func WithTargetVector(ctx context.Context, name string) context.Context {
+    return context.WithValue(ctx, targetVectorKey{}, name)
+}
+
+// targetVectorOf returns the named vector ctx asks for, or "" for the unnamed one.
+func targetVectorOf(ctx context.Context) string {
+    s, _ := ctx.Value(targetVectorKey{}).(string)
+    return s
+}
+
+// ErrUnknownTargetVector is returned (wrapped with the name) when WithTargetVector names a
+// vector the Card class does not configure. Test with errors.Is.
+var ErrUnknownTargetVector = errors.New("unknown target vector")
+
+// vectorName matches what Weaviate accepts as a named vector, which is also what can be
+// spliced into a selection set unquoted.
+var vectorName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// checkTargetVector rejects a malformed target and, when the schema can be read, one the Card
+// class does not configure. Confirmed names are remembered per client.
+func (c *Client) checkTargetVector(ctx context.Context, target string) error {
+    if target == "" { return nil }
+    if !vectorName.MatchString(target) { return fmt.Errorf("%w: %q is not a valid vector name", ErrUnknownTargetVector, target) }
+    if _, ok := c.targets.Load(target); ok { return nil }
+    cfg, err := c.CardClassConfig(ctx)
+    if err != nil { return nil } // no schema to check against; let the query fail if it must
+    if !slices.Contains(cfg.Vectors, target) {
+        configured := "none"
+        if len(cfg.Vectors) > 0 { configured = strings.Join(cfg.Vectors, ", ") }
+        return fmt.Errorf("%w: %s (configured: %s)", ErrUnknownTargetVector, target, configured)
+    }
+    c.targets.Store(target, struct{}{})
+    return nil
+}
+
+// vectorSelection is the `_additional` field selecting the target vector.
+func vectorSelection(target string) string {
+    if target == "" { return "vector" }
+    return "vectors { " + target + " }"
+}
+
+// additionalVector decodes an `_additional` block selected with vectorSelection.
+type additionalVector struct {
+    ID       string               `json:"id"`
+    Distance float64              `json:"distance"`
+    Score    string               `json:"score"` // bm25 only; Weaviate returns it as a string
+    Vector   []float64            `json:"vector"`
+    Vectors  map[string][]float64 `json:"vectors"`
+}
+
+// of returns the target vector, nil when the object has none.
+func (a additionalVector) of(target string) []float64 {
+    if target == "" { return a.Vector }
+    return a.Vectors[target]
+}
@@ -0,0 +1,32 @@
+package weaviateclient
+
+import (
+    "context"
+    "strings"
+)
+
+// bm25Properties are the text properties SearchBM25 ranks by.
+var bm25Properties = []string{"name", "type_line", "oracle_text"}
+
+// SearchNearText returns the k cards nearest to text as embedded by the Card class's own
+// vectorizer module (nearText). It fails on classes with vectorizer "none", which is how
+// DeckTech ingests by default; check CardClassConfig first. WithTargetVector, WithEf and
+// WithAutocut apply as for SearchNearVector.
+func (c *Client) SearchNearText(ctx context.Context, text string, k int, where *WhereFilter) ([]Card, error) {
+    text = strings.TrimSpace(text)
+    if text == "" { return nil, nil }
+    target := targetVectorOf(ctx)
+    if err := c.checkTargetVector(ctx, target); err != nil { return nil, err }
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: where, nearText: text, targetVector: target, autocut: autocutOf(ctx), limit: searchLimit(ctx, k)}
+    return c.runSearch(ctx, q, "id distance", target, k)
+}
+
+// SearchBM25 ranks cards by keyword relevance (bm25) of query against their name, type line
+// and oracle text, best first, with Card.Score set. It needs no vectors, so it works on any
+// collection with the default inverted index.
+func (c *Client) SearchBM25(ctx context.Context, query string, k int, where *WhereFilter) ([]Card, error) {
+    query = strings.TrimSpace(query)
+    if query == "" { return nil, nil }
+    q := queryBuilder{consistency: c.consistencyFor(ctx), where: where, bm25: query, autocut: autocutOf(ctx), limit: k}
+    return c.runSearch(ctx, q, "id score", "", k)
+}
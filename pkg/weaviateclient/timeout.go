@@ -0,0 +1,82 @@
+package weaviateclient
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log/slog"
+    "os"
+    "strings"
+    "time"
+)
+
+// Operation groups client calls that share a timeout (see WithTimeout).
+type Operation string
+
+const (
+    OpList      Operation = "list"      // Get queries: browsing, lookups by name or ID, text and keyword search
+    OpSearch    Operation = "search"    // nearVector similarity searches
+    OpAggregate Operation = "aggregate" // counts and groupBy statistics
+    OpAdmin     Operation = "admin"     // REST calls: readiness, schema, owned flags
+    OpDelete    Operation = "delete"    // one batch delete chunk
+)
+
+// Operations lists every Operation, e.g. for reporting the configured timeouts.
+var Operations = []Operation{OpList, OpSearch, OpAggregate, OpAdmin, OpDelete}
+
+// defaultTimeouts bound each request when no WithTimeout overrides them. A batch delete
+// removes up to QUERY_MAXIMUM_RESULTS (10000 by default) objects per call, which takes far
+// longer than a query.
+var defaultTimeouts = map[Operation]time.Duration{
+    OpList:      15 * time.Second,
+    OpSearch:    15 * time.Second,
+    OpAggregate: 15 * time.Second,
+    OpAdmin:     15 * time.Second,
+    OpDelete:    5 * time.Minute,
+}
+
+// WithTimeout bounds each Weaviate request of kind op, e.g. 5s for browsing but 30s for
+// nearVector searches with a high ef. The caller's context deadline still applies, so a request
+// gets whichever is sooner; d <= 0 leaves op bounded by the context alone. The timeout covers
+// one HTTP request, not the wait for a rate limit token or the retries of a multi-request call.
+func WithTimeout(op Operation, d time.Duration) Option {
+    return func(c *Client) { c.timeouts[op] = d }
+}
+
+// TimeoutsFromEnv builds WithTimeout options from WEAVIATE_TIMEOUT_LIST, _SEARCH, _AGGREGATE,
+// _ADMIN and _DELETE (Go durations, e.g. 5s; 0 disables the client's own limit). Unset or
+// invalid values keep the defaults.
+func TimeoutsFromEnv() Option {
+    var opts []Option
+    for _, op := range Operations {
+        key := "WEAVIATE_TIMEOUT_" + strings.ToUpper(string(op))
+        v := strings.TrimSpace(os.Getenv(key))
+        if v == "" { continue }
+        d, err := time.ParseDuration(v)
+        if err != nil || d < 0 {
+            slog.Warn("ignoring invalid env value", "key", key, "value", v)
+            continue
+        }
+        slog.Info("weaviate timeout", "op", op, "timeout", d)
+        opts = append(opts, WithTimeout(op, d))
+    }
+    return func(c *Client) { for _, o := range opts { o(c) } }
+}
+
+// Timeout reports the client's own limit for op (0 = none).
+func (c *Client) Timeout(op Operation) time.Duration { return c.timeouts[op] }
+
+// withTimeout derives the context for one request of kind op; callers must call the returned
+// cancel once the response body has been read.
+func (c *Client) withTimeout(ctx context.Context, op Operation) (context.Context, context.CancelFunc) {
+    d := c.timeouts[op]
+    if d <= 0 { return context.WithCancel(ctx) }
+    return context.WithTimeout(ctx, d)
+}
+
+// timeoutErr names the operation's timeout when it, rather than the caller's context, ended a
+// request; other errors pass through.
+func timeoutErr(parent context.Context, op Operation, d time.Duration, err error) error {
+    if err == nil || parent.Err() != nil || !errors.Is(err, context.DeadlineExceeded) { return err }
+    return fmt.Errorf("weaviate %s request timed out after %s: %w", op, d, err)
+}
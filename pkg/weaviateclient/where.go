@@ -0,0 +1,136 @@
+package weaviateclient
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+)
+
+// WhereFilter is a Weaviate GraphQL `where` operand: either a leaf comparing a
+// property path against a value, or an And/Or node over Operands.
+// Build leaves with the constructors below; String renders GraphQL.
+type WhereFilter struct {
+    Operator string
+    Path     []string
+    Value    string // rendered value argument, e.g. valueText:"mh3"
+    Operands []*WhereFilter
+}
+
+// TextFilter compares a text property, e.g. TextFilter("Equal", "set", "mh3").
+func TextFilter(op, path, value string) *WhereFilter {
+    return &WhereFilter{Operator: op, Path: []string{path}, Value: "valueText:" + gqlString(value)}
+}
+
+// TextsFilter compares against several values, for ContainsAny/ContainsAll on text[] properties.
+func TextsFilter(op, path string, values []string) *WhereFilter {
+    vb, _ := json.Marshal(values)
+    return &WhereFilter{Operator: op, Path: []string{path}, Value: "valueText:" + string(vb)}
+}
+
+// NumberFilter compares a number property, e.g. NumberFilter("LessThanEqual", "cmc", 3).
+func NumberFilter(op, path string, value float64) *WhereFilter {
+    return &WhereFilter{Operator: op, Path: []string{path}, Value: fmt.Sprintf("valueNumber:%g", value)}
+}
+
+// IntFilter compares an int property, e.g. IntFilter("LessThan", "edhrec_rank", 1000).
+func IntFilter(op, path string, value int) *WhereFilter {
+    return &WhereFilter{Operator: op, Path: []string{path}, Value: fmt.Sprintf("valueInt:%d", value)}
+}
+
+// BoolFilter compares a boolean property.
+func BoolFilter(op, path string, value bool) *WhereFilter {
+    return &WhereFilter{Operator: op, Path: []string{path}, Value: fmt.Sprintf("valueBoolean:%t", value)}
+}
+
+// And combines filters, skipping nils. It returns nil for no filters and the filter itself for one.
+func And(filters ...*WhereFilter) *WhereFilter { return group("And", filters) }
+
+// Or is the disjunction counterpart of And.
+func Or(filters ...*WhereFilter) *WhereFilter { return group("Or", filters) }
+
+func group(op string, filters []*WhereFilter) *WhereFilter {
+    var ops []*WhereFilter
+    for _, f := range filters {
+        if f != nil { ops = append(ops, f) }
+    }
+    switch len(ops) {
+    case 0:
+        return nil
+    case 1:
+        return ops[0]
+    }
+    return &WhereFilter{Operator: op, Operands: ops}
+}
+
+// String renders the filter as a GraphQL input object.
+func (w *WhereFilter) String() string {
+    if w == nil { return "" }
+    if len(w.Operands) > 0 {
+        parts := make([]string, 0, len(w.Operands))
+        for _, o := range w.Operands { parts = append(parts, o.String()) }
+        return fmt.Sprintf(`{operator: %s, operands:[%s]}`, w.Operator, strings.Join(parts, ", "))
+    }
+    pb, _ := json.Marshal(w.Path)
+    return fmt.Sprintf(`{path:%s, operator: %s, %s}`, string(pb), w.Operator, w.Value)
+}
+
+// gqlString quotes s as a GraphQL string literal. Unlike %q it never emits Go-only escapes
+// (\x00, \a, \U0001F600) and replaces invalid UTF-8, so user input cannot break the query.
+func gqlString(s string) string {
+    b, _ := json.Marshal(s)
+    return string(b)
+}
+
+// likeContains is a Like pattern matching s anywhere in the value. Weaviate's Like has no
+// escape character, so a * or ? in s stays a wildcard there; callers keep only the results
+// that contain s literally (see literalMatch), fetching likeLimit rows to make up for them.
+func likeContains(s string) string { return "*" + s + "*" }
+
+// wildcardOverfetch is how many more rows a Like query fetches when its input has wildcards,
+// since literalMatch drops the hits that only matched them as wildcards.
+const wildcardOverfetch = 4
+
+// hasWildcard reports whether s contains a Like wildcard.
+func hasWildcard(s string) bool { return strings.ContainsAny(s, "*?") }
+
+// likeLimit is the row limit for a likeContains(s) query that should yield limit results.
+func likeLimit(s string, limit int) int {
+    if hasWildcard(s) { return limit * wildcardOverfetch }
+    return limit
+}
+
+// literalMatch reports whether one of values contains s, ignoring case: how a likeContains(s)
+// hit is checked when s has wildcards. Without wildcards the pattern was already literal.
+func literalMatch(s string, values ...string) bool {
+    if !hasWildcard(s) { return true }
+    ls := strings.ToLower(s)
+    for _, v := range values {
+        if strings.Contains(strings.ToLower(v), ls) { return true }
+    }
+    return false
+}
+
+// LegalIn matches cards legal (or restricted) in a format, e.g. "modern", using the
+// legal_formats text[] property written at ingest.
+func LegalIn(format string) *WhereFilter {
+    return TextFilter("Equal", "legal_formats", strings.ToLower(strings.TrimSpace(format)))
+}
+
+// WithinIdentity keeps cards whose color identity fits within identity (e.g. a commander's),
+// by excluding every WUBRG color outside it. On text[] properties NotEqual means "does not
+// contain". A five-color identity needs no filter and yields nil.
+func WithinIdentity(identity []string) *WhereFilter {
+    in := map[string]bool{}
+    for _, c := range identity { in[strings.ToUpper(strings.TrimSpace(c))] = true }
+    var ops []*WhereFilter
+    for _, c := range []string{"W", "U", "B", "R", "G"} {
+        if !in[c] { ops = append(ops, TextFilter("NotEqual", "color_identity", c)) }
+    }
+    return And(ops...)
+}
+
+// CommanderEligible keeps legendary creatures legal in Commander. Like matches anywhere in the
+// type line, so callers should still check the front face of double-faced cards.
+func CommanderEligible() *WhereFilter {
+    return And(TextFilter("Like", "type_line", "*Legendary*"), TextFilter("Like", "type_line", "*Creature*"), LegalIn("commander"))
+}
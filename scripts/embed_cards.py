@@ -17,6 +17,7 @@ Notes:
 """
 
 import argparse
+import gzip
 import json
 import math
 import os
@@ -153,9 +154,33 @@ def extract_props(card: Dict[str, Any]) -> Dict[str, Any]:
 
     legalities = card.get("legalities")
     legalities_str = json.dumps(legalities, separators=(",", ":")) if legalities else ""
+    legal_formats = sorted(f for f, st in (legalities or {}).items() if st in ("legal", "restricted"))
+
+    # Multi-face cards (transform, modal_dfc, split, ...): keep per-face text and image as a JSON string
+    faces = []
+    for f in card.get("card_faces") or []:
+        fiu = f.get("image_uris") or {}
+        faces.append({
+            "name": f.get("name") or "",
+            "mana_cost": f.get("mana_cost") or "",
+            "type_line": f.get("type_line") or "",
+            "oracle_text": f.get("oracle_text") or "",
+            "power": f.get("power") or "",
+            "toughness": f.get("toughness") or "",
+            "image_normal": fiu.get("normal") or "",
+        })
+    faces_str = json.dumps(faces, separators=(",", ":")) if len(faces) > 1 else ""
+
+    # Prices change daily; stored as a JSON string (null entries kept) so new price keys need no schema change
+    prices = card.get("prices")
+    prices_str = json.dumps(prices, separators=(",", ":")) if prices else ""
+
+    # Reversible cards carry oracle_id per face only
+    oracle_id = card.get("oracle_id") or next((f.get("oracle_id") for f in card.get("card_faces") or [] if f.get("oracle_id")), "")
 
     return {
         "scryfall_id": card.get("id"),
+        "oracle_id": oracle_id,
         "name": card.get("name"),
         "mana_cost": card.get("mana_cost") or "",
         "cmc": float(card.get("cmc")) if card.get("cmc") is not None else None,
@@ -173,7 +198,11 @@ def extract_props(card: Dict[str, Any]) -> Dict[str, Any]:
         "layout": card.get("layout") or "",
         "image_small": get_image(card, "small"),
         "image_normal": get_image(card, "normal"),
+        "image_large": get_image(card, "large"),
         "legalities": legalities_str,
+        "legal_formats": legal_formats,
+        "card_faces": faces_str,
+        "prices": prices_str,
     }
 
 
@@ -266,8 +295,11 @@ def main():
         def tqdm(x, **kwargs):  # type: ignore
             return x
 
-    # Load Scryfall bulk JSON (list of card dicts)
-    with open(args.scryfall_json, "r", encoding="utf-8") as f:
+    # Load Scryfall bulk JSON (list of card dicts), gunzipping it when it starts with the gzip magic
+    with open(args.scryfall_json, "rb") as raw:
+        gzipped = raw.read(2) == b"\x1f\x8b"
+    opener = gzip.open if gzipped else open
+    with opener(args.scryfall_json, "rt", encoding="utf-8") as f:
         cards = json.load(f)
 
     # Resolve offset via checkpoint if provided
@@ -355,6 +387,15 @@ def main():
             "include_name": bool(args.include_name),
             "kind": kind,
         }
+        # Keep the batch verification record written by the decktech runner.
+        try:
+            with open(args.checkpoint, "r", encoding="utf-8") as cf:
+                prev = json.load(cf)
+            for key in ("batch_counts", "batch_mismatches"):
+                if prev.get(key):
+                    state[key] = prev[key]
+        except Exception:
+            pass
         try:
             with open(args.checkpoint, "w", encoding="utf-8") as cf:
                 json.dump(state, cf)
@@ -8,10 +8,15 @@ fi
 
 BATCH_FILE=$1
 WEAVIATE_URL=${2:-${WEAVIATE_URL:-http://localhost:8080}}
+# Optional write consistency (ONE/QUORUM/ALL) for replicated classes.
+QUERY=""
+if [ -n "${CONSISTENCY_LEVEL:-}" ]; then
+  QUERY="?consistency_level=${CONSISTENCY_LEVEL}"
+fi
 
 echo "Ingesting batch to ${WEAVIATE_URL} ..."
 OUT=$(curl -sS -H 'Content-Type: application/json' \
-  -X POST "${WEAVIATE_URL}/v1/batch/objects" \
+  -X POST "${WEAVIATE_URL}/v1/batch/objects${QUERY}" \
   --data-binary @"${BATCH_FILE}" -w '\nHTTP_STATUS:%{http_code}')
 BODY=${OUT%HTTP_STATUS:*}
 CODE=${OUT##*HTTP_STATUS:}
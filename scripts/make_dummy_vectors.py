@@ -37,9 +37,33 @@ def extract_props(card: Dict[str, Any]) -> Dict[str, Any]:
 
     legalities = card.get("legalities")
     legalities_str = json.dumps(legalities, separators=(",", ":")) if legalities else ""
+    legal_formats = sorted(f for f, st in (legalities or {}).items() if st in ("legal", "restricted"))
+
+    # Multi-face cards (transform, modal_dfc, split, ...): keep per-face text and image as a JSON string
+    faces = []
+    for f in card.get("card_faces") or []:
+        fiu = f.get("image_uris") or {}
+        faces.append({
+            "name": f.get("name") or "",
+            "mana_cost": f.get("mana_cost") or "",
+            "type_line": f.get("type_line") or "",
+            "oracle_text": f.get("oracle_text") or "",
+            "power": f.get("power") or "",
+            "toughness": f.get("toughness") or "",
+            "image_normal": fiu.get("normal") or "",
+        })
+    faces_str = json.dumps(faces, separators=(",", ":")) if len(faces) > 1 else ""
+
+    # Prices change daily; stored as a JSON string (null entries kept) so new price keys need no schema change
+    prices = card.get("prices")
+    prices_str = json.dumps(prices, separators=(",", ":")) if prices else ""
+
+    # Reversible cards carry oracle_id per face only
+    oracle_id = card.get("oracle_id") or next((f.get("oracle_id") for f in card.get("card_faces") or [] if f.get("oracle_id")), "")
 
     return {
         "scryfall_id": card.get("id"),
+        "oracle_id": oracle_id,
         "name": card.get("name"),
         "mana_cost": card.get("mana_cost") or "",
         "cmc": float(card.get("cmc")) if card.get("cmc") is not None else None,
@@ -57,7 +81,11 @@ def extract_props(card: Dict[str, Any]) -> Dict[str, Any]:
         "layout": card.get("layout") or "",
         "image_small": get_image(card, "small"),
         "image_normal": get_image(card, "normal"),
+        "image_large": get_image(card, "large"),
         "legalities": legalities_str,
+        "legal_formats": legal_formats,
+        "card_faces": faces_str,
+        "prices": prices_str,
     }
 
 
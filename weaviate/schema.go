@@ -0,0 +1,10 @@
+// Package weaviate embeds the Weaviate schema (schema.json), so Go code creates the Card class
+// from the same definition scripts/apply_schema.sh posts.
+package weaviate
+
+import _ "embed"
+
+// Schema is schema.json: {"classes": [...]} with the Card class definition.
+//
+//go:embed schema.json
+var Schema []byte